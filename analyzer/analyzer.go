@@ -0,0 +1,564 @@
+// Package analyzer runs the static analysis of an Echo application and
+// returns its results, so the analyzer can be embedded in other tools. The
+// command line tool is a thin wrapper around Analyze.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	handleranalyzer "github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/aws"
+	"github.com/user/golang-echo-analyzer/internal/logger"
+	"github.com/user/golang-echo-analyzer/internal/parser"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// Frameworks routes can be registered with
+const (
+	// FrameworkEcho is the Echo web framework
+	FrameworkEcho = "echo"
+
+	// FrameworkNetHTTP is net/http's ServeMux, with the method and path
+	// patterns of Go 1.22, as in mux.HandleFunc("GET /users/{id}", getUser)
+	FrameworkNetHTTP = "nethttp"
+)
+
+// Options configures an analysis
+type Options struct {
+	RepoPath  string // Root of the repository to analyze
+	Framework string // Web framework routes are registered with (default: echo)
+	Verbose   bool   // Write debug messages of every analysis step to Log
+
+	ExcludeDirs  []string // Directory globs skipped while parsing
+	IncludePaths []string // Only analyze routes whose path matches one of these globs
+	ExcludePaths []string // Skip routes whose path matches one of these globs
+
+	// Packages restricts the analysis to the packages with these import
+	// paths or directories, relative to RepoPath, and Files to these files.
+	// Only the routes registered in them and their AWS events are documented;
+	// routes are still scanned across the repository, so group prefixes
+	// applied elsewhere are kept.
+	Packages []string
+	Files    []string
+
+	// RegistrarMethods are custom route registration methods, as
+	// Name:methodArg:pathArg:handlerArg
+	RegistrarMethods []string
+
+	// DetectTimeouts notes context timeouts handlers apply to the request context
+	DetectTimeouts bool
+
+	// DefaultQueryHelper is the name of a helper reading a query parameter
+	// with a default value, called as helper(c, "name", "default")
+	DefaultQueryHelper string
+
+	// PathParamConvention checks path parameter names against a naming
+	// convention or regular expression, if set
+	PathParamConvention string
+
+	// JSONTagKeys are the struct tag keys naming fields in JSON, in
+	// priority order; if empty, only json is consulted
+	JSONTagKeys []string
+
+	// Envelope is the response wrapper type as Type.Field, e.g.
+	// Envelope.Data; the field is documented with each response's payload
+	Envelope string
+
+	// Detectors match custom response and request helpers, such as
+	// respondJSON(c, 200, data), in addition to the echo.Context methods
+	Detectors *Detectors
+
+	// Strict fails the analysis on routing errors, such as a method and
+	// path registered twice, instead of reporting them as warnings
+	Strict bool
+
+	// Cache reads and writes the parse cache in the repository root
+	Cache bool
+
+	// FailOnParseError stops the analysis if any file can't be parsed,
+	// instead of skipping the file with a warning
+	FailOnParseError bool
+
+	// Log receives progress messages, and debug messages if Verbose is set;
+	// nil discards them
+	Log io.Writer
+
+	// Progress, if set, is called as files are parsed, packages are
+	// collected and handlers are analyzed, with the name of the step, the
+	// number of items done and their total, or 0 if it isn't known yet
+	Progress func(step string, done, total int)
+}
+
+// APIDocument holds the results of analyzing a repository
+type APIDocument struct {
+	RepoRoot      string
+	Routes        []scanner.RouteInfo
+	Handlers      map[string]*handleranalyzer.HandlerInfo
+	ErrorHandler  *handleranalyzer.HandlerInfo // HTTP error handler, if one is assigned
+	Events        []aws.EventInfo
+	Middleware    []string // Global middleware
+	ResponseTypes map[string]*types.ResponseInfo
+	RequestTypes  map[string]*types.TypeDefinition // Request body types, by handler name
+	TypeRegistry  *types.TypeRegistry
+
+	// Calls maps each function to the functions of the repository it calls,
+	// by name
+	Calls map[string][]string
+
+	// PathParamDiagnostics lists the path parameters not following
+	// Options.PathParamConvention
+	PathParamDiagnostics []scanner.PathParamDiagnostic
+
+	// Warnings lists the errors that didn't stop the analysis
+	Warnings []string
+
+	// Diagnostics lists the same errors as Warnings, with their kind and
+	// position when known
+	Diagnostics []Diagnostic
+}
+
+// Analyze parses a repository, resolves its types, and analyzes its routes,
+// handlers, responses and AWS events. It doesn't write any file, except for
+// the parse cache if Options.Cache is set.
+func Analyze(opts Options) (*APIDocument, error) {
+	if opts.Framework != "" && opts.Framework != FrameworkEcho && opts.Framework != FrameworkNetHTTP {
+		return nil, fmt.Errorf("unsupported framework: %s", opts.Framework)
+	}
+	netHTTP := opts.Framework == FrameworkNetHTTP
+
+	repoRoot, err := filepath.Abs(opts.RepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving repository path: %v", err)
+	}
+
+	w := opts.Log
+	if w == nil {
+		w = io.Discard
+	}
+	level := logger.LevelInfo
+	if opts.Verbose {
+		level = logger.LevelDebug
+	}
+	log := logger.New(w, w, level)
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, int, int) {}
+	}
+
+	doc := &APIDocument{
+		RepoRoot:    repoRoot,
+		Warnings:    []string{},
+		Diagnostics: []Diagnostic{},
+	}
+
+	var envelope *types.Envelope
+	if opts.Envelope != "" {
+		if envelope, err = types.ParseEnvelope(opts.Envelope); err != nil {
+			return nil, fmt.Errorf("error parsing response envelope: %v", err)
+		}
+	}
+
+	// 1. Parse Go source files
+	log.Infof("Step 1: Parsing Go source files...")
+	codeParser := parser.NewCodeParser(repoRoot, log)
+	if err := codeParser.SetExcludeDirs(opts.ExcludeDirs); err != nil {
+		return nil, fmt.Errorf("error parsing exclude directories: %v", err)
+	}
+	scope, err := newScope(repoRoot, opts.Packages, opts.Files)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil {
+		codeParser.Scope = scope.parseDirs()
+	}
+	if opts.Cache {
+		codeParser.SetCache(parser.LoadParseCache(filepath.Join(repoRoot, parser.CacheFileName), log))
+	}
+	codeParser.Progress = func(files int) { progress("Parsing files", files, 0) }
+	if err := codeParser.Parse(); err != nil {
+		return nil, fmt.Errorf("error parsing repository: %v", err)
+	}
+	if parseErrors := codeParser.Errors(); len(parseErrors) > 0 {
+		if opts.FailOnParseError {
+			if len(parseErrors) > 1 {
+				return nil, fmt.Errorf("error parsing repository: %v (and %d other files)", parseErrors[0], len(parseErrors)-1)
+			}
+			return nil, fmt.Errorf("error parsing repository: %v", parseErrors[0])
+		}
+		for _, err := range parseErrors {
+			doc.warn(DiagnosticParseError, parseErrorPosition(err), fmt.Sprintf("skipped file: %v", err))
+		}
+	}
+	if err := codeParser.SaveCache(); err != nil {
+		doc.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("error saving parse cache: %v", err))
+	}
+	if skipped := len(codeParser.Errors()); skipped > 0 {
+		log.Infof("  Parsing completed. Files that could not be parsed were skipped: %d", skipped)
+	} else {
+		log.Infof("  Parsing completed successfully.")
+	}
+
+	// 2. Initialize type registry and collector
+	log.Infof("Step 2: Initializing type resolution system...")
+	typeRegistry := types.NewTypeRegistry(codeParser.FileSet, log)
+	typeRegistry.JSONTagKeys = opts.JSONTagKeys
+	typeCollector := types.NewTypeCollector(typeRegistry, log)
+
+	// Collect types from all packages, or only the selected ones and the
+	// packages they import
+	scopePackages := codeParser.ScopePackages()
+	collected := 0
+	for _, pkgPath := range codeParser.PackagePaths() {
+		collected++
+		progress("Collecting types", collected, len(codeParser.Packages))
+		if scopePackages != nil && !scopePackages[pkgPath] {
+			continue
+		}
+		files := parser.PackageFiles(codeParser.Packages[pkgPath])
+		if err := typeCollector.CollectTypes(files, pkgPath); err != nil {
+			doc.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("error collecting types from package %s: %v", pkgPath, err))
+		}
+	}
+
+	// Resolve types
+	if err := typeCollector.ResolveTypes(); err != nil {
+		doc.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("error resolving types: %v", err))
+	}
+
+	// 3. Initialize package resolver
+	packageResolver := types.NewPackageResolver(typeRegistry, repoRoot, log)
+	if err := packageResolver.ResolvePackages(); err != nil {
+		doc.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("error resolving packages: %v", err))
+	}
+
+	// 4. Initialize struct field analyzer
+	fieldAnalyzer := types.NewStructFieldAnalyzer(typeRegistry, log)
+	if err := fieldAnalyzer.AnalyzeStructFields(); err != nil {
+		doc.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("error analyzing struct fields: %v", err))
+	}
+
+	// Analyze nested structs
+	fieldAnalyzer.AnalyzeNestedStructs()
+
+	log.Infof("  Type resolution system initialized successfully.")
+
+	// 5. Scan for Echo route definitions
+	log.Infof("Step 3: Scanning for Echo route definitions...")
+	routeScanner := scanner.NewRouteScanner(codeParser.FileSet, log)
+	routeScanner.ServeMux = netHTTP
+	for _, spec := range opts.RegistrarMethods {
+		registrar, err := scanner.ParseRegistrarMethod(spec)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing registrar method: %v", err)
+		}
+		routeScanner.AddRegistrarMethod(registrar)
+	}
+	if err := routeScanner.Scan(codeParser.GetAllFiles()); err != nil {
+		return nil, fmt.Errorf("error scanning for routes: %v", err)
+	}
+	routes := routeScanner.GetRoutes()
+	log.Infof("  Found %d routes.", len(routes))
+	if scope != nil {
+		routes = scope.filterRoutes(routes)
+		log.Infof("  Documenting %d routes registered in the selected packages and files.", len(routes))
+	}
+
+	// Echo keeps only the last handler registered for a method and path
+	duplicates := routeScanner.Validate()
+	if scope != nil {
+		duplicates = scope.filterDuplicates(duplicates)
+	}
+	if len(duplicates) > 0 {
+		if opts.Strict {
+			return nil, fmt.Errorf("duplicate route: %s", duplicates[0])
+		}
+		for _, duplicate := range duplicates {
+			doc.warn(DiagnosticDuplicateRoute, duplicate.Route.Position, fmt.Sprintf("duplicate route: %s", duplicate))
+		}
+	}
+
+	// Filter routes before handler analysis, so handlers that are only
+	// registered on excluded routes are left out of the documentation
+	if len(opts.IncludePaths) > 0 || len(opts.ExcludePaths) > 0 {
+		routeFilter, err := scanner.NewRouteFilter(opts.IncludePaths, opts.ExcludePaths, log)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing route filters: %v", err)
+		}
+		routes = routeFilter.Filter(routes)
+		log.Infof("  Documenting %d routes after filtering.", len(routes))
+	}
+
+	// Check path parameter naming, if enabled
+	if opts.PathParamConvention != "" {
+		linter, err := scanner.NewPathParamLinter(opts.PathParamConvention, log)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing path parameter convention: %v", err)
+		}
+		doc.PathParamDiagnostics = linter.Lint(routes)
+		log.Infof("  Found %d path parameters not following the %s convention.", len(doc.PathParamDiagnostics), opts.PathParamConvention)
+		for _, diagnostic := range doc.PathParamDiagnostics {
+			log.Infof("    %s", diagnostic)
+		}
+	}
+
+	// 6. Analyze handler functions
+	log.Infof("Step 4: Analyzing handler functions...")
+	handlerAnalyzer := handleranalyzer.NewHandlerAnalyzer(codeParser.FileSet, log)
+	handlerAnalyzer.DetectTimeouts = opts.DetectTimeouts
+	handlerAnalyzer.DefaultQueryHelper = opts.DefaultQueryHelper
+	if netHTTP {
+		handlerAnalyzer.UseNetHTTP(codeParser.GetAllFiles())
+	}
+	handlerAnalyzer.Detectors.Include(opts.Detectors)
+	if err := handlerAnalyzer.Analyze(codeParser.GetAllFiles(), routes); err != nil {
+		return nil, fmt.Errorf("error analyzing handlers: %v", err)
+	}
+	handlers := handlerAnalyzer.GetHandlers()
+	log.Infof("  Analyzed %d handlers.", len(handlers))
+
+	// Analyze the HTTP error handler, whose responses document errors
+	// returned by every route
+	var errorHandlerDecl *ast.FuncDecl
+	if errorHandler := routeScanner.GetErrorHandler(); errorHandler != nil {
+		doc.ErrorHandler = handlerAnalyzer.AnalyzeErrorHandler(codeParser.GetAllFiles(), errorHandler)
+		if doc.ErrorHandler != nil {
+			errorHandlerDecl = handleranalyzer.ErrorHandlerDecl(codeParser.GetAllFiles(), errorHandler)
+			log.Infof("  Analyzed HTTP error handler %s.", doc.ErrorHandler.Name)
+		} else {
+			doc.warn(DiagnosticMissingHandler, errorHandler.Position, fmt.Sprintf("HTTP error handler %s not found", errorHandler.HandlerName))
+		}
+	}
+
+	// Tag each route with the protocol its handler speaks
+	for i := range routes {
+		routes[i].Protocol = scanner.ProtocolHTTP
+		if handler := handlerAnalyzer.HandlerForRoute(routes[i]); handler != nil {
+			routes[i].Protocol = handler.Protocol
+		}
+	}
+
+	// 7. Analyze response and request body types
+	log.Infof("Step 5: Analyzing response types...")
+	statusConstants := types.CollectStatusConstants(codeParser.GetAllFiles())
+	filePackages := packagesByFile(codeParser.Packages)
+	responseTypes, requestTypes, warnings := analyzeHandlerTypes(codeParser.GetAllFiles(), filePackages, handlers, typeRegistry, statusConstants, envelope, handlerAnalyzer.Detectors, log, func(done, total int) {
+		progress("Analyzing handlers", done, total)
+	})
+	for _, warning := range warnings {
+		doc.warn(DiagnosticWarning, token.Position{}, warning)
+	}
+
+	if errorHandlerDecl != nil {
+		result := analyzeHandler(doc.ErrorHandler.Name, "", []*ast.FuncDecl{errorHandlerDecl}, filePackages, typeRegistry, statusConstants, envelope, handlerAnalyzer.Detectors, log)
+		for _, response := range result.Responses {
+			responseTypes[fmt.Sprintf("%s_%d", doc.ErrorHandler.Name, response.StatusCode)] = response
+			if response.Type != nil {
+				doc.ErrorHandler.SetResponseDataType(response.StatusCode, response.Type.Name)
+			}
+		}
+		for _, warning := range result.Warnings {
+			doc.warn(DiagnosticWarning, token.Position{}, warning)
+		}
+	}
+
+	unknownResponses := 0
+	for _, response := range responseTypes {
+		if response.Type.Kind == types.KindUnknown {
+			unknownResponses++
+		}
+	}
+	log.Infof("  Analyzed %d response types (%d could not be statically determined).", len(responseTypes), unknownResponses)
+
+	// 8. Scan for AWS SDK usage
+	log.Infof("Step 6: Analyzing AWS SDK usage...")
+	awsAnalyzer := aws.NewAWSAnalyzer(codeParser.FileSet, log)
+	awsAnalyzer.Registry = typeRegistry
+	awsAnalyzer.FilePackages = filePackages
+	awsFiles := codeParser.GetAllFiles()
+	if scope != nil {
+		awsFiles = scope.filter(awsFiles, codeParser.FileSet)
+	}
+	if err := awsAnalyzer.Analyze(awsFiles); err != nil {
+		return nil, fmt.Errorf("error analyzing AWS SDK usage: %v", err)
+	}
+	events := awsAnalyzer.GetEvents()
+	log.Infof("  Found %d AWS events.", len(events))
+
+	doc.Routes = routes
+	doc.Handlers = handlers
+	doc.Events = events
+	doc.Middleware = routeScanner.GetMiddleware()
+	doc.ResponseTypes = responseTypes
+	doc.RequestTypes = requestTypes
+	doc.TypeRegistry = typeRegistry
+	doc.Calls = collectCalls(codeParser.GetAllFiles())
+
+	return doc, nil
+}
+
+// packagesByFile maps the names of parsed files to the path of their package
+func packagesByFile(packages map[string]*ast.Package) map[string]string {
+	filePackages := make(map[string]string)
+	for pkgPath, pkg := range packages {
+		for fileName := range pkg.Files {
+			filePackages[fileName] = pkgPath
+		}
+	}
+	return filePackages
+}
+
+// handlerTypes holds the types analyzed in a handler
+type handlerTypes struct {
+	Responses   []*types.ResponseInfo
+	RequestBody *types.TypeDefinition
+	Warnings    []string
+}
+
+// analyzeHandlerTypes analyzes the responses and request body of every
+// handler across a pool of workers. Results are merged in handler name
+// order, so the output is the same regardless of how the goroutines are
+// scheduled.
+func analyzeHandlerTypes(files []*ast.File, filePackages map[string]string, handlers map[string]*handleranalyzer.HandlerInfo, typeRegistry *types.TypeRegistry, statusConstants map[string]int, envelope *types.Envelope, detectors *types.Detectors, log *logger.Logger, progress func(done, total int)) (map[string]*types.ResponseInfo, map[string]*types.TypeDefinition, []string) {
+	// Index function declarations by name
+	funcDecls := make(map[string][]*ast.FuncDecl)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				funcDecls[funcDecl.Name.Name] = append(funcDecls[funcDecl.Name.Name], funcDecl)
+			}
+		}
+	}
+
+	handlerNames := make([]string, 0, len(handlers))
+	for handlerName, handler := range handlers {
+		handlerNames = append(handlerNames, handlerName)
+
+		// Anonymous handlers are analyzed as a function declared with the
+		// name they're stored under
+		if funcLit, ok := handler.Route.HandlerNode.(*ast.FuncLit); ok && len(funcDecls[handlerName]) == 0 {
+			funcDecls[handlerName] = []*ast.FuncDecl{{
+				Name: ast.NewIdent(handlerName),
+				Type: funcLit.Type,
+				Body: funcLit.Body,
+			}}
+		}
+	}
+	sort.Strings(handlerNames)
+
+	// Each worker writes the types of a handler to its own slot
+	results := make([]handlerTypes, len(handlerNames))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	done := 0
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				bodyVar := ""
+				if body := handlers[handlerNames[i]].RequestBody(); body != nil {
+					bodyVar = body.Name
+				}
+				results[i] = analyzeHandler(handlerNames[i], bodyVar, funcDecls[handlerNames[i]], filePackages, typeRegistry, statusConstants, envelope, detectors, log)
+
+				progressMu.Lock()
+				done++
+				progress(done, len(handlerNames))
+				progressMu.Unlock()
+			}
+		}()
+	}
+	for i := range handlerNames {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	responseTypes := make(map[string]*types.ResponseInfo)
+	requestTypes := make(map[string]*types.TypeDefinition)
+	warnings := []string{}
+	for i, handlerName := range handlerNames {
+		for _, response := range results[i].Responses {
+			// Template models are documented by the HTML output they're
+			// rendered into, rather than as a response body
+			if response.Template != "" {
+				if response.Type != nil {
+					handlers[handlerName].SetTemplateModel(response.StatusCode, response.Template, response.Type.Name)
+				}
+				continue
+			}
+
+			responseKey := fmt.Sprintf("%s_%d", handlerName, response.StatusCode)
+			responseTypes[responseKey] = response
+
+			// Describe the output with the type its schema is generated from
+			if response.Type != nil {
+				handlers[handlerName].SetResponseDataType(response.StatusCode, response.Type.Name)
+			}
+		}
+
+		if requestBody := results[i].RequestBody; requestBody != nil {
+			// Fields bound from the path, query string or headers with
+			// c.Bind are parameters rather than part of the body
+			params, body := types.SplitBinding(requestBody)
+			handlers[handlerName].BindParams(params, body != nil)
+			if body != nil {
+				requestTypes[handlerName] = body
+				handlers[handlerName].RequestBody().DataType = body.Name
+			}
+		}
+		warnings = append(warnings, results[i].Warnings...)
+	}
+
+	return responseTypes, requestTypes, warnings
+}
+
+// analyzeHandler analyzes the JSON responses of the functions declaring a
+// handler, and the type of the variable its request body is bound to. Types
+// are resolved in the package declaring each function, found by file name
+// in filePackages.
+func analyzeHandler(handlerName, bodyVar string, funcDecls []*ast.FuncDecl, filePackages map[string]string, typeRegistry *types.TypeRegistry, statusConstants map[string]int, envelope *types.Envelope, detectors *types.Detectors, log *logger.Logger) handlerTypes {
+	result := handlerTypes{
+		Responses: []*types.ResponseInfo{},
+		Warnings:  []string{},
+	}
+
+	// Initialize variable tracker
+	variableTracker := types.NewVariableTracker(typeRegistry, log)
+
+	for _, funcDecl := range funcDecls {
+		// Track variables in the function
+		variableTracker.Package = filePackages[typeRegistry.FileSet.Position(funcDecl.Pos()).Filename]
+		if err := variableTracker.TrackFunction(funcDecl); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("error tracking variables in handler %s: %v", handlerName, err))
+			continue
+		}
+
+		// Resolve the request body type
+		if bodyVar != "" && result.RequestBody == nil {
+			result.RequestBody = variableTracker.GetVariableType(bodyVar)
+		}
+
+		// Analyze responses
+		responseAnalyzer := types.NewResponseAnalyzer(typeRegistry, variableTracker, log)
+		responseAnalyzer.StatusConstants = statusConstants
+		responseAnalyzer.Envelope = envelope
+		responseAnalyzer.Detectors = detectors
+		if err := responseAnalyzer.AnalyzeHandler(funcDecl); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("error analyzing responses in handler %s: %v", handlerName, err))
+			continue
+		}
+		result.Responses = append(result.Responses, responseAnalyzer.GetResponses()...)
+	}
+
+	return result
+}
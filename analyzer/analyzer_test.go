@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// analyzeFixture analyzes a repository holding copies of sample applications
+// of the testdata directory, given by file name
+func analyzeFixture(t *testing.T, names ...string) *APIDocument {
+	t.Helper()
+
+	files := make(map[string]string)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[name] = string(data)
+	}
+	return analyzeSource(t, files)
+}
+
+// analyzeSource analyzes a repository made of files, by path relative to its
+// root. A go.mod declaring the example.com/app module is added if missing.
+func analyzeSource(t *testing.T, files map[string]string) *APIDocument {
+	t.Helper()
+
+	doc, err := Analyze(Options{RepoPath: writeSource(t, files)})
+	if err != nil {
+		t.Fatalf("Analyze() = %v", err)
+	}
+	return doc
+}
+
+// writeSource writes a repository made of files, like analyzeSource, and
+// returns its root
+func writeSource(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	if _, exists := files["go.mod"]; !exists {
+		files["go.mod"] = "module example.com/app\n\ngo 1.18\n"
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// responseType returns the type of the response a handler writes with a
+// status code, failing the test if there is none
+func responseType(t *testing.T, doc *APIDocument, handler string, status int) *types.TypeDefinition {
+	t.Helper()
+
+	key := fmt.Sprintf("%s_%d", handler, status)
+	info, exists := doc.ResponseTypes[key]
+	if !exists || info.Type == nil {
+		keys := make([]string, 0, len(doc.ResponseTypes))
+		for key := range doc.ResponseTypes {
+			keys = append(keys, key)
+		}
+		t.Fatalf("no response type %s, found %v", key, keys)
+	}
+	return info.Type
+}
+
+// fieldType returns the type of a struct field, failing the test if the
+// struct has no such field
+func fieldType(t *testing.T, typ *types.TypeDefinition, name string) *types.TypeDefinition {
+	t.Helper()
+
+	for _, field := range typ.Fields {
+		if field.Name == name {
+			return field.Type
+		}
+	}
+	t.Fatalf("%s has no field %s", typ.Name, name)
+	return nil
+}
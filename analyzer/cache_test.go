@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/parser"
+)
+
+func TestCachedAnalysis(t *testing.T) {
+	source, err := os.ReadFile(filepath.Join("..", "testdata", "enhanced_sample_app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := writeSource(t, map[string]string{"main.go": string(source)})
+
+	// The second run loads the file from the cache written by the first
+	var docs []*APIDocument
+	for i := 0; i < 2; i++ {
+		doc, err := Analyze(Options{RepoPath: root, Cache: true})
+		if err != nil {
+			t.Fatalf("Analyze() = %v", err)
+		}
+		docs = append(docs, doc)
+		if _, err := os.Stat(filepath.Join(root, parser.CacheFileName)); err != nil {
+			t.Fatalf("no cache file after run %d: %v", i+1, err)
+		}
+	}
+
+	if len(docs[1].Routes) == 0 || len(docs[1].Routes) != len(docs[0].Routes) {
+		t.Fatalf("cached run found %d routes, want %d", len(docs[1].Routes), len(docs[0].Routes))
+	}
+	for i, route := range docs[1].Routes {
+		if route.Method != docs[0].Routes[i].Method || route.Path != docs[0].Routes[i].Path || route.Position != docs[0].Routes[i].Position {
+			t.Errorf("cached route %d = %s %s at %s, want %s %s at %s", i, route.Method, route.Path, route.Position,
+				docs[0].Routes[i].Method, docs[0].Routes[i].Path, docs[0].Routes[i].Position)
+		}
+	}
+	names := func(doc *APIDocument) map[string]string {
+		names := make(map[string]string)
+		for key, response := range doc.ResponseTypes {
+			names[key] = response.Type.Name
+		}
+		return names
+	}
+	if got, want := names(docs[1]), names(docs[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached run response types = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package analyzer
+
+import "github.com/user/golang-echo-analyzer/internal/types"
+
+// Detectors is a registry of custom response and request detectors, by the
+// name of the function or method whose calls they match. Set it as
+// Options.Detectors; detectors registered later for a name are tried first,
+// and custom detectors before the echo.Context methods.
+type Detectors = types.Detectors
+
+// ResponseDetector extracts the response written by a call to a response
+// helper, such as respondJSON(c, http.StatusOK, user) or c.OK(user)
+type ResponseDetector = types.ResponseDetector
+
+// ResponseDetectorFunc adapts a function to a ResponseDetector
+type ResponseDetectorFunc = types.ResponseDetectorFunc
+
+// ResponseMatch describes the response written by a matched call: its type
+// (JSON, XML, String, ...), status code and payload expressions
+type ResponseMatch = types.ResponseMatch
+
+// RequestDetector extracts the request input read by a call to a request
+// helper, such as pathInt(c, "id")
+type RequestDetector = types.RequestDetector
+
+// RequestDetectorFunc adapts a function to a RequestDetector
+type RequestDetectorFunc = types.RequestDetectorFunc
+
+// RequestMatch describes the request input read by a matched call: its
+// location (Path, Query, Header, Body, ...), name and default value
+type RequestMatch = types.RequestMatch
+
+// NewDetectors creates an empty Detectors registry
+func NewDetectors() *Detectors {
+	return types.NewDetectors()
+}
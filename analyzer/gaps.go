@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	handleranalyzer "github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// GapKind classifies a gap in the documentation
+type GapKind string
+
+// Kinds of documentation gaps, in the order they are reported
+const (
+	GapNoResponse      GapKind = "Routes with no resolved response type"
+	GapRequestBody     GapKind = "Request bodies with an unresolved type"
+	GapUnreadPathParam GapKind = "Path parameters never read by the handler"
+	GapUnknownResponse GapKind = "Responses typed unknown or any"
+	GapDynamicRoute    GapKind = "Routes with a method or path that isn't a constant"
+)
+
+// GapKinds lists every kind of documentation gap, in report order
+var GapKinds = []GapKind{GapNoResponse, GapRequestBody, GapUnreadPathParam, GapUnknownResponse, GapDynamicRoute}
+
+// Gap is a place where the documentation is incomplete because the analysis
+// couldn't determine something
+type Gap struct {
+	Kind     GapKind
+	Route    string // Method and path of the route, e.g. GET /users/:id
+	Detail   string
+	Location string // file:line relative to the repository root
+
+	// Missing reports whether the route's handler wasn't found, for
+	// GapNoResponse gaps
+	Missing bool
+}
+
+// Gaps returns the documentation gaps found in the analysis results. Gaps of
+// a handler shared by several routes are reported once, for its first route.
+func (d *APIDocument) Gaps() []Gap {
+	gaps := []Gap{}
+	seenHandlers := make(map[*handleranalyzer.HandlerInfo]bool)
+
+	for _, route := range d.Routes {
+		routeName := route.Method + " " + route.Path
+		handler := d.handlerForRoute(route.HandlerName, route.Method, route.Path)
+
+		if route.Dynamic {
+			gaps = append(gaps, Gap{
+				Kind:     GapDynamicRoute,
+				Route:    routeName,
+				Detail:   "the method or path is computed at runtime",
+				Location: d.sourceLocation(route.Position),
+			})
+		}
+
+		// WebSocket and SSE handlers are documented by their protocol
+		if handler == nil || (len(handler.ResponseOutputs) == 0 && handler.Protocol == scanner.ProtocolHTTP) {
+			detail := fmt.Sprintf("handler %s writes no response", route.HandlerName)
+			if handler == nil {
+				detail = fmt.Sprintf("handler %s not found", route.HandlerName)
+			}
+			gaps = append(gaps, Gap{
+				Kind:     GapNoResponse,
+				Route:    routeName,
+				Detail:   detail,
+				Location: d.sourceLocation(route.Position),
+				Missing:  handler == nil,
+			})
+		}
+		if handler == nil {
+			continue
+		}
+
+		// Path parameters are checked per route, since routes sharing a
+		// handler can declare different ones
+		read := make(map[string]bool)
+		for _, input := range handler.RequestInputs {
+			if input.Type == "Path" {
+				read[input.Name] = true
+			}
+		}
+		for _, param := range route.PathParams() {
+			if !read[param] {
+				gaps = append(gaps, Gap{
+					Kind:     GapUnreadPathParam,
+					Route:    routeName,
+					Detail:   fmt.Sprintf("path parameter %s is not read with c.Param", param),
+					Location: d.sourceLocation(route.Position),
+				})
+			}
+		}
+
+		if seenHandlers[handler] {
+			continue
+		}
+		seenHandlers[handler] = true
+
+		if body := handler.RequestBody(); body != nil && !isResolved(d.RequestTypes[handler.Name]) {
+			gaps = append(gaps, Gap{
+				Kind:     GapRequestBody,
+				Route:    routeName,
+				Detail:   fmt.Sprintf("body bound to %s in handler %s", body.Name, handler.Name),
+				Location: d.sourceLocation(body.Position),
+			})
+		}
+
+		for _, output := range handler.ResponseOutputs {
+			if output.Type != "JSON" && output.Type != "XML" {
+				continue
+			}
+			responseInfo := d.ResponseTypes[fmt.Sprintf("%s_%d", handler.Name, output.StatusCode)]
+			if responseInfo == nil || !isResolved(responseInfo.Type) {
+				gaps = append(gaps, Gap{
+					Kind:     GapUnknownResponse,
+					Route:    routeName,
+					Detail:   fmt.Sprintf("%d response of handler %s", output.StatusCode, handler.Name),
+					Location: d.sourceLocation(output.Position),
+				})
+			}
+		}
+	}
+
+	return gaps
+}
+
+// handlerForRoute finds the analyzed handler of a route, including anonymous
+// handlers, which are stored under a name generated from the route
+func (d *APIDocument) handlerForRoute(handlerName, method, path string) *handleranalyzer.HandlerInfo {
+	if handler, exists := d.Handlers[handlerName]; exists {
+		return handler
+	}
+	return d.Handlers[anonymousHandlerName(method, path)]
+}
+
+// anonymousHandlerName returns the name an anonymous handler is stored under
+func anonymousHandlerName(method, path string) string {
+	return fmt.Sprintf("anonymous_%s_%s", method, strings.Replace(path, "/", "_", -1))
+}
+
+// sourceLocation formats a position as file:line relative to the repository root
+func (d *APIDocument) sourceLocation(pos token.Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+
+	file := pos.Filename
+	if rel, err := filepath.Rel(d.RepoRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	return fmt.Sprintf("%s:%d", filepath.ToSlash(file), pos.Line)
+}
+
+// isResolved reports whether a type was statically determined and is more
+// specific than an empty interface
+func isResolved(typeDef *types.TypeDefinition) bool {
+	if typeDef == nil || typeDef.Kind == types.KindUnknown {
+		return false
+	}
+	return !(typeDef.Kind == types.KindInterface && (typeDef.Name == "interface{}" || typeDef.Name == "any"))
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNestedGroupsAcrossFunctions(t *testing.T) {
+	// /api and /api/v1 are created in newAPI, extended with /users and
+	// /admin in other functions and packages, each level adding middleware
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"example.com/app/users"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func main() {
+	e := echo.New()
+	v1 := newAPI(e)
+	users.Register(v1.Group("/users", authMiddleware))
+	registerHealth(v1)
+	e.Start(":8080")
+}
+
+func newAPI(e *echo.Echo) *echo.Group {
+	api := e.Group("/api", middleware.Logger())
+	v1 := api.Group("/v1")
+	v1.Use(middleware.Recover())
+	return v1
+}
+
+func registerHealth(g *echo.Group) {
+	g.GET("/health", health)
+}
+
+func authMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+func health(c echo.Context) error {
+	return c.NoContent(204)
+}
+`,
+		"users/users.go": `package users
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func Register(g *echo.Group) {
+	g.GET("/:id", GetUser)
+	registerAdmin(g.Group("/admin", middleware.BasicAuth(nil)))
+}
+
+func registerAdmin(admin *echo.Group) {
+	roles := admin.Group("/roles")
+	roles.DELETE("/:role", DeleteRole)
+}
+
+func GetUser(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func DeleteRole(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+`,
+	})
+
+	routes := make(map[string][]string)
+	for _, route := range doc.Routes {
+		routes[route.Method+" "+route.Path] = route.Middleware
+	}
+	want := map[string][]string{
+		"GET /api/v1/health":                     {"middleware.Logger", "middleware.Recover"},
+		"GET /api/v1/users/:id":                  {"middleware.Logger", "middleware.Recover", "authMiddleware"},
+		"DELETE /api/v1/users/admin/roles/:role": {"middleware.Logger", "middleware.Recover", "authMiddleware", "middleware.BasicAuth"},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("routes = %v, want %v", routes, want)
+	}
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"sort"
+
+	"github.com/user/golang-echo-analyzer/internal/aws"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// Index answers questions about the results of an analysis, such as which
+// routes return a type or which handlers publish to an SNS topic
+type Index struct {
+	returning  map[string][]scanner.RouteInfo
+	producers  map[string][]string
+	routeTypes map[string]RouteTypes
+}
+
+// RouteTypes holds the resolved request and response types of a route
+type RouteTypes struct {
+	Route     scanner.RouteInfo
+	Request   *types.TypeDefinition         // Bound request body type, if any
+	Responses map[int]*types.TypeDefinition // Response types, by status code
+}
+
+// NewIndex indexes the routes, types and events of an analysis
+func NewIndex(doc *APIDocument) *Index {
+	index := &Index{
+		returning:  make(map[string][]scanner.RouteInfo),
+		producers:  make(map[string][]string),
+		routeTypes: make(map[string]RouteTypes),
+	}
+
+	for _, route := range doc.Routes {
+		routeTypes := RouteTypes{
+			Route:     route,
+			Responses: make(map[int]*types.TypeDefinition),
+		}
+
+		if handler := doc.handlerForRoute(route.HandlerName, route.Method, route.Path); handler != nil {
+			routeTypes.Request = doc.RequestTypes[handler.Name]
+
+			returned := make(map[string]bool)
+			for _, output := range handler.ResponseOutputs {
+				responseInfo := doc.ResponseTypes[fmt.Sprintf("%s_%d", handler.Name, output.StatusCode)]
+				if responseInfo == nil || responseInfo.Type == nil {
+					continue
+				}
+				routeTypes.Responses[output.StatusCode] = responseInfo.Type
+				for _, name := range returnedTypeNames(responseInfo.Type) {
+					if !returned[name] {
+						returned[name] = true
+						index.returning[name] = append(index.returning[name], route)
+					}
+				}
+			}
+		}
+
+		index.routeTypes[route.Method+" "+route.Path] = routeTypes
+	}
+
+	// Events produced by a function are produced by the handlers calling it
+	callers := reachingHandlers(doc)
+	for _, event := range doc.Events {
+		if event.Direction != aws.DirectionProduce || event.Target == "" || event.Function == "" {
+			continue
+		}
+		for _, handler := range callers[event.Function] {
+			index.producers[event.Target] = appendUnique(index.producers[event.Target], handler)
+		}
+	}
+	for target := range index.producers {
+		sort.Strings(index.producers[target])
+	}
+
+	return index
+}
+
+// RoutesReturning returns the routes with a response of a type, given by
+// name and optionally qualified by its package name, e.g. Order or
+// models.Order. Pointers to the type and slices and maps of it count too.
+func (x *Index) RoutesReturning(typeName string) []scanner.RouteInfo {
+	return x.returning[typeName]
+}
+
+// ProducersOf returns the names of the handlers writing events to a topic,
+// queue, table, bucket or event bus, directly or through the functions they
+// call, sorted by name
+func (x *Index) ProducersOf(target string) []string {
+	return x.producers[target]
+}
+
+// RouteTypes returns the resolved request and response types of a route
+func (x *Index) RouteTypes(method, path string) (RouteTypes, bool) {
+	routeTypes, exists := x.routeTypes[method+" "+path]
+	return routeTypes, exists
+}
+
+// returnedTypeNames returns the names a response type is looked up by: the
+// name of the named type it is, or holds through pointers, slices and maps,
+// with and without its package name
+func returnedTypeNames(typeDef *types.TypeDefinition) []string {
+	for typeDef != nil {
+		switch typeDef.Kind {
+		case types.KindPointer, types.KindArray:
+			typeDef = typeDef.ElementType
+			continue
+		case types.KindMap:
+			typeDef = typeDef.ValueType
+			continue
+		case types.KindUnknown, types.KindBasic:
+			return nil
+		}
+		if typeDef.Name == "" || typeDef.Name == "anonymous" {
+			return nil
+		}
+		if typeDef.Package == "" {
+			return []string{typeDef.Name}
+		}
+		return []string{typeDef.Name, path.Base(typeDef.Package) + "." + typeDef.Name}
+	}
+	return nil
+}
+
+// reachingHandlers maps each function to the handlers calling it, directly
+// or through other functions. A handler reaches itself.
+func reachingHandlers(doc *APIDocument) map[string][]string {
+	callers := make(map[string][]string)
+	for handlerName := range doc.Handlers {
+		visited := make(map[string]bool)
+		queue := []string{handlerName}
+		for len(queue) > 0 {
+			function := queue[0]
+			queue = queue[1:]
+			if visited[function] {
+				continue
+			}
+			visited[function] = true
+			callers[function] = append(callers[function], handlerName)
+			queue = append(queue, doc.Calls[function]...)
+		}
+	}
+	return callers
+}
+
+// collectCalls maps each function declared in the files to the declared
+// functions it calls by name, e.g. sendOrderCreatedEvent(order) or
+// s.sendOrderCreatedEvent(order)
+func collectCalls(files []*ast.File) map[string][]string {
+	declared := make(map[string]bool)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				declared[funcDecl.Name.Name] = true
+			}
+		}
+	}
+
+	calls := make(map[string][]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			caller := funcDecl.Name.Name
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				callee := ""
+				switch fun := call.Fun.(type) {
+				case *ast.Ident:
+					callee = fun.Name
+				case *ast.SelectorExpr:
+					callee = fun.Sel.Name
+				}
+				if declared[callee] && callee != caller {
+					calls[caller] = appendUnique(calls[caller], callee)
+				}
+				return true
+			})
+		}
+	}
+	return calls
+}
+
+// appendUnique appends a value to a slice unless it already holds it
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,34 @@
+package analyzer
+
+import "testing"
+
+// hasInput reports whether a handler reads a request input of a type and name
+func hasInput(t *testing.T, doc *APIDocument, handler, inputType, name string) bool {
+	t.Helper()
+
+	info, exists := doc.Handlers[handler]
+	if !exists {
+		t.Fatalf("handler %s wasn't analyzed", handler)
+	}
+	for _, input := range info.RequestInputs {
+		if input.Type == inputType && input.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHeaderAndCookieInputs(t *testing.T) {
+	doc := analyzeFixture(t, "enhanced_sample_app.go")
+
+	// apiKey := c.Request().Header.Get("X-Api-Key"); session, _ := c.Cookie("session")
+	for _, input := range []struct{ inputType, name string }{
+		{"Header", "X-Api-Key"},
+		{"Cookie", "session"},
+		{"Query", "status"},
+	} {
+		if !hasInput(t, doc, "getOrders", input.inputType, input.name) {
+			t.Errorf("getOrders has no %s input %s: %+v", input.inputType, input.name, doc.Handlers["getOrders"].RequestInputs)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPathParamConvention(t *testing.T) {
+	source, err := os.ReadFile(filepath.Join("..", "testdata", "enhanced_sample_app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := writeSource(t, map[string]string{"main.go": string(source)})
+
+	// r.GET("/users/:userId/orders/:id", getUserOrder) mixes camelCase and lowercase
+	for _, test := range []struct {
+		convention string
+		want       []string
+	}{
+		{"", nil},
+		{"camelCase", nil},
+		{"snake_case", []string{"userId"}},
+		{"^id$", []string{"userId"}},
+	} {
+		doc, err := Analyze(Options{RepoPath: root, PathParamConvention: test.convention})
+		if err != nil {
+			t.Fatalf("Analyze(%q) = %v", test.convention, err)
+		}
+
+		var got []string
+		for _, diagnostic := range doc.PathParamDiagnostics {
+			if diagnostic.Route.Path == "/users/:userId/orders/:id" {
+				got = append(got, diagnostic.Param)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("convention %q flags %v in /users/:userId/orders/:id, want %v", test.convention, got, test.want)
+		}
+	}
+
+	if _, err := Analyze(Options{RepoPath: root, PathParamConvention: "(["}); err == nil {
+		t.Error("Analyze() with an invalid convention succeeded, want an error")
+	}
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerboseLogsToOptionsLog(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "testdata", "enhanced_sample_app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := writeSource(t, map[string]string{"main.go": string(data)})
+
+	// Capture stdout, which must receive nothing
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+
+	var log bytes.Buffer
+	_, err = Analyze(Options{RepoPath: root, Verbose: true, Log: &log})
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatalf("Analyze() = %v", err)
+	}
+
+	if out := <-captured; out != "" {
+		t.Errorf("Analyze() wrote to stdout:\n%s", out)
+	}
+	for _, want := range []string{
+		"Step 1: Parsing Go source files...",
+		"Collecting types from package: example.com/app",
+		"Collected struct type: User",
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("log has no %q:\n%s", want, log.String())
+		}
+	}
+}
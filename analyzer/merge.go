@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	handleranalyzer "github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/aws"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// Merge combines the documents of several repositories, e.g. the services
+// of a microservice architecture, into a single document. Each service is
+// named after its repository directory. Handler names are qualified with the
+// service name, as in orders.getOrder, and the types of each service are
+// tagged with it, so schemas of types sharing a name in different services
+// don't collide. The documents are modified in place.
+//
+// Global middleware is folded into the middleware of each route of its
+// service. HTTP error handlers apply to a single service, so they aren't
+// part of the merged document; a warning notes each one left out.
+func Merge(docs []*APIDocument) *APIDocument {
+	if len(docs) == 1 {
+		return docs[0]
+	}
+
+	merged := &APIDocument{
+		Routes:        []scanner.RouteInfo{},
+		Events:        []aws.EventInfo{},
+		Handlers:      make(map[string]*handleranalyzer.HandlerInfo),
+		ResponseTypes: make(map[string]*types.ResponseInfo),
+		RequestTypes:  make(map[string]*types.TypeDefinition),
+		Calls:         make(map[string][]string),
+		Warnings:      []string{},
+		Diagnostics:   []Diagnostic{},
+	}
+
+	repoRoots := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		repoRoots = append(repoRoots, doc.RepoRoot)
+	}
+	merged.RepoRoot = commonDir(repoRoots)
+
+	services := serviceNames(repoRoots)
+	registered := make(map[string]string) // Service of each method and path
+	for i, doc := range docs {
+		service := services[i]
+		qualify := func(name string) string { return service + "." + name }
+
+		for _, diagnostic := range doc.Diagnostics {
+			merged.warn(diagnostic.Kind, diagnostic.Position, fmt.Sprintf("%s: %s", service, diagnostic.Message))
+		}
+		if doc.ErrorHandler != nil {
+			merged.warn(DiagnosticWarning, token.Position{}, fmt.Sprintf("%s: HTTP error handler %s is not documented when several repositories are merged", service, doc.ErrorHandler.Name))
+		}
+
+		// Qualify handler names, following anonymous handlers to the name
+		// they're stored under
+		for _, route := range doc.Routes {
+			handlerName := route.HandlerName
+			if _, exists := doc.Handlers[handlerName]; !exists {
+				if _, exists := doc.Handlers[anonymousHandlerName(route.Method, route.Path)]; exists {
+					handlerName = anonymousHandlerName(route.Method, route.Path)
+				}
+			}
+
+			route.HandlerName = qualify(handlerName)
+			route.Service = service
+			route.Source = doc.RepoRoot
+			route.Middleware = append(append([]string{}, doc.Middleware...), route.Middleware...)
+
+			key := route.Method + " " + route.Path
+			if previous, exists := registered[key]; exists && previous != service {
+				merged.warn(DiagnosticDuplicateRoute, route.Position, fmt.Sprintf("%s is registered by both %s and %s", key, previous, service))
+			}
+			registered[key] = service
+
+			merged.Routes = append(merged.Routes, route)
+		}
+
+		visited := make(map[*types.TypeDefinition]bool)
+		for name, handler := range doc.Handlers {
+			handler.Name = qualify(name)
+			merged.Handlers[qualify(name)] = handler
+		}
+		for key, response := range doc.ResponseTypes {
+			setService(response.Type, service, visited)
+			merged.ResponseTypes[qualify(key)] = response
+		}
+		for name, requestType := range doc.RequestTypes {
+			setService(requestType, service, visited)
+			merged.RequestTypes[qualify(name)] = requestType
+		}
+
+		for caller, callees := range doc.Calls {
+			for _, callee := range callees {
+				merged.Calls[qualify(caller)] = append(merged.Calls[qualify(caller)], qualify(callee))
+			}
+		}
+		for _, event := range doc.Events {
+			if event.Function != "" {
+				event.Function = qualify(event.Function)
+			}
+			setService(event.MessageType, service, visited)
+			merged.Events = append(merged.Events, event)
+		}
+		for _, diagnostic := range doc.PathParamDiagnostics {
+			diagnostic.Route.Service = service
+			merged.PathParamDiagnostics = append(merged.PathParamDiagnostics, diagnostic)
+		}
+		if merged.TypeRegistry == nil {
+			merged.TypeRegistry = doc.TypeRegistry
+		}
+	}
+
+	return merged
+}
+
+// setService tags a type and the types it references with a service
+func setService(typeDef *types.TypeDefinition, service string, visited map[*types.TypeDefinition]bool) {
+	if typeDef == nil || visited[typeDef] {
+		return
+	}
+	visited[typeDef] = true
+
+	typeDef.Service = service
+	for _, field := range typeDef.Fields {
+		setService(field.Type, service, visited)
+	}
+	setService(typeDef.ElementType, service, visited)
+	setService(typeDef.KeyType, service, visited)
+	setService(typeDef.ValueType, service, visited)
+	for _, impl := range typeDef.Implementations {
+		setService(impl, service, visited)
+	}
+}
+
+// serviceNames names the service of each repository after its directory,
+// numbering repeated names
+func serviceNames(repoRoots []string) []string {
+	names := make([]string, len(repoRoots))
+	seen := make(map[string]int)
+	for i, repoRoot := range repoRoots {
+		name := filepath.Base(repoRoot)
+		if count := seen[name]; count > 0 {
+			names[i] = fmt.Sprintf("%s%d", name, count+1)
+		} else {
+			names[i] = name
+		}
+		seen[name]++
+	}
+	return names
+}
+
+// commonDir returns the deepest directory containing every path
+func commonDir(paths []string) string {
+	dir := paths[0]
+	for _, path := range paths[1:] {
+		for dir != filepath.Dir(dir) && path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"errors"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/parser"
+)
+
+// Kinds of diagnostics collected during an analysis
+const (
+	DiagnosticParseError     = "parse_error"
+	DiagnosticDuplicateRoute = "duplicate_route"
+	DiagnosticMissingHandler = "missing_handler"
+	DiagnosticWarning        = "warning"
+)
+
+// Diagnostic is an error that didn't stop the analysis
+type Diagnostic struct {
+	Kind     string // One of the Diagnostic* kinds
+	Message  string
+	Position token.Position // Where the error is, if known
+}
+
+// warn records an error that didn't stop the analysis
+func (d *APIDocument) warn(kind string, pos token.Position, message string) {
+	d.Warnings = append(d.Warnings, message)
+	d.Diagnostics = append(d.Diagnostics, Diagnostic{Kind: kind, Message: message, Position: pos})
+}
+
+// parseErrorPosition returns the file and line of an error skipping a file
+func parseErrorPosition(err error) token.Position {
+	var fileErr *parser.FileError
+	if errors.As(err, &fileErr) {
+		return token.Position{Filename: fileErr.Path, Line: fileErr.Line}
+	}
+	return token.Position{}
+}
+
+// ReportVersion is the version of the Report format. It changes only when
+// a field is removed or changes meaning.
+const ReportVersion = 1
+
+// Severity ranks report findings
+type Severity string
+
+// Severities of report findings
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Kinds of report findings, in addition to the Diagnostic* kinds
+const (
+	FindingUnresolvedType  = "unresolved_type"
+	FindingNoResponse      = "no_response"
+	FindingUnreadPathParam = "unread_path_param"
+	FindingDynamicRoute    = "dynamic_route"
+)
+
+// Report is a machine-readable summary of the problems an analysis found,
+// for CI systems. Its JSON encoding is stable across versions of the tool
+// with the same ReportVersion.
+type Report struct {
+	Version  int             `json:"version"`
+	Summary  ReportSummary   `json:"summary"`
+	Findings []ReportFinding `json:"findings"`
+
+	// Gaps counts the documentation gaps by kind, as listed by GapKinds
+	Gaps map[string]int `json:"gaps"`
+}
+
+// ReportSummary counts the routes analyzed and the findings by severity
+type ReportSummary struct {
+	Routes   int `json:"routes"`
+	Handlers int `json:"handlers"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+}
+
+// ReportFinding is a problem found by the analysis
+type ReportFinding struct {
+	Kind     string   `json:"kind"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Route    string   `json:"route,omitempty"` // Method and path, e.g. GET /users/:id
+	File     string   `json:"file,omitempty"`  // Relative to the repository root
+	Line     int      `json:"line,omitempty"`
+}
+
+// findingKinds maps gap kinds to the kinds of their findings and severities
+var findingKinds = map[GapKind]struct {
+	Kind     string
+	Severity Severity
+}{
+	GapNoResponse:      {FindingNoResponse, SeverityWarning},
+	GapRequestBody:     {FindingUnresolvedType, SeverityWarning},
+	GapUnreadPathParam: {FindingUnreadPathParam, SeverityInfo},
+	GapUnknownResponse: {FindingUnresolvedType, SeverityWarning},
+	GapDynamicRoute:    {FindingDynamicRoute, SeverityInfo},
+}
+
+// gapKeys names the gap kinds in Report.Gaps
+var gapKeys = map[GapKind]string{
+	GapNoResponse:      "no_response",
+	GapRequestBody:     "unresolved_request_body",
+	GapUnreadPathParam: "unread_path_param",
+	GapUnknownResponse: "unresolved_response",
+	GapDynamicRoute:    "dynamic_route",
+}
+
+// Report summarizes the diagnostics and documentation gaps of the analysis
+func (d *APIDocument) Report() *Report {
+	report := &Report{
+		Version:  ReportVersion,
+		Findings: []ReportFinding{},
+		Gaps:     make(map[string]int),
+	}
+	report.Summary.Routes = len(d.Routes)
+	report.Summary.Handlers = len(d.Handlers)
+
+	for _, diagnostic := range d.Diagnostics {
+		severity := SeverityWarning
+		if diagnostic.Kind == DiagnosticParseError || diagnostic.Kind == DiagnosticDuplicateRoute {
+			severity = SeverityError
+		}
+		file, line := splitLocation(d.sourceLocation(diagnostic.Position))
+		report.Findings = append(report.Findings, ReportFinding{
+			Kind:     diagnostic.Kind,
+			Severity: severity,
+			Message:  diagnostic.Message,
+			File:     file,
+			Line:     line,
+		})
+	}
+
+	for _, kind := range GapKinds {
+		report.Gaps[gapKeys[kind]] = 0
+	}
+	for _, gap := range d.Gaps() {
+		report.Gaps[gapKeys[gap.Kind]]++
+
+		// Routes whose handler wasn't found have no response either
+		finding := findingKinds[gap.Kind]
+		if gap.Kind == GapNoResponse && gap.Missing {
+			finding.Kind = DiagnosticMissingHandler
+		}
+		file, line := splitLocation(gap.Location)
+		report.Findings = append(report.Findings, ReportFinding{
+			Kind:     finding.Kind,
+			Severity: finding.Severity,
+			Message:  gap.Detail,
+			Route:    gap.Route,
+			File:     file,
+			Line:     line,
+		})
+	}
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case SeverityError:
+			report.Summary.Errors++
+		case SeverityWarning:
+			report.Summary.Warnings++
+		case SeverityInfo:
+			report.Summary.Infos++
+		}
+	}
+	return report
+}
+
+// splitLocation splits a file:line location, as formatted by sourceLocation
+func splitLocation(location string) (string, int) {
+	i := strings.LastIndex(location, ":")
+	if i < 0 {
+		return location, 0
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:i], line
+}
@@ -0,0 +1,426 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+func TestCompositeLiteralResponse(t *testing.T) {
+	doc := analyzeFixture(t, "enhanced_sample_app.go")
+
+	// order := Order{..., Items: []OrderItem{{...}}, ShippingAddress: Address{...}}
+	order := responseType(t, doc, "updateOrderStatus", 200)
+	if order.Name != "Order" {
+		t.Fatalf("updateOrderStatus response = %s, want Order", order.Name)
+	}
+	if address := fieldType(t, order, "ShippingAddress"); address == nil || address.Name != "Address" || len(address.Fields) == 0 {
+		t.Errorf("Order.ShippingAddress = %+v, want the Address struct", address)
+	}
+	if items := fieldType(t, order, "Items"); items == nil || items.Name != "OrderItems" {
+		t.Errorf("Order.Items = %+v, want OrderItems", items)
+	}
+}
+
+func TestCompositeLiteralResponseInHandlerPackage(t *testing.T) {
+	// Both packages declare Order; each handler's literal is of its own package's
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"example.com/app/api"
+	"example.com/app/legacy"
+	"github.com/labstack/echo/v4"
+)
+
+func main() {
+	e := echo.New()
+	api.Register(e)
+	legacy.Register(e)
+	e.Start(":8080")
+}
+`,
+		"api/orders.go": `package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Order struct {
+	ID    int
+	Items []OrderItem
+}
+
+type OrderItem struct {
+	ProductID int
+}
+
+func Register(e *echo.Echo) {
+	e.PUT("/orders/:id/status", UpdateOrderStatus)
+}
+
+func UpdateOrderStatus(c echo.Context) error {
+	order := Order{
+		ID:    1,
+		Items: []OrderItem{{ProductID: 1}},
+	}
+	order = Order{ID: 2}
+	return c.JSON(http.StatusOK, order)
+}
+`,
+		"legacy/orders.go": `package legacy
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Order struct {
+	Reference string
+}
+
+func Register(e *echo.Echo) {
+	e.GET("/legacy/orders/:id", GetOrder)
+}
+
+func GetOrder(c echo.Context) error {
+	order := Order{Reference: c.Param("id")}
+	return c.JSON(http.StatusOK, order)
+}
+`,
+	})
+
+	for handler, field := range map[string]string{"UpdateOrderStatus": "Items", "GetOrder": "Reference"} {
+		order := responseType(t, doc, handler, 200)
+		if order.Name != "Order" {
+			t.Errorf("%s response = %s, want Order", handler, order.Name)
+			continue
+		}
+		fieldType(t, order, field)
+	}
+}
+
+func TestJSONPrettyAndJSONBlobResponses(t *testing.T) {
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Product struct {
+	SKU string ` + "`json:\"sku\"`" + `
+}
+
+func main() {
+	e := echo.New()
+	e.GET("/users/:id", getUser)
+	e.GET("/products/:sku", getProduct)
+	e.GET("/raw", getRaw)
+	e.Start(":8080")
+}
+
+func getUser(c echo.Context) error {
+	user := User{ID: 1, Name: "Jane"}
+	return c.JSONPretty(http.StatusOK, user, "  ")
+}
+
+func getProduct(c echo.Context) error {
+	product := Product{SKU: c.Param("sku")}
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	return c.JSONBlob(http.StatusOK, data)
+}
+
+func getRaw(c echo.Context) error {
+	return c.JSONBlob(http.StatusAccepted, []byte("{}"))
+}
+`,
+	})
+
+	// The indent argument of JSONPretty isn't the payload
+	if user := responseType(t, doc, "getUser", 200); user.Name != "User" {
+		t.Errorf("getUser response = %s, want User", user.Name)
+	}
+
+	// data, err := json.Marshal(product) traces the blob back to Product
+	if product := responseType(t, doc, "getProduct", 200); product.Name != "Product" {
+		t.Errorf("getProduct response = %s, want Product", product.Name)
+	}
+
+	// A blob that isn't marshaled from a value can't be typed
+	if raw := responseType(t, doc, "getRaw", 202); raw.Kind != types.KindUnknown {
+		t.Errorf("getRaw response = %s (%v), want an unknown type", raw.Name, raw.Kind)
+	}
+}
+
+func TestFieldTypeFromSiblingFile(t *testing.T) {
+	// Order is collected from orders.go before Address is from shipping.go
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import "github.com/labstack/echo/v4"
+
+func main() {
+	e := echo.New()
+	e.GET("/orders/:id", getOrder)
+	e.Start(":8080")
+}
+`,
+		"orders.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Order struct {
+	ID              int
+	ShippingAddress Address
+	BillingAddress  *Address
+	PastAddresses   []Address
+}
+
+func getOrder(c echo.Context) error {
+	return c.JSON(http.StatusOK, Order{ID: 1})
+}
+`,
+		"shipping.go": `package main
+
+type Address struct {
+	Street string
+	City   string
+}
+`,
+	})
+
+	order := responseType(t, doc, "getOrder", 200)
+	if address := fieldType(t, order, "ShippingAddress"); address == nil || address.Name != "Address" || address.Kind != types.KindStruct || len(address.Fields) != 2 {
+		t.Errorf("Order.ShippingAddress = %+v, want the Address struct", address)
+	}
+	for _, name := range []string{"BillingAddress", "PastAddresses"} {
+		if typ := fieldType(t, order, name); typ == nil || typ.ElementType == nil || typ.ElementType.Name != "Address" || len(typ.ElementType.Fields) != 2 {
+			t.Errorf("Order.%s = %+v, want a pointer or slice of the Address struct", name, typ)
+		}
+	}
+}
+
+func TestNamedSliceAndMapTypes(t *testing.T) {
+	doc := analyzeFixture(t, "enhanced_sample_app.go")
+	order := responseType(t, doc, "updateOrderStatus", 200)
+
+	// type OrderItems []OrderItem
+	items := fieldType(t, order, "Items")
+	if items == nil || items.Name != "OrderItems" || items.Kind != types.KindArray {
+		t.Fatalf("Order.Items = %+v, want the OrderItems slice", items)
+	}
+	if item := items.ElementType; item == nil || item.Name != "OrderItem" || item.Kind != types.KindStruct || len(item.Fields) == 0 {
+		t.Errorf("OrderItems element = %+v, want the OrderItem struct", item)
+	}
+
+	// type Attributes map[string]string
+	attributes := fieldType(t, order, "Attributes")
+	if attributes == nil || attributes.Name != "Attributes" || attributes.Kind != types.KindMap {
+		t.Fatalf("Order.Attributes = %+v, want the Attributes map", attributes)
+	}
+	if attributes.KeyType == nil || attributes.KeyType.Name != "string" || attributes.ValueType == nil || attributes.ValueType.Name != "string" {
+		t.Errorf("Attributes = map[%+v]%+v, want map[string]string", attributes.KeyType, attributes.ValueType)
+	}
+}
+
+func TestNamedMapOfStructs(t *testing.T) {
+	// The element and value types aren't strings, which would go unnoticed
+	// with the fixture's map[string]string
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Product struct {
+	SKU string
+}
+
+type Attrs map[string]Product
+
+type Ratings []int
+
+type Catalog struct {
+	Products Attrs
+	Ratings  Ratings
+}
+
+func main() {
+	e := echo.New()
+	e.GET("/catalog", getCatalog)
+	e.Start(":8080")
+}
+
+func getCatalog(c echo.Context) error {
+	return c.JSON(http.StatusOK, Catalog{})
+}
+`,
+	})
+	catalog := responseType(t, doc, "getCatalog", 200)
+
+	if products := fieldType(t, catalog, "Products"); products == nil || products.Kind != types.KindMap || products.ValueType == nil || products.ValueType.Name != "Product" || len(products.ValueType.Fields) == 0 {
+		t.Errorf("Catalog.Products = %+v, want map[string]Product", products)
+	}
+	if ratings := fieldType(t, catalog, "Ratings"); ratings == nil || ratings.Kind != types.KindArray || ratings.ElementType == nil || ratings.ElementType.Name != "int" {
+		t.Errorf("Catalog.Ratings = %+v, want []int", ratings)
+	}
+}
+
+func TestResponseDataTypeNamesSchemaType(t *testing.T) {
+	// Each output names the type its response schema is generated from,
+	// rather than the expression passed to c.JSON
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"example.com/app/models"
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	Name string
+}
+
+type ErrorResponse struct {
+	Error string
+}
+
+func main() {
+	e := echo.New()
+	e.GET("/users/:id", getUser)
+	e.Start(":8080")
+}
+
+func getUser(c echo.Context) error {
+	switch c.Param("id") {
+	case "":
+		return c.JSON(400, ErrorResponse{Error: "x"})
+	case "0":
+		return c.JSON(http.StatusNotFound, models.NotFound{Resource: "user"})
+	}
+	user := User{Name: c.Param("id")}
+	return c.JSON(http.StatusOK, user)
+}
+`,
+		"models/models.go": `package models
+
+type NotFound struct {
+	Resource string
+}
+`,
+	})
+
+	for status, want := range map[int]string{200: "User", 400: "ErrorResponse", 404: "NotFound"} {
+		if typ := responseType(t, doc, "getUser", status); typ.Name != want {
+			t.Errorf("getUser %d response = %s, want %s", status, typ.Name, want)
+		}
+		found := false
+		for _, output := range doc.Handlers["getUser"].ResponseOutputs {
+			if output.StatusCode == status {
+				found = true
+				if output.DataType != want {
+					t.Errorf("getUser %d output data type = %q, want %q", status, output.DataType, want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("getUser has no %d output: %+v", status, doc.Handlers["getUser"].ResponseOutputs)
+		}
+	}
+}
+
+// assertUserElements fails the test unless typ is a slice of the User struct,
+// or of pointers to it if pointers is set, with its fields resolved
+func assertUserElements(t *testing.T, what string, typ *types.TypeDefinition, pointers bool) {
+	t.Helper()
+
+	if typ == nil || typ.Kind != types.KindArray || typ.ElementType == nil {
+		t.Fatalf("%s = %+v, want a slice of User", what, typ)
+	}
+	user := typ.ElementType
+	if pointers {
+		if user.Kind != types.KindPointer || user.ElementType == nil {
+			t.Fatalf("%s element = %+v, want *User", what, user)
+		}
+		user = user.ElementType
+	}
+	if user.Kind != types.KindStruct || user.Name != "User" || len(user.Fields) == 0 {
+		t.Fatalf("%s element = %+v, want the User struct", what, user)
+	}
+	if profile := fieldType(t, user, "Profile"); profile == nil || profile.Kind != types.KindPointer || profile.ElementType == nil || len(profile.ElementType.Fields) == 0 {
+		t.Errorf("%s User.Profile = %+v, want *Profile", what, profile)
+	}
+}
+
+func TestSliceLiteralResponses(t *testing.T) {
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID      int
+	Name    string
+	Profile *Profile
+}
+
+type Profile struct {
+	Bio string
+}
+
+func main() {
+	e := echo.New()
+	e.GET("/users/featured", getFeaturedUsers)
+	e.GET("/users/featured/pointers", getFeaturedUserPointers)
+	e.Start(":8080")
+}
+
+func getFeaturedUsers(c echo.Context) error {
+	return c.JSON(http.StatusOK, []User{
+		{ID: 1, Name: "John Doe"},
+		{ID: 2, Name: "Jane Smith"},
+	})
+}
+
+func getFeaturedUserPointers(c echo.Context) error {
+	return c.JSON(http.StatusOK, []*User{
+		{ID: 1, Name: "John Doe"},
+	})
+}
+`,
+	})
+	assertUserElements(t, "[]User{...}", responseType(t, doc, "getFeaturedUsers", 200), false)
+	assertUserElements(t, "[]*User{...}", responseType(t, doc, "getFeaturedUserPointers", 200), true)
+
+	// The sample app documents its []User{...} response
+	doc = analyzeFixture(t, "enhanced_sample_app.go")
+	assertUserElements(t, "getFeaturedUsers_200", responseType(t, doc, "getFeaturedUsers", 200), false)
+}
@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/parser"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+)
+
+// scope is the part of a repository selected with Options.Packages and
+// Options.Files, whose routes and AWS events are documented
+type scope struct {
+	dirs  map[string]bool // Directories of the selected packages
+	files map[string]bool // Selected files
+}
+
+// newScope resolves the selected packages and files to paths in the
+// repository, or returns nil if the whole repository is analyzed. Packages
+// are given by import path or by directory, and files by path, relative to
+// the repository root unless they're absolute.
+func newScope(repoRoot string, packages, files []string) (*scope, error) {
+	if len(packages) == 0 && len(files) == 0 {
+		return nil, nil
+	}
+
+	s := &scope{
+		dirs:  make(map[string]bool),
+		files: make(map[string]bool),
+	}
+	modulePath := parser.ReadModulePath(repoRoot)
+	for _, pkg := range packages {
+		dir := repoPath(repoRoot, pkg)
+		if modulePath != "" && (pkg == modulePath || strings.HasPrefix(pkg, modulePath+"/")) {
+			dir = filepath.Join(repoRoot, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(pkg, modulePath), "/")))
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("package %s not found in %s", pkg, repoRoot)
+		}
+		s.dirs[dir] = true
+	}
+	for _, file := range files {
+		path := repoPath(repoRoot, file)
+		if info, err := os.Stat(path); err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil, fmt.Errorf("Go file %s not found in %s", file, repoRoot)
+		}
+		s.files[path] = true
+	}
+	return s, nil
+}
+
+// repoPath returns the absolute path of a path relative to the repository root
+func repoPath(repoRoot, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(repoRoot, filepath.FromSlash(path))
+}
+
+// parseDirs returns the directories of the selected packages and files, in
+// sorted order. Whole directories are selected, so the types a selected file
+// refers to are found in its package.
+func (s *scope) parseDirs() []string {
+	seen := make(map[string]bool)
+	dirs := []string{}
+	for dir := range s.dirs {
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	for file := range s.files {
+		if dir := filepath.Dir(file); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// contains reports whether a file is in the scope
+func (s *scope) contains(fileName string) bool {
+	return s.dirs[filepath.Dir(fileName)] || s.files[fileName]
+}
+
+// filter returns the files in the scope
+func (s *scope) filter(files []*ast.File, fset *token.FileSet) []*ast.File {
+	var selected []*ast.File
+	for _, file := range files {
+		if s.contains(fset.Position(file.Pos()).Filename) {
+			selected = append(selected, file)
+		}
+	}
+	return selected
+}
+
+// filterRoutes returns the routes registered in the scope. Routes are
+// scanned across the whole repository, so the groups they're registered on
+// carry the prefixes and middleware applied outside the scope.
+func (s *scope) filterRoutes(routes []scanner.RouteInfo) []scanner.RouteInfo {
+	var selected []scanner.RouteInfo
+	for _, route := range routes {
+		if s.contains(route.Position.Filename) {
+			selected = append(selected, route)
+		}
+	}
+	return selected
+}
+
+// filterDuplicates returns the duplicate routes registered in the scope
+func (s *scope) filterDuplicates(duplicates []scanner.DuplicateRoute) []scanner.DuplicateRoute {
+	var selected []scanner.DuplicateRoute
+	for _, duplicate := range duplicates {
+		if s.contains(duplicate.Route.Position.Filename) {
+			selected = append(selected, duplicate)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScopeKeepsPrefixesAppliedOutside(t *testing.T) {
+	// The /v1 group and its middleware are created in main, outside the
+	// selected package
+	root := writeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"example.com/app/api/health"
+	"example.com/app/api/users"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func main() {
+	e := echo.New()
+	v1 := e.Group("/v1", middleware.Logger())
+	users.Register(v1.Group("/users"))
+	health.RegisterHealth(v1)
+	e.Start(":8080")
+}
+`,
+		"api/users/users.go": `package users
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID   string
+	Name string
+}
+
+func Register(g *echo.Group) {
+	g.GET("/:id", GetUser)
+	admin := g.Group("/admin")
+	admin.DELETE("/:id", DeleteUser)
+}
+
+func GetUser(c echo.Context) error {
+	return c.JSON(http.StatusOK, User{ID: c.Param("id")})
+}
+
+func DeleteUser(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+`,
+		"api/health/health.go": `package health
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterHealth(g *echo.Group) {
+	g.GET("/health", Health)
+}
+
+func Health(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+`,
+	})
+
+	for _, pkg := range []string{"api/users", "example.com/app/api/users"} {
+		doc, err := Analyze(Options{RepoPath: root, Packages: []string{pkg}})
+		if err != nil {
+			t.Fatalf("Analyze(%s) = %v", pkg, err)
+		}
+
+		paths := make(map[string][]string)
+		for _, route := range doc.Routes {
+			paths[route.Method+" "+route.Path] = route.Middleware
+		}
+		want := map[string][]string{
+			"GET /v1/users/:id":          {"middleware.Logger"},
+			"DELETE /v1/users/admin/:id": {"middleware.Logger"},
+		}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("routes of %s = %v, want %v", pkg, paths, want)
+		}
+
+		if user := responseType(t, doc, "GetUser", 200); user.Name != "User" {
+			t.Errorf("GetUser response of %s = %s, want User", pkg, user.Name)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// assertPointerTo fails the test unless typ is a pointer to the named struct
+func assertPointerTo(t *testing.T, what string, typ *types.TypeDefinition, name string) {
+	t.Helper()
+
+	if typ == nil || typ.Kind != types.KindPointer || typ.ElementType == nil || typ.ElementType.Name != name || len(typ.ElementType.Fields) == 0 {
+		t.Errorf("%s = %+v, want *%s", what, typ, name)
+	}
+}
+
+func TestNewResolvesToPointer(t *testing.T) {
+	// user := new(User); c.Bind(user); ...; return c.JSON(http.StatusCreated, user)
+	doc := analyzeFixture(t, "enhanced_sample_app.go")
+	assertPointerTo(t, "createUser request body", doc.RequestTypes["createUser"], "User")
+	assertPointerTo(t, "createUser response", responseType(t, doc, "createUser", 201), "User")
+
+	// The same with the address of a composite literal
+	doc = analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func main() {
+	e := echo.New()
+	e.POST("/users", createUser)
+	e.Start(":8080")
+}
+
+func createUser(c echo.Context) error {
+	user := &User{}
+	if err := c.Bind(user); err != nil {
+		return err
+	}
+	user.ID = 123
+	return c.JSON(http.StatusCreated, user)
+}
+`,
+	})
+	assertPointerTo(t, "&User{} request body", doc.RequestTypes["createUser"], "User")
+	assertPointerTo(t, "&User{} response", responseType(t, doc, "createUser", 201), "User")
+}
+
+func TestBranchResponses(t *testing.T) {
+	// resp is an ErrorResponse in the if branch and a User in the else branch
+	doc := analyzeSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	Name string
+}
+
+type ErrorResponse struct {
+	Error string
+}
+
+func main() {
+	e := echo.New()
+	e.GET("/users/:id", getUser)
+	e.Start(":8080")
+}
+
+func getUser(c echo.Context) error {
+	var resp interface{}
+	if c.Param("id") == "" {
+		resp = ErrorResponse{Error: "missing id"}
+		return c.JSON(http.StatusBadRequest, resp)
+	} else {
+		resp = User{Name: c.Param("id")}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+`,
+	})
+	for status, want := range map[int]string{200: "User", 400: "ErrorResponse"} {
+		if typ := responseType(t, doc, "getUser", status); typ.Name != want {
+			t.Errorf("getUser %d response = %s, want %s", status, typ.Name, want)
+		}
+	}
+}
+
+func TestIndexResponses(t *testing.T) {
+	doc := analyzeFixture(t, "enhanced_sample_app.go")
+
+	// users[len(users)-1] with users a []User
+	if typ := responseType(t, doc, "getNewestUser", 200); typ.Kind != types.KindStruct || typ.Name != "User" || len(typ.Fields) == 0 {
+		t.Errorf("getNewestUser response = %+v, want User", typ)
+	}
+
+	// cache[c.QueryParam("id")] with cache a map[string]*User
+	assertPointerTo(t, "lookupUser response", responseType(t, doc, "lookupUser", 200), "User")
+
+	// getUserByID responds with the user itself
+	if typ := responseType(t, doc, "getUserByID", 200); typ.Kind != types.KindStruct || typ.Name != "User" {
+		t.Errorf("getUserByID response = %+v, want User", typ)
+	}
+}
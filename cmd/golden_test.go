@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/golden")
+
+// generatedAt matches the generation timestamp in the header of the output,
+// the only part of it expected to change from run to run
+var generatedAt = regexp.MustCompile(`\w+ \d{1,2}, \d{4} \d{2}:\d{2}:\d{2}|\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+
+// generate analyzes a repository and returns the documentation generated in
+// a format, with the generation timestamp blanked out
+func generate(t *testing.T, repoRoot, format string) []byte {
+	t.Helper()
+
+	result, err := analyze(repoRoot, io.Discard)
+	if err != nil {
+		t.Fatalf("analyze() = %v", err)
+	}
+
+	outputFormat = format
+	outputFile = filepath.Join(t.TempDir(), "output")
+	if err := newDocGenerator(result).Generate(); err != nil {
+		t.Fatalf("Generate() = %v", err)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc := generatedAt.FindIndex(data); loc != nil {
+		data = []byte(string(data[:loc[0]]) + "TIMESTAMP" + string(data[loc[1]:]))
+	}
+	return data
+}
+
+// TestOutputIsDeterministic runs the analysis and generation twice on the
+// enhanced sample application and checks the output is byte-identical, and
+// with --sort also identical to the golden files. Run with -update to
+// rewrite them.
+func TestOutputIsDeterministic(t *testing.T) {
+	repoRoot := t.TempDir()
+	source, err := os.ReadFile(filepath.Join("..", "testdata", "enhanced_sample_app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "main.go"), source, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(format, file string, sorted, cache bool) {
+		outputFormat, outputFile, sortOutput, noCache = format, file, sorted, cache
+	}(outputFormat, outputFile, sortOutput, noCache)
+	noCache = true
+
+	for _, format := range []string{"markdown", "json", "openapi", "typescript", "go-client"} {
+		for _, sorted := range []bool{false, true} {
+			sortOutput = sorted
+			first := generate(t, repoRoot, format)
+			if second := generate(t, repoRoot, format); string(first) != string(second) {
+				t.Errorf("%s output (sort %v) differs between runs:\n%s\n---\n%s", format, sorted, first, second)
+			}
+			if !sorted {
+				continue
+			}
+
+			golden := filepath.Join("testdata", "golden", "enhanced_sample_app."+format+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, first, 0644); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if string(first) != string(want) {
+				t.Errorf("%s output differs from %s (run with -update if the change is intended):\n%s", format, golden, first)
+			}
+		}
+	}
+}
@@ -3,196 +3,472 @@ package main
 import (
 	"flag"
 	"fmt"
-	"go/ast"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
-	"github.com/user/golang-echo-analyzer/internal/analyzer"
-	"github.com/user/golang-echo-analyzer/internal/aws"
+	"github.com/user/golang-echo-analyzer/analyzer"
 	"github.com/user/golang-echo-analyzer/internal/generator"
-	"github.com/user/golang-echo-analyzer/internal/parser"
-	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/logger"
 	"github.com/user/golang-echo-analyzer/internal/types"
 )
 
+// stringSliceFlag collects the values of a flag that can be repeated
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 // Command line flags
 var (
-	repoPath     string
-	outputFile   string
-	outputFormat string
-	verbose      bool
+	repoPaths          stringSliceFlag
+	outputFile         string
+	outputFormat       string
+	verbose            bool
+	noRequired         bool
+	registrarMethods   stringSliceFlag
+	typeMappings       stringSliceFlag
+	noCache            bool
+	nullablePointers   bool
+	tsClient           bool
+	clientPackage      string
+	includePaths       stringSliceFlag
+	excludePaths       stringSliceFlag
+	excludeDirs        stringSliceFlag
+	scopePackages      stringSliceFlag
+	scopeFiles         stringSliceFlag
+	detectTimeouts     bool
+	lintPathParams     string
+	baselinePath       string
+	failOnParseError   bool
+	reportGaps         bool
+	reportPath         string
+	envelope           string
+	strict             bool
+	logLevel           string
+	showProgress       bool
+	errorType          string
+	defaultQuery       string
+	schemaOnly         bool
+	basePath           string
+	stripPrefix        string
+	securityMiddleware stringSliceFlag
+	jsonTagKeys        stringSliceFlag
+	schemaDraft        string
+	watch              bool
+	sortOutput         bool
+	jsonIndent         string
+	wildcardParam      string
+	framework          string
+	exampleItems       int
+	formatRules        stringSliceFlag
+	noFormatInference  bool
+	skipValidation     bool
 )
 
+// Default outputs of the documentation and of --schema-only
+const (
+	defaultOutputFile   = "api-docs.md"
+	defaultSchemaOutput = "schemas"
+)
+
+// log writes the messages of the tool at the level given by --log-level
+var log *logger.Logger
+
 func init() {
-	flag.StringVar(&repoPath, "repo", ".", "Path to the repository to analyze")
-	flag.StringVar(&outputFile, "output", "api-docs.md", "Output file for the API documentation")
-	flag.StringVar(&outputFormat, "format", "markdown", "Output format (markdown, json, openapi)")
-	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
-	flag.Parse()
+	flag.Var(&repoPaths, "repo", "Path to a repository to analyze; repeat it or separate paths with commas to merge several repositories into one document (default: .)")
+	flag.StringVar(&outputFile, "output", defaultOutputFile, "Output file for the API documentation, or - for stdout")
+	flag.StringVar(&outputFormat, "format", "markdown", "Output format (markdown, json, openapi, typescript, go-client)")
+	flag.StringVar(&framework, "framework", analyzer.FrameworkEcho, "Framework routes are registered with (echo, or nethttp for net/http's ServeMux)")
+	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output (same as --log-level debug)")
+	flag.StringVar(&logLevel, "log-level", "info", "Log level (error, warn, info, debug)")
+	flag.BoolVar(&showProgress, "progress", false, "Show the number of files parsed, packages collected and handlers analyzed")
+	flag.StringVar(&basePath, "base-path", "", "Prefix prepended to every route path in the output, e.g. /api/v2 for an application mounted there by a gateway")
+	flag.StringVar(&stripPrefix, "strip-prefix", "", "Prefix removed from the start of the route paths it matches in the output, applied before --base-path")
+	flag.StringVar(&wildcardParam, "wildcard-param", generator.WildcardParamName, "Name of the path parameter documenting Echo's unnamed * wildcard, e.g. filepath for /files/{filepath}")
+	flag.BoolVar(&sortOutput, "sort", false, "Sort routes by path and method, parameters by name and responses by status code instead of keeping source order")
+	flag.StringVar(&jsonIndent, "json-indent", "2", "Indentation of JSON output: a number of spaces, tab, or compact for no whitespace")
+	flag.StringVar(&schemaDraft, "schema-draft", "", "JSON Schema draft of generated schemas (draft-07 or 2020-12); with openapi output, writes OpenAPI 3.1 declaring it (default: 2020-12 schema documents and OpenAPI 3.0)")
+	flag.BoolVar(&skipValidation, "skip-validation", false, "Write OpenAPI output without checking it for missing fields, dangling $refs and duplicate operationIds")
+	flag.BoolVar(&noRequired, "no-required", false, "Omit required arrays from generated schemas")
+	flag.StringVar(&clientPackage, "client-package", generator.DefaultClientPackage, "Package name of the generated Go client")
+	flag.BoolVar(&tsClient, "ts-client", false, "Include a typed client function per endpoint in TypeScript output")
+	flag.BoolVar(&nullablePointers, "nullable-pointers", true, "Mark pointer fields as nullable in generated schemas")
+	flag.IntVar(&exampleItems, "example-items", types.DefaultExampleArrayLength, "Number of elements of arrays in example responses, with values varying by element")
+	flag.BoolVar(&noCache, "no-cache", false, "Disable the parse cache and reparse every file")
+	flag.BoolVar(&failOnParseError, "fail-on-parse-error", false, "Stop if any file can't be parsed instead of skipping it with a warning")
+	flag.Var(&includePaths, "include-path", "Only document routes whose path matches this glob (repeatable)")
+	flag.Var(&excludePaths, "exclude-path", "Skip routes whose path matches this glob (repeatable)")
+	flag.Var(&excludeDirs, "exclude-dir", "Skip directories matching this glob while parsing (repeatable)")
+	flag.Var(&scopePackages, "package", "Only document the routes and events of this package, by import path or directory relative to the repository; the packages it imports are still parsed to resolve types (repeatable)")
+	flag.Var(&scopeFiles, "file", "Only document the routes and events of this Go file, relative to the repository (repeatable)")
+	flag.StringVar(&defaultQuery, "default-query-helper", "", "Name of a helper reading a query parameter with a default value, called as helper(c, \"name\", \"default\")")
+	flag.BoolVar(&detectTimeouts, "detect-timeouts", false, "Note context timeouts applied to the request context by handlers")
+	flag.StringVar(&lintPathParams, "lint-path-params", "", "Report path parameters not following a naming convention (camelCase, snake_case, kebab-case, lowercase or a regexp)")
+	flag.BoolVar(&strict, "strict", false, "Fail on routing errors, such as a method and path registered twice, instead of warning")
+	flag.BoolVar(&reportGaps, "report-gaps", false, "Print the routes, request bodies and responses the analysis couldn't fully resolve instead of writing documentation")
+	flag.StringVar(&reportPath, "report", "", "Also write a JSON report of parse errors, duplicate routes, missing handlers, unresolved types and documentation gaps to this file, for CI")
+	flag.BoolVar(&schemaOnly, "schema-only", false, "Write a JSON Schema document for every named struct instead of documentation, to the --output directory (default: schemas) or a single .json file")
+	flag.StringVar(&baselinePath, "baseline", "", "Compare against the repository at this path and write an API diff report instead of documentation")
+	flag.Var(&registrarMethods, "registrar-method", "Custom route registration method as Name:methodArg:pathArg:handlerArg (repeatable)")
+	flag.StringVar(&errorType, "error-type", "", "Type of error responses, referenced from every error response of the OpenAPI output (default: the type of most 4xx and 5xx responses)")
+	flag.StringVar(&envelope, "envelope", "", "Response wrapper type as Type.Field, e.g. Envelope.Data; the field is documented with each response's payload")
+	flag.Var(&securityMiddleware, "security-middleware", "Middleware constructor enforcing security as Name=kind, where kind is bearer, basic, apiKey or rateLimit, e.g. auth.RequireUser=bearer (repeatable)")
+	flag.Var(&jsonTagKeys, "json-tag", "Struct tag key naming fields in JSON, in priority order, e.g. json then easyjson (repeatable; default: json)")
+	flag.BoolVar(&watch, "watch", false, "Keep running and regenerate the output whenever a .go file under the repository changes")
+	flag.Var(&formatRules, "format-rule", "Format of fields whose name matches a regexp, as pattern=format[:type] with type string by default, e.g. Phone$=phone (repeatable)")
+	flag.BoolVar(&noFormatInference, "no-format-inference", false, "Don't infer formats such as email, uri or date-time from field names")
+	flag.Var(&typeMappings, "type-mapping", "Schema of an external type as Name=type[:format], e.g. uuid.UUID=string:uuid (repeatable)")
 }
 
 func main() {
-	// Validate repository path
-	absPath, err := filepath.Abs(repoPath)
+	flag.Parse()
+
+	// --verbose is a shorthand for debug logging, which enables the
+	// detailed output of every analysis step
+	level, err := logger.ParseLevel(logLevel)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error resolving repository path: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if verbose {
+		level = logger.LevelDebug
+	}
+	verbose = level == logger.LevelDebug
+
+	// When the output goes to stdout, messages go to stderr so the output
+	// can be piped
+	out := io.Writer(os.Stdout)
+	if outputFile == generator.StdoutOutput {
+		out = os.Stderr
+	}
+	log = logger.New(out, os.Stderr, level)
+
+	if schemaDraft != "" {
+		if _, err := types.SchemaDialect(schemaDraft); err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
 
-	// Check if the path exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Repository path does not exist: %s\n", absPath)
+	indent, err := generator.ParseJSONIndent(jsonIndent)
+	if err != nil {
+		log.Errorf("%v", err)
 		os.Exit(1)
 	}
+	generator.JSONIndent = indent
 
-	// Print banner
-	printBanner()
+	if exampleItems < 1 {
+		log.Errorf("--example-items must be at least 1, got %d", exampleItems)
+		os.Exit(1)
+	}
 
-	// Print configuration
-	fmt.Println("Configuration:")
-	fmt.Printf("  Repository path: %s\n", absPath)
-	fmt.Printf("  Output file: %s\n", outputFile)
-	fmt.Printf("  Output format: %s\n", outputFormat)
-	fmt.Printf("  Verbose mode: %v\n", verbose)
-	fmt.Println()
-
-	// 1. Parse Go source files
-	fmt.Println("Step 1: Parsing Go source files...")
-	codeParser := parser.NewCodeParser(absPath, verbose)
-	if err := codeParser.Parse(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing repository: %v\n", err)
+	if framework != analyzer.FrameworkEcho && framework != analyzer.FrameworkNetHTTP {
+		log.Errorf("unsupported framework %q, expected %s or %s", framework, analyzer.FrameworkEcho, analyzer.FrameworkNetHTTP)
 		os.Exit(1)
 	}
-	fmt.Println("  Parsing completed successfully.")
 
-	// 2. Initialize type registry and collector
-	fmt.Println("Step 2: Initializing type resolution system...")
-	typeRegistry := types.NewTypeRegistry(codeParser.FileSet, verbose)
-	typeCollector := types.NewTypeCollector(typeRegistry, verbose)
+	if wildcardParam == "" || strings.ContainsAny(wildcardParam, "/{}:*") {
+		log.Errorf("invalid wildcard parameter name %q", wildcardParam)
+		os.Exit(1)
+	}
+	generator.WildcardParamName = wildcardParam
+
+	// Validate repository paths
+	absPaths := []string{}
+	for _, value := range repoPaths {
+		for _, repoPath := range strings.Split(value, ",") {
+			if repoPath = strings.TrimSpace(repoPath); repoPath == "" {
+				continue
+			}
+			absPath, err := filepath.Abs(repoPath)
+			if err != nil {
+				log.Errorf("resolving repository path: %v", err)
+				os.Exit(1)
+			}
 
-	// Collect types from all packages
-	for pkgPath, pkg := range codeParser.Packages {
-		files := make([]*ast.File, 0, len(pkg.Files))
-		for _, file := range pkg.Files {
-			files = append(files, file)
+			// Check if the path exists
+			if _, err := os.Stat(absPath); os.IsNotExist(err) {
+				log.Errorf("repository path does not exist: %s", absPath)
+				os.Exit(1)
+			}
+			absPaths = append(absPaths, absPath)
 		}
-		if err := typeCollector.CollectTypes(files, pkgPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error collecting types from package %s: %v\n", pkgPath, err)
+	}
+	if len(absPaths) == 0 {
+		absPath, err := filepath.Abs(".")
+		if err != nil {
+			log.Errorf("resolving repository path: %v", err)
+			os.Exit(1)
 		}
+		absPaths = append(absPaths, absPath)
+	}
+	if len(absPaths) > 1 && baselinePath != "" {
+		log.Errorf("--baseline compares a single repository, but %d were given", len(absPaths))
+		os.Exit(1)
+	}
+	if len(absPaths) > 1 && schemaOnly {
+		log.Errorf("--schema-only writes the schemas of a single repository, but %d were given", len(absPaths))
+		os.Exit(1)
 	}
 
-	// Resolve types
-	if err := typeCollector.ResolveTypes(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error resolving types: %v\n", err)
+	if watch && (baselinePath != "" || reportGaps) {
+		log.Errorf("--watch regenerates documentation or schemas and can't be combined with --baseline or --report-gaps")
+		os.Exit(1)
 	}
 
-	// 3. Initialize package resolver
-	packageResolver := types.NewPackageResolver(typeRegistry, absPath, verbose)
-	if err := packageResolver.ResolvePackages(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error resolving packages: %v\n", err)
+	// Schemas are written to a directory unless an output file is given
+	if schemaOnly && outputFile == defaultOutputFile {
+		outputFile = defaultSchemaOutput
 	}
 
-	// 4. Initialize struct field analyzer
-	fieldAnalyzer := types.NewStructFieldAnalyzer(typeRegistry, verbose)
-	if err := fieldAnalyzer.AnalyzeStructFields(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error analyzing struct fields: %v\n", err)
+	// Print banner
+	if log.Enabled(logger.LevelInfo) {
+		printBanner()
 	}
 
-	// Analyze nested structs
-	fieldAnalyzer.AnalyzeNestedStructs()
+	// Print configuration
+	log.Infof("Configuration:")
+	for _, absPath := range absPaths {
+		log.Infof("  Repository path: %s", absPath)
+	}
+	log.Infof("  Output file: %s", outputFile)
+	log.Infof("  Output format: %s", outputFormat)
+	log.Infof("  Log level: %s", logLevel)
+	log.Infof("")
+
+	if watch {
+		if err := watchRepositories(absPaths); err != nil {
+			log.Errorf("watching repositories: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	fmt.Println("  Type resolution system initialized successfully.")
+	// Analyze each repository, merging their results into one document, and
+	// the baseline revision in diff mode
+	results := []*analyzer.APIDocument{}
+	for _, absPath := range absPaths {
+		if len(absPaths) > 1 {
+			log.Infof("Analyzing repository: %s", absPath)
+		}
+		results = append(results, analyzeRepository(absPath))
+	}
+	result := analyzer.Merge(results)
+	printWarnings(result)
+	if reportPath != "" {
+		if err := generator.WriteJSON(reportPath, result.Report()); err != nil {
+			log.Errorf("writing report: %v", err)
+			os.Exit(1)
+		}
+		log.Infof("  Report written: %s", reportPath)
+	}
+	if schemaOnly {
+		log.Infof("Step 7: Generating JSON Schemas...")
+		count, err := generator.WriteSchemas(outputFile, result.TypeRegistry, newSchemaGenerator(result))
+		if err != nil {
+			log.Errorf("generating schemas: %v", err)
+			os.Exit(1)
+		}
+		log.Infof("  Wrote %d schemas: %s", count, outputFile)
+		log.Infof("\nAnalysis completed successfully!")
+		return
+	}
+	if reportGaps {
+		printGaps(result.Gaps())
+		log.Infof("\nAnalysis completed successfully!")
+		return
+	}
+	if baselinePath != "" {
+		baselineAbs, err := filepath.Abs(baselinePath)
+		if err != nil {
+			log.Errorf("resolving baseline path: %v", err)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(baselineAbs); os.IsNotExist(err) {
+			log.Errorf("baseline path does not exist: %s", baselineAbs)
+			os.Exit(1)
+		}
 
-	// 5. Scan for Echo route definitions
-	fmt.Println("Step 3: Scanning for Echo route definitions...")
-	routeScanner := scanner.NewRouteScanner(codeParser.FileSet, verbose)
-	if err := routeScanner.Scan(codeParser.GetAllFiles()); err != nil {
-		fmt.Fprintf(os.Stderr, "Error scanning for routes: %v\n", err)
-		os.Exit(1)
+		log.Infof("\nAnalyzing baseline: %s", baselineAbs)
+		baseline := analyzeRepository(baselineAbs)
+		printWarnings(baseline)
+
+		// Compare the two revisions instead of generating documentation
+		log.Infof("Step 7: Comparing API revisions...")
+		changes := generator.DiffAPIDocuments(
+			newDocGenerator(baseline).APIDocument(),
+			newDocGenerator(result).APIDocument(),
+		)
+		if err := generator.WriteDiffMarkdown(outputFile, baselineAbs, absPaths[0], changes); err != nil {
+			log.Errorf("generating diff report: %v", err)
+			os.Exit(1)
+		}
+		log.Infof("  Found %d API changes. Diff report generated: %s", len(changes), outputFile)
+
+		if generator.HasBreakingChanges(changes) {
+			log.Errorf("breaking API changes detected")
+			os.Exit(2)
+		}
+		log.Infof("\nAnalysis completed successfully!")
+		return
 	}
-	routes := routeScanner.GetRoutes()
-	fmt.Printf("  Found %d routes.\n", len(routes))
 
-	// 6. Analyze handler functions
-	fmt.Println("Step 4: Analyzing handler functions...")
-	handlerAnalyzer := analyzer.NewHandlerAnalyzer(codeParser.FileSet, verbose)
-	if err := handlerAnalyzer.Analyze(codeParser.GetAllFiles(), routes); err != nil {
-		fmt.Fprintf(os.Stderr, "Error analyzing handlers: %v\n", err)
+	// 9. Generate documentation
+	log.Infof("Step 7: Generating documentation...")
+	docGenerator := newDocGenerator(result)
+	if err := docGenerator.Generate(); err != nil {
+		log.Errorf("generating documentation: %v", err)
 		os.Exit(1)
 	}
-	handlers := handlerAnalyzer.GetHandlers()
-	fmt.Printf("  Analyzed %d handlers.\n", len(handlers))
-
-	// 7. Analyze response types
-	fmt.Println("Step 5: Analyzing response types...")
-	responseTypes := make(map[string]*types.ResponseInfo)
-
-	// For each handler function
-	for handlerName, _ := range handlers {
-		// Initialize variable tracker
-		variableTracker := types.NewVariableTracker(typeRegistry, verbose)
-
-		// Find the handler function in the AST
-		for _, file := range codeParser.GetAllFiles() {
-			for _, decl := range file.Decls {
-				if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-					if funcDecl.Name.Name == handlerName {
-						// Track variables in the function
-						if err := variableTracker.TrackFunction(funcDecl); err != nil {
-							fmt.Fprintf(os.Stderr, "Error tracking variables in handler %s: %v\n", handlerName, err)
-							continue
-						}
-
-						// Analyze responses
-						responseAnalyzer := types.NewResponseAnalyzer(typeRegistry, variableTracker, verbose)
-						if err := responseAnalyzer.AnalyzeHandler(funcDecl); err != nil {
-							fmt.Fprintf(os.Stderr, "Error analyzing responses in handler %s: %v\n", handlerName, err)
-							continue
-						}
-
-						// Store response types
-						for _, response := range responseAnalyzer.GetResponses() {
-							responseKey := fmt.Sprintf("%s_%d", handlerName, response.StatusCode)
-							responseTypes[responseKey] = response
-						}
-					}
-				}
-			}
-		}
-	}
+	log.Infof("  Documentation generated: %s", outputFile)
 
-	fmt.Printf("  Analyzed %d response types.\n", len(responseTypes))
+	log.Infof("\nAnalysis completed successfully!")
+}
 
-	// 8. Scan for AWS SDK usage
-	fmt.Println("Step 6: Analyzing AWS SDK usage...")
-	awsAnalyzer := aws.NewAWSAnalyzer(codeParser.FileSet, verbose)
-	if err := awsAnalyzer.Analyze(codeParser.GetAllFiles()); err != nil {
-		fmt.Fprintf(os.Stderr, "Error analyzing AWS SDK usage: %v\n", err)
+// analyzeRepository runs every analysis step on a repository, exiting on errors
+func analyzeRepository(repoRoot string) *analyzer.APIDocument {
+	result, err := analyze(repoRoot, log.Writer(logger.LevelInfo))
+	if err != nil {
+		log.Errorf("%v", err)
 		os.Exit(1)
 	}
-	events := awsAnalyzer.GetEvents()
-	fmt.Printf("  Found %d AWS events.\n", len(events))
 
-	// 9. Generate documentation
-	fmt.Println("Step 7: Generating documentation...")
+	return result
+}
 
-	// Initialize schema generator
-	schemaGenerator := types.NewSchemaGenerator(typeRegistry, verbose)
+// analyze runs every analysis step on a repository, logging the steps to w
+func analyze(repoRoot string, w io.Writer) (*analyzer.APIDocument, error) {
+	return analyzer.Analyze(analyzer.Options{
+		RepoPath:            repoRoot,
+		Framework:           framework,
+		Verbose:             verbose,
+		ExcludeDirs:         excludeDirs,
+		Packages:            scopePackages,
+		Files:               scopeFiles,
+		IncludePaths:        includePaths,
+		ExcludePaths:        excludePaths,
+		RegistrarMethods:    registrarMethods,
+		DetectTimeouts:      detectTimeouts,
+		DefaultQueryHelper:  defaultQuery,
+		Envelope:            envelope,
+		JSONTagKeys:         jsonTagKeys,
+		Strict:              strict,
+		PathParamConvention: lintPathParams,
+		Cache:               !noCache,
+		FailOnParseError:    failOnParseError,
+		Log:                 w,
+		Progress:            progress,
+	})
+}
+
+// printWarnings prints the errors that didn't stop an analysis
+func printWarnings(result *analyzer.APIDocument) {
+	for _, warning := range result.Warnings {
+		log.Warnf("%s", warning)
+	}
+}
+
+// newSchemaGenerator creates a schema generator for the types of an analysis
+func newSchemaGenerator(result *analyzer.APIDocument) *types.SchemaGenerator {
+	schemaGenerator := types.NewSchemaGenerator(result.TypeRegistry, log)
+	schemaGenerator.OmitRequired = noRequired
+	schemaGenerator.NullablePointers = nullablePointers
+	schemaGenerator.Draft = schemaDraft
+	schemaGenerator.ExampleArrayLength = exampleItems
+	if noFormatInference {
+		schemaGenerator.FormatRules = nil
+	}
+	for _, spec := range formatRules {
+		rule, err := types.ParseFormatRule(spec)
+		if err != nil {
+			log.Errorf("parsing format rule: %v", err)
+			os.Exit(1)
+		}
+		schemaGenerator.AddFormatRule(rule)
+	}
+	for _, spec := range typeMappings {
+		name, wellKnown, err := types.ParseTypeMapping(spec)
+		if err != nil {
+			log.Errorf("parsing type mapping: %v", err)
+			os.Exit(1)
+		}
+		schemaGenerator.RegisterWellKnownType(name, wellKnown)
+	}
+
+	return schemaGenerator
+}
+
+// newDocGenerator creates a documentation generator for the results of an analysis
+func newDocGenerator(result *analyzer.APIDocument) *generator.DocGenerator {
+	schemaGenerator := newSchemaGenerator(result)
 
 	// Initialize documentation generator
-	docGenerator := generator.NewDocGenerator(outputFile, outputFormat, verbose)
-	docGenerator.SetData(routes, handlers, events)
+	docGenerator := generator.NewDocGenerator(outputFile, outputFormat, log)
+	docGenerator.SetData(result.Routes, result.Handlers, result.Events)
+	docGenerator.SetPathRewrite(stripPrefix, basePath)
+	docGenerator.SetMiddleware(result.Middleware)
+	docGenerator.SetErrorHandler(result.ErrorHandler)
+	if sortOutput {
+		docGenerator.Sort()
+	}
 	docGenerator.SetSchemaGenerator(schemaGenerator)
-	docGenerator.SetResponseTypes(responseTypes)
+	docGenerator.SetResponseTypes(result.ResponseTypes)
+	docGenerator.SetRequestTypes(result.RequestTypes)
+	docGenerator.TypeScriptClient = tsClient
+	docGenerator.ClientPackage = clientPackage
+	docGenerator.RepoRoot = result.RepoRoot
+	docGenerator.ErrorType = errorType
+	docGenerator.SchemaDraft = schemaDraft
+	docGenerator.SkipValidation = skipValidation
+	for _, spec := range securityMiddleware {
+		name, kind, err := generator.ParseSecurityMiddleware(spec)
+		if err != nil {
+			log.Errorf("parsing security middleware: %v", err)
+			os.Exit(1)
+		}
+		docGenerator.RegisterSecurityMiddleware(name, kind)
+	}
 
-	if err := docGenerator.Generate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating documentation: %v\n", err)
-		os.Exit(1)
+	return docGenerator
+}
+
+// progress reports the progress of an analysis step if --progress is set
+func progress(step string, done, total int) {
+	if showProgress {
+		log.Progress(step, done, total)
+	}
+}
+
+// printGaps prints a summary of the documentation gaps, grouped by kind
+func printGaps(gaps []analyzer.Gap) {
+	log.Infof("Step 7: Checking documentation completeness...")
+	if len(gaps) == 0 {
+		log.Infof("  No documentation gaps found.")
+		return
 	}
-	fmt.Printf("  Documentation generated: %s\n", outputFile)
 
-	fmt.Println("\nAnalysis completed successfully!")
+	w := log.Writer(logger.LevelInfo)
+
+	byKind := make(map[analyzer.GapKind][]analyzer.Gap)
+	for _, gap := range gaps {
+		byKind[gap.Kind] = append(byKind[gap.Kind], gap)
+	}
+	for _, kind := range analyzer.GapKinds {
+		fmt.Fprintf(w, "\n  %s: %d\n", kind, len(byKind[kind]))
+		for _, gap := range byKind[kind] {
+			fmt.Fprintf(w, "    %s  %s: %s\n", gap.Location, gap.Route, gap.Detail)
+		}
+	}
+	fmt.Fprintf(w, "\n  Found %d documentation gaps.\n", len(gaps))
 }
 
 // printBanner prints a fancy banner for the tool
@@ -201,12 +477,13 @@ func printBanner() {
 	cyan := color.New(color.FgCyan).SprintFunc()
 	green := color.New(color.FgGreen).SprintFunc()
 
-	fmt.Println()
-	fmt.Println(bold(cyan("┌─────────────────────────────────────────────┐")))
-	fmt.Println(bold(cyan("│ ")) + bold(green(" Echo Framework Static Analyzer ")) + bold(cyan("            │")))
-	fmt.Println(bold(cyan("│ ")) + "                                             " + bold(cyan("│")))
-	fmt.Println(bold(cyan("│ ")) + " Automatically generate API documentation    " + bold(cyan("│")))
-	fmt.Println(bold(cyan("│ ")) + " with detailed JSON response schemas         " + bold(cyan("│")))
-	fmt.Println(bold(cyan("└─────────────────────────────────────────────┘")))
-	fmt.Println()
+	w := log.Writer(logger.LevelInfo)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, bold(cyan("┌─────────────────────────────────────────────┐")))
+	fmt.Fprintln(w, bold(cyan("│ "))+bold(green(" Echo Framework Static Analyzer "))+bold(cyan("            │")))
+	fmt.Fprintln(w, bold(cyan("│ "))+"                                             "+bold(cyan("│")))
+	fmt.Fprintln(w, bold(cyan("│ "))+" Automatically generate API documentation    "+bold(cyan("│")))
+	fmt.Fprintln(w, bold(cyan("│ "))+" with detailed JSON response schemas         "+bold(cyan("│")))
+	fmt.Fprintln(w, bold(cyan("└─────────────────────────────────────────────┘")))
+	fmt.Fprintln(w)
 }
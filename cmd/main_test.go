@@ -0,0 +1,55 @@
+package main
+
+import (
+	"go/build"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSingleEntryPoint checks that go build ./... builds a single binary:
+// cmd is the only main package of the module, in the directories the go
+// tool builds
+func TestSingleEntryPoint(t *testing.T) {
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mains []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		// The go tool skips testdata and directories starting with . or _
+		name := d.Name()
+		if path != root && (name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+
+		pkg, err := build.Default.ImportDir(path, 0)
+		if err != nil {
+			if _, ok := err.(*build.NoGoError); ok {
+				return nil
+			}
+			return err
+		}
+		if pkg.Name == "main" {
+			rel, _ := filepath.Rel(root, path)
+			mains = append(mains, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mains) != 1 || mains[0] != "cmd" {
+		t.Errorf("main packages = %v, want [cmd]", mains)
+	}
+}
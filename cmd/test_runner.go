@@ -1,636 +0,0 @@
-package main
-
-import (
-	"fmt"
-	"os"
-	"path/filepath"
-)
-
-// TestRunner runs tests for the enhanced static analyzer
-func main() {
-	fmt.Println("Running tests for the enhanced static analyzer...")
-
-	// Create test directory if it doesn't exist
-	testDir := filepath.Join("/home/ubuntu/golang-echo-analyzer/test_output")
-	if err := os.MkdirAll(testDir, 0755); err != nil {
-		fmt.Printf("Error creating test directory: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Test with different output formats
-	testFormats := []string{"markdown", "openapi"}
-	for _, format := range testFormats {
-		outputFile := filepath.Join(testDir, fmt.Sprintf("api-docs.%s", getExtension(format)))
-
-		fmt.Printf("\nTesting with format: %s\n", format)
-		fmt.Printf("Output file: %s\n", outputFile)
-
-		// Run the analyzer
-		cmd := fmt.Sprintf("cd /home/ubuntu/golang-echo-analyzer && go run src/main.go --repo ./test --output %s --format %s --verbose",
-			outputFile, format)
-
-		fmt.Printf("Running command: %s\n", cmd)
-		fmt.Println("This would execute the analyzer on the test directory.")
-		fmt.Println("Since we can't compile and run Go code in this environment, this is a simulation.")
-
-		// In a real environment, we would execute the command and check the results
-		fmt.Println("In a real environment, the analyzer would:")
-		fmt.Println("1. Parse the Go source files in the test directory")
-		fmt.Println("2. Collect and resolve types across packages")
-		fmt.Println("3. Analyze struct fields and nested types")
-		fmt.Println("4. Scan for Echo route definitions")
-		fmt.Println("5. Analyze handler functions and their response types")
-		fmt.Println("6. Generate API documentation with JSON schemas")
-
-		// Simulate the expected output
-		simulateOutput(format, outputFile)
-	}
-
-	fmt.Println("\nTest completed. In a real environment, you would:")
-	fmt.Println("1. Verify that the generated documentation correctly identifies all routes")
-	fmt.Println("2. Verify that the JSON schemas match the struct definitions")
-	fmt.Println("3. Verify that nested structs, arrays, and maps are correctly documented")
-	fmt.Println("4. Verify that the OpenAPI specification is valid and complete")
-}
-
-// getExtension returns the file extension for a given format
-func getExtension(format string) string {
-	switch format {
-	case "markdown":
-		return "md"
-	case "openapi":
-		return "json"
-	case "json":
-		return "json"
-	default:
-		return "txt"
-	}
-}
-
-// simulateOutput simulates the expected output for a given format
-func simulateOutput(format, outputFile string) {
-	var content string
-
-	switch format {
-	case "markdown":
-		content = `# API Documentation
-
-*Generated at: April 23, 2025 01:32:15*
-
-## Endpoints
-
-| Method | Path | Handler | Description |
-|--------|------|---------|-------------|
-| GET | / | helloWorld | |
-| GET | /users | getUsers | |
-| GET | /users/:id | getUserByID | |
-| POST | /users | createUser | |
-| PUT | /users/:id | updateUser | |
-| DELETE | /users/:id | deleteUser | |
-| GET | /products | getProducts | |
-| GET | /products/:id | getProductByID | |
-| POST | /products | createProduct | |
-| PUT | /products/:id | updateProduct | |
-| GET | /orders | getOrders | |
-| GET | /orders/:id | getOrderByID | |
-| POST | /orders | createOrder | |
-| PUT | /orders/:id/status | updateOrderStatus | |
-
-## Detailed Endpoint Documentation
-
-### GET /users
-
-**Handler:** getUsers
-
-#### Request Parameters
-
-| Type | Name | Data Type | Required | Description |
-|------|------|-----------|----------|-------------|
-| Query | limit | string | false | Maximum number of users to return |
-| Query | offset | string | false | Number of users to skip |
-
-#### Response
-
-| Type | Status Code | Data Type | Description |
-|------|------------|-----------|-------------|
-| JSON | 200 | []User | List of users |
-
-**JSON Schema:**
-
-` + "```json" + `
-{
-  "type": "array",
-  "items": {
-    "type": "object",
-    "properties": {
-      "id": {
-        "type": "integer"
-      },
-      "name": {
-        "type": "string"
-      },
-      "email": {
-        "type": "string"
-      },
-      "created_at": {
-        "type": "string",
-        "format": "date-time"
-      },
-      "profile": {
-        "type": "object",
-        "properties": {
-          "bio": {
-            "type": "string"
-          },
-          "skills": {
-            "type": "array",
-            "items": {
-              "type": "string"
-            }
-          }
-        },
-        "required": ["skills"]
-      }
-    },
-    "required": ["id", "name", "created_at"]
-  }
-}
-` + "```" + `
-
-**Example Response:**
-
-` + "```json" + `
-[
-  {
-    "id": 1,
-    "name": "John Doe",
-    "email": "john@example.com",
-    "created_at": "2025-04-23T01:32:15Z",
-    "profile": {
-      "bio": "Software Engineer",
-      "skills": ["Go", "JavaScript", "Docker"]
-    }
-  },
-  {
-    "id": 2,
-    "name": "Jane Smith",
-    "email": "jane@example.com",
-    "created_at": "2025-04-23T01:32:15Z"
-  }
-]
-` + "```" + `
-
-### GET /products
-
-**Handler:** getProducts
-
-#### Request Parameters
-
-| Type | Name | Data Type | Required | Description |
-|------|------|-----------|----------|-------------|
-| Query | category | string | false | Filter products by category |
-
-#### Response
-
-| Type | Status Code | Data Type | Description |
-|------|------------|-----------|-------------|
-| JSON | 200 | []Product | List of products |
-
-**JSON Schema:**
-
-` + "```json" + `
-{
-  "type": "array",
-  "items": {
-    "type": "object",
-    "properties": {
-      "id": {
-        "type": "integer"
-      },
-      "name": {
-        "type": "string"
-      },
-      "description": {
-        "type": "string"
-      },
-      "price": {
-        "type": "number"
-      },
-      "categories": {
-        "type": "array",
-        "items": {
-          "type": "string"
-        }
-      },
-      "attributes": {
-        "type": "object",
-        "additionalProperties": {
-          "type": "string"
-        }
-      },
-      "inventory": {
-        "type": "object",
-        "properties": {
-          "quantity": {
-            "type": "integer"
-          },
-          "available": {
-            "type": "boolean"
-          }
-        },
-        "required": ["quantity", "available"]
-      }
-    },
-    "required": ["id", "name", "price", "categories"]
-  }
-}
-` + "```" + `
-
-**Example Response:**
-
-` + "```json" + `
-[
-  {
-    "id": 1,
-    "name": "Product 1",
-    "description": "This is product 1",
-    "price": 19.99,
-    "categories": ["Electronics", "Gadgets"],
-    "attributes": {
-      "color": "black",
-      "size": "medium"
-    },
-    "inventory": {
-      "quantity": 100,
-      "available": true
-    }
-  },
-  {
-    "id": 2,
-    "name": "Product 2",
-    "description": "This is product 2",
-    "price": 29.99,
-    "categories": ["Home", "Kitchen"]
-  }
-]
-` + "```" + `
-
-## AWS Events
-
-| Service | Operation | Topic/Queue | Message Format |
-|---------|-----------|-------------|----------------|
-| SNS | Publish | arn:aws:sns:us-east-1:123456789012:product-events | Structured |
-| SNS | Publish | arn:aws:sns:us-east-1:123456789012:order-events | Structured |
-
-### Detailed Event Documentation
-
-#### SNS Publish to arn:aws:sns:us-east-1:123456789012:product-events
-
-**Message Fields:**
-
-| Field | Type | Description |
-|-------|------|-------------|
-| event | String | Event type |
-| product | Product | Product data |
-
-#### SNS Publish to arn:aws:sns:us-east-1:123456789012:order-events
-
-**Message Fields:**
-
-| Field | Type | Description |
-|-------|------|-------------|
-| event | String | Event type |
-| order | Order | Order data |
-`
-	case "openapi":
-		content = `{
-  "openapi": "3.0.0",
-  "info": {
-    "title": "API Documentation",
-    "description": "Generated by Echo Framework Static Analyzer",
-    "version": "1.0.0"
-  },
-  "servers": [
-    {
-      "url": "/",
-      "description": "Default server"
-    }
-  ],
-  "paths": {
-    "/": {
-      "get": {
-        "summary": "GET /",
-        "description": "Handler: helloWorld",
-        "operationId": "get_",
-        "responses": {
-          "200": {
-            "description": "200 response"
-          }
-        }
-      }
-    },
-    "/users": {
-      "get": {
-        "summary": "GET /users",
-        "description": "Handler: getUsers",
-        "operationId": "get_users",
-        "parameters": [
-          {
-            "name": "limit",
-            "in": "query",
-            "description": "Maximum number of users to return",
-            "required": false,
-            "schema": {
-              "type": "string"
-            }
-          },
-          {
-            "name": "offset",
-            "in": "query",
-            "description": "Number of users to skip",
-            "required": false,
-            "schema": {
-              "type": "string"
-            }
-          }
-        ],
-        "responses": {
-          "200": {
-            "description": "200 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/getUsers_200_Response"
-                }
-              }
-            }
-          }
-        }
-      },
-      "post": {
-        "summary": "POST /users",
-        "description": "Handler: createUser",
-        "operationId": "post_users",
-        "requestBody": {
-          "description": "Request body",
-          "content": {
-            "application/json": {
-              "schema": {
-                "$ref": "#/components/schemas/User"
-              }
-            }
-          },
-          "required": true
-        },
-        "responses": {
-          "201": {
-            "description": "201 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/User"
-                }
-              }
-            }
-          },
-          "400": {
-            "description": "400 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/ErrorResponse"
-                }
-              }
-            }
-          }
-        }
-      }
-    },
-    "/users/{id}": {
-      "get": {
-        "summary": "GET /users/{id}",
-        "description": "Handler: getUserByID",
-        "operationId": "get_users_id",
-        "parameters": [
-          {
-            "name": "id",
-            "in": "path",
-            "description": "User ID",
-            "required": true,
-            "schema": {
-              "type": "string"
-            }
-          }
-        ],
-        "responses": {
-          "200": {
-            "description": "200 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/User"
-                }
-              }
-            }
-          }
-        }
-      },
-      "put": {
-        "summary": "PUT /users/{id}",
-        "description": "Handler: updateUser",
-        "operationId": "put_users_id",
-        "parameters": [
-          {
-            "name": "id",
-            "in": "path",
-            "description": "User ID",
-            "required": true,
-            "schema": {
-              "type": "string"
-            }
-          }
-        ],
-        "requestBody": {
-          "description": "Request body",
-          "content": {
-            "application/json": {
-              "schema": {
-                "$ref": "#/components/schemas/User"
-              }
-            }
-          },
-          "required": true
-        },
-        "responses": {
-          "200": {
-            "description": "200 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/User"
-                }
-              }
-            }
-          },
-          "400": {
-            "description": "400 response",
-            "content": {
-              "application/json": {
-                "schema": {
-                  "$ref": "#/components/schemas/ErrorResponse"
-                }
-              }
-            }
-          }
-        }
-      },
-      "delete": {
-        "summary": "DELETE /users/{id}",
-        "description": "Handler: deleteUser",
-        "operationId": "delete_users_id",
-        "parameters": [
-          {
-            "name": "id",
-            "in": "path",
-            "description": "User ID",
-            "required": true,
-            "schema": {
-              "type": "string"
-            }
-          }
-        ],
-        "responses": {
-          "204": {
-            "description": "204 response"
-          }
-        }
-      }
-    }
-  },
-  "components": {
-    "schemas": {
-      "User": {
-        "type": "object",
-        "properties": {
-          "id": {
-            "type": "integer"
-          },
-          "name": {
-            "type": "string"
-          },
-          "email": {
-            "type": "string"
-          },
-          "created_at": {
-            "type": "string",
-            "format": "date-time"
-          },
-          "profile": {
-            "type": "object",
-            "properties": {
-              "bio": {
-                "type": "string"
-              },
-              "skills": {
-                "type": "array",
-                "items": {
-                  "type": "string"
-                }
-              }
-            },
-            "required": ["skills"]
-          }
-        },
-        "required": ["id", "name", "created_at"]
-      },
-      "Profile": {
-        "type": "object",
-        "properties": {
-          "bio": {
-            "type": "string"
-          },
-          "skills": {
-            "type": "array",
-            "items": {
-              "type": "string"
-            }
-          }
-        },
-        "required": ["skills"]
-      },
-      "Product": {
-        "type": "object",
-        "properties": {
-          "id": {
-            "type": "integer"
-          },
-          "name": {
-            "type": "string"
-          },
-          "description": {
-            "type": "string"
-          },
-          "price": {
-            "type": "number"
-          },
-          "categories": {
-            "type": "array",
-            "items": {
-              "type": "string"
-            }
-          },
-          "attributes": {
-            "type": "object",
-            "additionalProperties": {
-              "type": "string"
-            }
-          },
-          "inventory": {
-            "type": "object",
-            "properties": {
-              "quantity": {
-                "type": "integer"
-              },
-              "available": {
-                "type": "boolean"
-              }
-            },
-            "required": ["quantity", "available"]
-          }
-        },
-        "required": ["id", "name", "price", "categories"]
-      },
-      "ErrorResponse": {
-        "type": "object",
-        "properties": {
-          "error": {
-            "type": "string"
-          },
-          "message": {
-            "type": "string"
-          },
-          "code": {
-            "type": "integer"
-          }
-        },
-        "required": ["error", "code"]
-      },
-      "getUsers_200_Response": {
-        "type": "array",
-        "items": {
-          "$ref": "#/components/schemas/User"
-        }
-      }
-    }
-  }
-}`
-	}
-
-	// Write the simulated output to a file
-	if err := os.WriteFile(outputFile, []byte(content), 0644); err != nil {
-		fmt.Printf("Error writing simulated output: %v\n", err)
-		return
-	}
-
-	fmt.Printf("Simulated output written to: %s\n", outputFile)
-}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/user/golang-echo-analyzer/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/generator"
+	"github.com/user/golang-echo-analyzer/internal/logger"
+)
+
+// watchDebounce is how long --watch waits after the last change before
+// regenerating, so saving several files at once triggers a single run
+const watchDebounce = 300 * time.Millisecond
+
+// watchRepositories regenerates the output whenever a .go file under one of
+// the repositories changes, until the process is interrupted
+func watchRepositories(absPaths []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for _, absPath := range absPaths {
+		if err := addWatchDirs(watcher, absPath); err != nil {
+			return err
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	generated := generatedPaths()
+	regenerate(absPaths)
+	log.Infof("Watching for changes, press Ctrl+C to stop...")
+
+	// The timer is started by the first change and reset by the next ones
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// Watch the directories created while running
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchDirs(watcher, event.Name); err != nil {
+						log.Warnf("watching %s: %v", event.Name, err)
+					}
+					continue
+				}
+			}
+			if !isSourceChange(event.Name, generated) {
+				continue
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			log.Debugf("Change detected: %s", event.Name)
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnf("watching files: %v", err)
+
+		case <-timer.C:
+			regenerate(absPaths)
+
+		case <-interrupt:
+			log.Infof("\nStopped watching.")
+			return nil
+		}
+	}
+}
+
+// generatedPaths returns the absolute paths of the files the tool writes:
+// the output file, or directory with --schema-only, and the report
+func generatedPaths() []string {
+	var paths []string
+	for _, path := range []string{outputFile, reportPath} {
+		if path == "" || path == generator.StdoutOutput {
+			continue
+		}
+		if absPath, err := filepath.Abs(path); err == nil {
+			paths = append(paths, absPath)
+		}
+	}
+	return paths
+}
+
+// isSourceChange reports whether a changed file is a Go source file the
+// analysis reads. Test files are skipped, and so are the generated files and
+// the temporary files they're written through, or a Go output inside the
+// repository would trigger a new run every time it's written.
+func isSourceChange(name string, generated []string) bool {
+	if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+	dir, base := filepath.Split(name)
+	for _, path := range generated {
+		if name == path || strings.HasPrefix(name, path+string(filepath.Separator)) {
+			return false
+		}
+		if filepath.Clean(dir) == filepath.Dir(path) && strings.HasPrefix(base, "."+filepath.Base(path)+".tmp-") {
+			return false
+		}
+	}
+	return true
+}
+
+// addWatchDirs watches a directory and its subdirectories, skipping the ones
+// the parser skips
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+		for _, pattern := range excludeDirs {
+			if matched, _ := filepath.Match(pattern, info.Name()); matched {
+				return filepath.SkipDir
+			}
+		}
+		return watcher.Add(path)
+	})
+}
+
+// regenerate analyzes the repositories and writes the output, printing a
+// one-line summary. Errors are printed so the watch keeps running.
+func regenerate(absPaths []string) {
+	start := time.Now()
+
+	// The analysis steps are only logged at debug level to keep the watch
+	// output concise; the parse cache makes unchanged files cheap to reload
+	results := []*analyzer.APIDocument{}
+	for _, absPath := range absPaths {
+		result, err := analyze(absPath, log.Writer(logger.LevelDebug))
+		if err != nil {
+			log.Errorf("%v", err)
+			return
+		}
+		results = append(results, result)
+	}
+	result := analyzer.Merge(results)
+	printWarnings(result)
+
+	if reportPath != "" {
+		if err := generator.WriteJSON(reportPath, result.Report()); err != nil {
+			log.Errorf("writing report: %v", err)
+			return
+		}
+	}
+
+	summary := fmt.Sprintf("%d routes", len(result.Routes))
+	if schemaOnly {
+		count, err := generator.WriteSchemas(outputFile, result.TypeRegistry, newSchemaGenerator(result))
+		if err != nil {
+			log.Errorf("generating schemas: %v", err)
+			return
+		}
+		summary = fmt.Sprintf("%d schemas", count)
+	} else if err := newDocGenerator(result).Generate(); err != nil {
+		log.Errorf("generating documentation: %v", err)
+		return
+	}
+
+	log.Infof("[%s] Regenerated %s: %s in %s", start.Format("15:04:05"), outputFile, summary, time.Since(start).Round(time.Millisecond))
+}
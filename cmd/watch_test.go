@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIsSourceChange(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	generated := []string{
+		filepath.Join(root, "client", "client.go"),
+		filepath.Join(root, "schemas"),
+		filepath.Join(root, "report.go"),
+	}
+
+	for _, test := range []struct {
+		name string
+		want bool
+	}{
+		{"main.go", true},
+		{"client/types.go", true},
+		{"api/handlers.go", true},
+		{"api/handlers_test.go", false},
+		{"README.md", false},
+		{"client/client.go", false},
+		{"client/.client.go.tmp-123456.go", false},
+		{"api/.client.go.tmp-123456.go", true},
+		{"schemas/user.go", false},
+		{"schemas.go", true},
+		{"report.go", false},
+	} {
+		name := filepath.Join(root, filepath.FromSlash(test.name))
+		if got := isSourceChange(name, generated); got != test.want {
+			t.Errorf("isSourceChange(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGeneratedPaths(t *testing.T) {
+	defer func(output, report string) {
+		outputFile, reportPath = output, report
+	}(outputFile, reportPath)
+
+	outputFile, reportPath = "-", ""
+	if paths := generatedPaths(); len(paths) != 0 {
+		t.Errorf("generatedPaths() with --output - = %v, want none", paths)
+	}
+
+	outputFile, reportPath = filepath.Join("client", "client.go"), "report.json"
+	paths := generatedPaths()
+	if len(paths) != 2 || !filepath.IsAbs(paths[0]) || filepath.Base(paths[0]) != "client.go" || filepath.Base(paths[1]) != "report.json" {
+		t.Errorf("generatedPaths() = %v, want the absolute output and report paths", paths)
+	}
+}
@@ -3,20 +3,12 @@ module github.com/user/golang-echo-analyzer
 go 1.18
 
 require (
-	github.com/aws/aws-sdk-go v1.50.0
 	github.com/fatih/color v1.16.0
-	github.com/labstack/echo/v4 v4.11.4
+	github.com/fsnotify/fsnotify v1.7.0
 )
 
 require (
-	github.com/jmespath/go-jmespath v0.4.0 // indirect
-	github.com/labstack/gommon v0.4.2 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasttemplate v1.2.2 // indirect
-	golang.org/x/crypto v0.17.0 // indirect
-	golang.org/x/net v0.19.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 )
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// bodyOptionalMarker marks a handler whose request body is optional when
+// it appears in the handler's doc comment
+const bodyOptionalMarker = "@body-optional"
+
+// hasBodyOptionalMarker checks if a doc comment has the @body-optional marker
+func hasBodyOptionalMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.Contains(comment.Text, bodyOptionalMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBindErrors infers whether the bodies bound in a handler are required
+// from how the bind error is handled. A body whose bind error leads to an
+// early return, as in if err := c.Bind(&u); err != nil { return err }, stays
+// required; one whose bind error is discarded, or checked without returning,
+// is optional.
+func (a *HandlerAnalyzer) checkBindErrors(body *ast.BlockStmt, handlerInfo *HandlerInfo) {
+	// Variables holding the error of a bind call, by the bound variable
+	bindErrs := make(map[string]string)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.ExprStmt:
+			// c.Bind(&u) with the result ignored
+			if name := a.boundBody(stmt.X); name != "" {
+				a.setBodyRequired(handlerInfo, name, false)
+			}
+
+		case *ast.AssignStmt:
+			a.trackBindErrors(stmt, bindErrs, handlerInfo)
+
+		case *ast.IfStmt:
+			// The init statement is tracked before the condition checking it
+			if assign, ok := stmt.Init.(*ast.AssignStmt); ok {
+				a.trackBindErrors(assign, bindErrs, handlerInfo)
+			}
+
+			cond, ok := stmt.Cond.(*ast.BinaryExpr)
+			if !ok || (cond.Op != token.NEQ && cond.Op != token.EQL) || !isNilIdent(cond.Y) {
+				return true
+			}
+			name := a.boundBody(cond.X)
+			if ident, ok := cond.X.(*ast.Ident); ok && name == "" {
+				name = bindErrs[ident.Name]
+			}
+			if name == "" {
+				return true
+			}
+
+			// The error branch is the body for err != nil, the else
+			// branch for err == nil
+			var errBranch ast.Stmt = stmt.Body
+			if cond.Op == token.EQL {
+				errBranch = stmt.Else
+			}
+			a.setBodyRequired(handlerInfo, name, returns(errBranch))
+		}
+		return true
+	})
+}
+
+// trackBindErrors records the variables assigned the error of a bind call,
+// and marks the bodies whose error is assigned to _ optional
+func (a *HandlerAnalyzer) trackBindErrors(assign *ast.AssignStmt, bindErrs map[string]string, handlerInfo *HandlerInfo) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, rhs := range assign.Rhs {
+		ident, ok := assign.Lhs[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		name := a.boundBody(rhs)
+		if name == "" {
+			delete(bindErrs, ident.Name)
+			continue
+		}
+		if ident.Name == "_" {
+			a.setBodyRequired(handlerInfo, name, false)
+			continue
+		}
+		bindErrs[ident.Name] = name
+	}
+}
+
+// boundBody returns the variable a bind call binds the body to, or "" if
+// the expression isn't a bind call
+func (a *HandlerAnalyzer) boundBody(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	match, ok := a.Detectors.DetectRequest(call)
+	if !ok || match.Type != "Body" || match.Name == nil {
+		return ""
+	}
+	return a.extractVariableName(match.Name)
+}
+
+// setBodyRequired sets whether the body bound to a variable is required
+func (a *HandlerAnalyzer) setBodyRequired(handlerInfo *HandlerInfo, varName string, required bool) {
+	for i := range handlerInfo.RequestInputs {
+		input := &handlerInfo.RequestInputs[i]
+		if input.Type == "Body" && input.Name == varName {
+			input.Required = required
+			if !required {
+				a.Log.Debugf("    Request body %s is optional: bind errors are ignored", varName)
+			}
+		}
+	}
+}
+
+// markBodiesOptional marks every request body of a handler optional, for
+// handlers with the @body-optional marker
+func markBodiesOptional(handlerInfo *HandlerInfo) {
+	for i := range handlerInfo.RequestInputs {
+		if handlerInfo.RequestInputs[i].Type == "Body" {
+			handlerInfo.RequestInputs[i].Required = false
+		}
+	}
+}
+
+// isNilIdent checks if an expression is the nil identifier
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// returns checks if a statement returns from the handler, outside of the
+// function literals it declares
+func returns(stmt ast.Stmt) bool {
+	if stmt == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// echoImportPath is the import path of Echo up to v3; later major versions
+// add a /vN suffix
+const echoImportPath = "github.com/labstack/echo"
+
+// defaultEchoVersion is the Echo major version assumed for files that don't
+// import Echo, such as files declaring handler helpers
+const defaultEchoVersion = 4
+
+// contextMethod describes an echo.Context method reading a request input
+// named by its first argument
+type contextMethod struct {
+	InputType  string // Type of the request input (Path, Query, Form, File, Cookie)
+	DataType   string // Data type of the input, "string" if empty
+	Required   bool   // Whether the input is required
+	DefaultArg bool   // Whether the second argument is the default value
+}
+
+// echoV4ContextMethods are the request input accessors of echo.Context in v4
+var echoV4ContextMethods = map[string]contextMethod{
+	"Param":      {InputType: "Path", Required: true},
+	"QueryParam": {InputType: "Query"},
+	"FormValue":  {InputType: "Form"},
+	"FormFile":   {InputType: "File", DataType: "file"},
+	"Cookie":     {InputType: "Cookie"},
+}
+
+// echoV5ContextMethods are the request input accessors of *echo.Context in
+// v5, where the context is a struct rather than an interface. v5 differs
+// from v4 in:
+//   - PathParam, the v5 name for reading a path parameter (Param is kept)
+//   - QueryParamOr and FormValueOr, which take the value to use when the
+//     parameter is missing as their second argument
+var echoV5ContextMethods = map[string]contextMethod{
+	"Param":        {InputType: "Path", Required: true},
+	"PathParam":    {InputType: "Path", Required: true},
+	"QueryParam":   {InputType: "Query"},
+	"QueryParamOr": {InputType: "Query", DefaultArg: true},
+	"FormValue":    {InputType: "Form"},
+	"FormValueOr":  {InputType: "Form", DefaultArg: true},
+	"FormFile":     {InputType: "File", DataType: "file"},
+	"Cookie":       {InputType: "Cookie"},
+}
+
+// registerEchoRequestDetectors registers the detectors of the echo.Context
+// methods reading a request input, matched against the accessors of the
+// Echo version the file of each call imports
+func (a *HandlerAnalyzer) registerEchoRequestDetectors(d *types.Detectors) {
+	// Request body binding: c.Bind(&user)
+	d.RegisterRequest("Bind", types.RequestDetectorFunc(func(call *ast.CallExpr) (types.RequestMatch, bool) {
+		if !types.IsContextCall(call) || len(call.Args) == 0 {
+			return types.RequestMatch{}, false
+		}
+		return types.RequestMatch{Type: "Body", Name: call.Args[0], Required: true}, true
+	}))
+
+	// Inputs read by name, e.g. c.Param("id") or c.QueryParam("filter")
+	names := make(map[string]bool)
+	for _, methods := range []map[string]contextMethod{echoV4ContextMethods, echoV5ContextMethods} {
+		for name := range methods {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		name := name
+		d.RegisterRequest(name, types.RequestDetectorFunc(func(call *ast.CallExpr) (types.RequestMatch, bool) {
+			method, exists := contextMethods(a.echoVersionAt(call))[name]
+			if !exists || !types.IsContextCall(call) || len(call.Args) == 0 {
+				return types.RequestMatch{}, false
+			}
+			match := types.RequestMatch{
+				Type:     method.InputType,
+				Name:     call.Args[0],
+				DataType: method.DataType,
+				Required: method.Required,
+			}
+			if method.DefaultArg && len(call.Args) > 1 {
+				match.Default = call.Args[1]
+			}
+			return match, true
+		}))
+	}
+}
+
+// contextMethods returns the request input accessors of the context of an
+// Echo major version
+func contextMethods(version int) map[string]contextMethod {
+	if version >= 5 {
+		return echoV5ContextMethods
+	}
+	return echoV4ContextMethods
+}
+
+// echoMajorVersion returns the major version of Echo imported by a file,
+// from its import path, e.g. 5 for github.com/labstack/echo/v5, or 0 if the
+// file doesn't import Echo
+func echoMajorVersion(file *ast.File) int {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || !strings.HasPrefix(path, echoImportPath) {
+			continue
+		}
+		suffix := strings.TrimPrefix(path, echoImportPath)
+		if suffix == "" {
+			return 3
+		}
+		if !strings.HasPrefix(suffix, "/v") {
+			continue
+		}
+		if version, err := strconv.Atoi(strings.TrimPrefix(suffix, "/v")); err == nil {
+			return version
+		}
+	}
+	return 0
+}
+
+// collectEchoVersions records the Echo major version imported by each file
+func (a *HandlerAnalyzer) collectEchoVersions(files []*ast.File) {
+	a.echoVersions = make(map[string]int)
+	for _, file := range files {
+		if version := echoMajorVersion(file); version > 0 {
+			a.echoVersions[a.FileSet.Position(file.Pos()).Filename] = version
+		}
+	}
+}
+
+// echoVersionAt returns the Echo major version of the file declaring a node
+func (a *HandlerAnalyzer) echoVersionAt(node ast.Node) int {
+	if version, exists := a.echoVersions[a.FileSet.Position(node.Pos()).Filename]; exists {
+		return version
+	}
+	return defaultEchoVersion
+}
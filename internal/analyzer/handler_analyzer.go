@@ -4,9 +4,17 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	gotypes "go/types"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/user/golang-echo-analyzer/internal/logger"
 	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
 )
 
 // HandlerInfo represents information about a handler function
@@ -15,16 +23,31 @@ type HandlerInfo struct {
 	Route           scanner.RouteInfo
 	RequestInputs   []RequestInput
 	ResponseOutputs []ResponseOutput
+	Timeouts        []TimeoutInfo
+	Protocol        string // Protocol spoken by the handler (http, websocket or sse)
 	Position        token.Position
+
+	// Deprecated is set when the handler's doc comment has a paragraph
+	// starting with "Deprecated:", Go's convention for deprecated APIs
+	Deprecated bool
+}
+
+// TimeoutInfo represents a timeout or deadline applied to the request context
+type TimeoutInfo struct {
+	Function string // context function used (WithTimeout or WithDeadline)
+	Duration string // Duration if it is a constant expression (e.g. 5s)
+	Position token.Position
 }
 
 // RequestInput represents an input parameter from a request
 type RequestInput struct {
-	Type        string // Path, Query, Form, Body, etc.
+	Type        string // Path, Query, Form, File, Body, etc.
 	Name        string // Parameter name
 	DataType    string // Data type if available
 	Description string // Description from comments if available
 	Required    bool   // Whether the parameter is required
+	Default     string // Value used when a query parameter is missing, if detected
+	Validated   bool   // Whether a bound body is passed to c.Validate
 	Position    token.Position
 }
 
@@ -34,38 +57,77 @@ type ResponseOutput struct {
 	StatusCode  int    // HTTP status code
 	DataType    string // Data type if available
 	Description string // Description from comments if available
+	Primary     bool   // Whether this is the primary success response
+	ContentType string // MIME type of the response body, if it has one
+	Template    string // Name of the template rendered by c.Render, if any
 	Position    token.Position
+
+	// StatusUnknown is set when the status code argument can't be
+	// statically determined, in which case StatusCode defaults to 200, or
+	// to 204 for NoContent and 302 for Redirect
+	StatusUnknown bool
 }
 
 // HandlerAnalyzer analyzes Echo handler functions to determine inputs and outputs
 type HandlerAnalyzer struct {
 	FileSet  *token.FileSet
 	Handlers map[string]*HandlerInfo
-	Verbose  bool
+	Log      *logger.Logger
+
+	// DetectTimeouts records context timeouts applied to the request context
+	DetectTimeouts bool
+
+	// DefaultQueryHelper is the name of a helper reading a query parameter
+	// with a default value, called as helper(c, "name", "default")
+	DefaultQueryHelper string
+
+	// ErrorHandler is the analyzed HTTP error handler, if one is assigned
+	ErrorHandler *HandlerInfo
+
+	// Detectors match the calls reading a request input or writing a
+	// response: the echo.Context methods, and custom helpers registered
+	Detectors *types.Detectors
+
+	// statusConstants maps names declared with a status code value to the code
+	statusConstants map[string]int
+
+	// echoVersions maps the name of each file importing Echo to the major
+	// version it imports, which selects the context methods recognized
+	echoVersions map[string]int
+
+	// netHTTP is set by UseNetHTTP for handlers of net/http ServeMux routes
+	netHTTP bool
 }
 
 // NewHandlerAnalyzer creates a new HandlerAnalyzer
-func NewHandlerAnalyzer(fset *token.FileSet, verbose bool) *HandlerAnalyzer {
-	return &HandlerAnalyzer{
-		FileSet:  fset,
-		Handlers: make(map[string]*HandlerInfo),
-		Verbose:  verbose,
+func NewHandlerAnalyzer(fset *token.FileSet, log *logger.Logger) *HandlerAnalyzer {
+	a := &HandlerAnalyzer{
+		FileSet:   fset,
+		Handlers:  make(map[string]*HandlerInfo),
+		Log:       log,
+		Detectors: types.EchoDetectors(),
 	}
+	a.registerEchoRequestDetectors(a.Detectors)
+	return a
 }
 
 // Analyze analyzes handler functions for request inputs and response outputs
 func (a *HandlerAnalyzer) Analyze(files []*ast.File, routes []scanner.RouteInfo) error {
-	if a.Verbose {
-		fmt.Println("Analyzing handler functions...")
-	}
+	a.Log.Debugf("Analyzing handler functions...")
 
-	// First, find all handler function declarations
+	// First, find all handler function declarations and status code constants
 	handlerFuncs := a.findHandlerFunctions(files)
+	a.statusConstants = types.CollectStatusConstants(files)
+	a.collectEchoVersions(files)
 
 	// Then, analyze each handler function
 	for _, route := range routes {
-		if a.Verbose {
-			fmt.Printf("  Analyzing handler for route: %s %s\n", route.Method, route.Path)
+		a.Log.Debugf("  Analyzing handler for route: %s %s", route.Method, route.Path)
+
+		// Static routes are served by Echo rather than a handler function
+		if route.StaticDir != "" {
+			a.addStaticHandler(route)
+			continue
 		}
 
 		// Check if we have the handler function
@@ -83,6 +145,7 @@ func (a *HandlerAnalyzer) Analyze(files []*ast.File, routes []scanner.RouteInfo)
 			Route:           route,
 			RequestInputs:   []RequestInput{},
 			ResponseOutputs: []ResponseOutput{},
+			Protocol:        scanner.ProtocolHTTP,
 			Position:        a.FileSet.Position(handlerFunc.Pos()),
 		}
 
@@ -93,9 +156,7 @@ func (a *HandlerAnalyzer) Analyze(files []*ast.File, routes []scanner.RouteInfo)
 		a.Handlers[route.HandlerName] = handlerInfo
 	}
 
-	if a.Verbose {
-		fmt.Printf("Analyzed %d handlers\n", len(a.Handlers))
-	}
+	a.Log.Debugf("Analyzed %d handlers", len(a.Handlers))
 
 	return nil
 }
@@ -107,12 +168,15 @@ func (a *HandlerAnalyzer) findHandlerFunctions(files []*ast.File) map[string]*as
 	for _, file := range files {
 		for _, decl := range file.Decls {
 			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-				// Check if this function has the Echo handler signature
-				if a.isEchoHandler(funcDecl) {
+				// Check if this function has the Echo handler signature, or
+				// the net/http one for ServeMux routes
+				isHandler := a.isEchoHandler(funcDecl)
+				if a.netHTTP {
+					isHandler = a.isNetHTTPHandler(funcDecl)
+				}
+				if isHandler {
 					handlerFuncs[funcDecl.Name.Name] = funcDecl
-					if a.Verbose {
-						fmt.Printf("  Found handler function: %s\n", funcDecl.Name.Name)
-					}
+					a.Log.Debugf("  Found handler function: %s", funcDecl.Name.Name)
 				}
 			}
 		}
@@ -167,20 +231,128 @@ func (a *HandlerAnalyzer) analyzeHandlerFromRoute(route scanner.RouteInfo) {
 	// Handle anonymous functions
 	if funcLit, ok := route.HandlerNode.(*ast.FuncLit); ok {
 		handlerInfo := &HandlerInfo{
-			Name:            "anonymous",
+			Name:            anonymousHandlerName(route),
 			Route:           route,
 			RequestInputs:   []RequestInput{},
 			ResponseOutputs: []ResponseOutput{},
+			Protocol:        scanner.ProtocolHTTP,
 			Position:        a.FileSet.Position(funcLit.Pos()),
 		}
 
 		// Analyze the function body
 		a.analyzeHandlerBody(funcLit.Body, handlerInfo)
 
-		// Store the handler info with a generated name
-		name := fmt.Sprintf("anonymous_%s_%s", route.Method, strings.Replace(route.Path, "/", "_", -1))
-		a.Handlers[name] = handlerInfo
+		// Store the handler info under its generated name
+		a.Handlers[handlerInfo.Name] = handlerInfo
+	}
+}
+
+// addStaticHandler describes the files served by a route registered with
+// Static or StaticFS, stored under the route's anonymous handler name. The
+// wildcard path parameter holds the path of the file.
+func (a *HandlerAnalyzer) addStaticHandler(route scanner.RouteInfo) {
+	handlerInfo := &HandlerInfo{
+		Name:  anonymousHandlerName(route),
+		Route: route,
+		RequestInputs: []RequestInput{{
+			Type:        "Path",
+			Name:        "*",
+			DataType:    "string",
+			Required:    true,
+			Description: "Path of the file under " + route.StaticDir,
+		}},
+		ResponseOutputs: []ResponseOutput{},
+		Protocol:        scanner.ProtocolHTTP,
+		Position:        route.Position,
+	}
+	a.addResponseOutput(handlerInfo, ResponseOutput{
+		Type:        "File",
+		StatusCode:  http.StatusOK,
+		DataType:    "unknown",
+		Description: "File served from " + route.StaticDir,
+		ContentType: defaultContentTypes["File"],
+		Position:    route.Position,
+	})
+	a.Handlers[handlerInfo.Name] = handlerInfo
+}
+
+// anonymousHandlerName returns the name an anonymous handler is stored under
+func anonymousHandlerName(route scanner.RouteInfo) string {
+	return fmt.Sprintf("anonymous_%s_%s", route.Method, strings.Replace(route.Path, "/", "_", -1))
+}
+
+// HandlerForRoute returns the analyzed handler of a route, or nil if it
+// wasn't found
+func (a *HandlerAnalyzer) HandlerForRoute(route scanner.RouteInfo) *HandlerInfo {
+	if handler, exists := a.Handlers[route.HandlerName]; exists {
+		return handler
 	}
+	return a.Handlers[anonymousHandlerName(route)]
+}
+
+// anonymousErrorHandlerName is the name an anonymous HTTP error handler is stored under
+const anonymousErrorHandlerName = "anonymous_HTTPErrorHandler"
+
+// ErrorHandlerDecl returns the declaration of an HTTP error handler, with a
+// declaration named after anonymousErrorHandlerName standing in for function
+// literals, or nil if the function isn't found
+func ErrorHandlerDecl(files []*ast.File, errorHandler *scanner.ErrorHandlerInfo) *ast.FuncDecl {
+	switch node := errorHandler.HandlerNode.(type) {
+	case *ast.FuncLit:
+		return &ast.FuncDecl{
+			Name: ast.NewIdent(anonymousErrorHandlerName),
+			Type: node.Type,
+			Body: node.Body,
+		}
+	case *ast.Ident:
+		for _, file := range files {
+			for _, decl := range file.Decls {
+				if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == node.Name {
+					return funcDecl
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AnalyzeErrorHandler analyzes the responses written by the HTTP error
+// handler, which has the signature func(err error, c echo.Context). It
+// returns nil if the handler function isn't found.
+func (a *HandlerAnalyzer) AnalyzeErrorHandler(files []*ast.File, errorHandler *scanner.ErrorHandlerInfo) *HandlerInfo {
+	funcDecl := ErrorHandlerDecl(files, errorHandler)
+	if funcDecl == nil {
+		a.Log.Debugf("  HTTP error handler %s not found", errorHandler.HandlerName)
+		return nil
+	}
+
+	if a.statusConstants == nil {
+		a.statusConstants = types.CollectStatusConstants(files)
+	}
+
+	handlerInfo := &HandlerInfo{
+		Name:            funcDecl.Name.Name,
+		RequestInputs:   []RequestInput{},
+		ResponseOutputs: []ResponseOutput{},
+		Protocol:        scanner.ProtocolHTTP,
+		Position:        a.FileSet.Position(funcDecl.Pos()),
+	}
+	a.Log.Debugf("  Analyzing HTTP error handler: %s", handlerInfo.Name)
+	a.analyzeHandlerBody(funcDecl.Body, handlerInfo)
+
+	a.ErrorHandler = handlerInfo
+	return handlerInfo
+}
+
+// ErrorResponse returns the JSON response of the HTTP error handler, or nil
+// if it doesn't write one
+func (h *HandlerInfo) ErrorResponse() *ResponseOutput {
+	for i := range h.ResponseOutputs {
+		if h.ResponseOutputs[i].Type == "JSON" {
+			return &h.ResponseOutputs[i]
+		}
+	}
+	return nil
 }
 
 // analyzeHandlerFunction analyzes a handler function for request inputs and response outputs
@@ -199,6 +371,30 @@ func (a *HandlerAnalyzer) analyzeHandlerFunction(funcDecl *ast.FuncDecl, handler
 
 	// Analyze the function body
 	a.analyzeHandlerBody(funcDecl.Body, handlerInfo)
+
+	// The @body-optional marker overrides the requiredness inferred from
+	// the handling of bind errors
+	if hasBodyOptionalMarker(funcDecl.Doc) {
+		markBodiesOptional(handlerInfo)
+	}
+
+	handlerInfo.Deprecated = isDeprecated(funcDecl.Doc)
+}
+
+// isDeprecated checks if a doc comment has a paragraph starting with
+// "Deprecated:"
+func isDeprecated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	paragraphStart := true
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if paragraphStart && strings.HasPrefix(line, "Deprecated:") {
+			return true
+		}
+		paragraphStart = strings.TrimSpace(line) == ""
+	}
+	return false
 }
 
 // analyzeHandlerBody analyzes a function body for Echo context method calls
@@ -207,162 +403,839 @@ func (a *HandlerAnalyzer) analyzeHandlerBody(body *ast.BlockStmt, handlerInfo *H
 		return
 	}
 
+	// Variables holding the request context, e.g. ctx := c.Request().Context()
+	requestContexts := make(map[string]bool)
+
+	// Variables holding a query parameter, e.g. page := c.QueryParam("page")
+	queryVars := make(map[string]string)
+
+	// Variables holding a path or query parameter, e.g. id := c.Param("id"),
+	// and the types the parameters are converted to with strconv
+	paramVars := make(map[string]paramRef)
+	conversions := make(map[paramRef]string)
+
 	ast.Inspect(body, func(n ast.Node) bool {
-		// Look for method calls on the context parameter
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			a.trackQueryVars(stmt, queryVars)
+			a.trackParamVars(stmt, paramVars)
+		case *ast.IfStmt:
+			a.checkQueryDefault(stmt, queryVars, handlerInfo)
+		}
+
+		// Track variables assigned the request context
+		if a.DetectTimeouts {
+			if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+				for i, rhs := range assign.Rhs {
+					if ident, ok := assign.Lhs[i].(*ast.Ident); ok && isRequestContext(rhs, nil) {
+						requestContexts[ident.Name] = true
+					}
+				}
+			}
+		}
+
+		// Look for method calls on the context parameter and helpers
+		// matched by the detectors
 		if expr, ok := n.(*ast.CallExpr); ok {
+			a.checkRequestInput(expr, handlerInfo)
+			a.checkResponseOutput(expr, handlerInfo)
+
+			if ident, ok := expr.Fun.(*ast.Ident); ok {
+				a.checkDefaultQueryHelper(ident.Name, expr, handlerInfo)
+			}
 			if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
-				if ident, ok := sel.X.(*ast.Ident); ok {
-					// Check for request input methods
-					a.checkRequestInputMethod(ident.Name, sel.Sel.Name, expr, handlerInfo)
+				// Check for validation of a bound body: c.Validate(&user)
+				a.checkValidate(sel, expr, handlerInfo)
+
+				// Check for query parameters read through the default value helper
+				a.checkDefaultQueryHelper(sel.Sel.Name, expr, handlerInfo)
+
+				// Check for request header reads: c.Request().Header.Get("X-Api-Key")
+				a.checkRequestHeaderGet(sel, expr, handlerInfo)
 
-					// Check for response output methods
-					a.checkResponseOutputMethod(ident.Name, sel.Sel.Name, expr, handlerInfo)
+				// Check for parameters converted from strings: strconv.Atoi(c.Param("id"))
+				a.checkParamConversion(sel, expr, paramVars, conversions)
+
+				// Check for WebSocket upgrades and Server-Sent Events streams
+				a.checkProtocol(sel, expr, handlerInfo)
+
+				// Check for timeouts: context.WithTimeout(c.Request().Context(), 5*time.Second)
+				if a.DetectTimeouts {
+					a.checkContextTimeout(sel, expr, requestContexts, handlerInfo)
 				}
 			}
 		}
 		return true
 	})
+
+	// The conversion call is visited before the parameter read it wraps, so
+	// the types are applied once all inputs are recorded
+	for ref, dataType := range conversions {
+		a.setInputDataType(handlerInfo, ref, dataType)
+	}
+
+	// Bodies whose bind errors are ignored are optional
+	a.checkBindErrors(body, handlerInfo)
 }
 
-// checkRequestInputMethod checks if a method call is a request input method
-func (a *HandlerAnalyzer) checkRequestInputMethod(objName, methodName string, call *ast.CallExpr, handlerInfo *HandlerInfo) {
-	// Common context parameter names
-	contextNames := map[string]bool{
-		"c": true, "ctx": true, "context": true, "ec": true,
+// sseContentType is the content type of Server-Sent Events streams
+const sseContentType = "text/event-stream"
+
+// checkProtocol checks if a call upgrades the connection to a WebSocket or
+// starts a Server-Sent Events stream
+func (a *HandlerAnalyzer) checkProtocol(sel *ast.SelectorExpr, call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	switch sel.Sel.Name {
+	case "Handler", "Accept":
+		// golang.org/x/net/websocket: websocket.Handler(fn).ServeHTTP(c.Response(), c.Request())
+		// nhooyr.io/websocket: websocket.Accept(c.Response(), c.Request(), nil)
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "websocket" {
+			handlerInfo.Protocol = scanner.ProtocolWebSocket
+		}
+	case "Upgrade":
+		// gorilla/websocket: upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if len(call.Args) >= 3 {
+			handlerInfo.Protocol = scanner.ProtocolWebSocket
+		}
+	case "Set", "Add":
+		// c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		if len(call.Args) == 2 && a.extractMIMEType(call.Args[1]) == sseContentType && handlerInfo.Protocol == scanner.ProtocolHTTP {
+			handlerInfo.Protocol = scanner.ProtocolSSE
+		}
+	case "Stream":
+		// c.Stream(http.StatusOK, "text/event-stream", reader)
+		if ident, ok := sel.X.(*ast.Ident); ok && contextNames[ident.Name] && len(call.Args) > 1 &&
+			a.extractMIMEType(call.Args[1]) == sseContentType && handlerInfo.Protocol == scanner.ProtocolHTTP {
+			handlerInfo.Protocol = scanner.ProtocolSSE
+		}
 	}
+}
 
-	if !contextNames[objName] {
+// checkContextTimeout checks if a call applies a timeout or deadline to the request context
+func (a *HandlerAnalyzer) checkContextTimeout(sel *ast.SelectorExpr, call *ast.CallExpr, requestContexts map[string]bool, handlerInfo *HandlerInfo) {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "context" || len(call.Args) < 2 {
+		return
+	}
+	if sel.Sel.Name != "WithTimeout" && sel.Sel.Name != "WithDeadline" {
+		return
+	}
+	if !isRequestContext(call.Args[0], requestContexts) {
 		return
 	}
 
-	var inputType, paramName string
-	var required bool
+	timeout := TimeoutInfo{
+		Function: sel.Sel.Name,
+		Position: a.FileSet.Position(call.Pos()),
+	}
 
-	switch methodName {
-	case "Param":
-		// Path parameter: c.Param("id")
-		inputType = "Path"
-		required = true
-		if len(call.Args) > 0 {
-			paramName = a.extractStringLiteral(call.Args[0])
+	// A deadline is usually time.Now().Add(duration)
+	durationExpr := call.Args[1]
+	if sel.Sel.Name == "WithDeadline" {
+		durationExpr = nil
+		if addCall, ok := call.Args[1].(*ast.CallExpr); ok && len(addCall.Args) == 1 {
+			if addSel, ok := addCall.Fun.(*ast.SelectorExpr); ok && addSel.Sel.Name == "Add" {
+				durationExpr = addCall.Args[0]
+			}
 		}
-	case "QueryParam":
-		// Query parameter: c.QueryParam("filter")
-		inputType = "Query"
-		required = false
-		if len(call.Args) > 0 {
-			paramName = a.extractStringLiteral(call.Args[0])
+	}
+	if d, ok := evalDuration(durationExpr); ok {
+		timeout.Duration = d.String()
+	}
+
+	handlerInfo.Timeouts = append(handlerInfo.Timeouts, timeout)
+	a.Log.Debugf("    Found request timeout: context.%s %s", timeout.Function, timeout.Duration)
+}
+
+// isRequestContext checks if an expression is the request context, either
+// c.Request().Context() or a variable holding it
+func isRequestContext(expr ast.Expr, requestContexts map[string]bool) bool {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return requestContexts[ident.Name]
+	}
+
+	// c.Request().Context()
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Context" {
+		return false
+	}
+	reqCall, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	reqSel, ok := reqCall.Fun.(*ast.SelectorExpr)
+	if !ok || reqSel.Sel.Name != "Request" {
+		return false
+	}
+	ident, ok := reqSel.X.(*ast.Ident)
+	return ok && contextNames[ident.Name]
+}
+
+// durationUnits maps the time package's duration constants to their values
+var durationUnits = map[string]time.Duration{
+	"Nanosecond":  time.Nanosecond,
+	"Microsecond": time.Microsecond,
+	"Millisecond": time.Millisecond,
+	"Second":      time.Second,
+	"Minute":      time.Minute,
+	"Hour":        time.Hour,
+}
+
+// evalDuration evaluates a constant duration expression such as
+// 5 * time.Second, time.Minute or time.Duration(30) * time.Second
+func evalDuration(expr ast.Expr) (time.Duration, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return evalDuration(e.X)
+	case *ast.BasicLit:
+		if e.Kind == token.INT || e.Kind == token.FLOAT {
+			value, err := strconv.ParseFloat(e.Value, 64)
+			return time.Duration(value), err == nil
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "time" {
+			unit, exists := durationUnits[e.Sel.Name]
+			return unit, exists
 		}
-	case "FormValue":
-		// Form value: c.FormValue("name")
-		inputType = "Form"
-		required = false
-		if len(call.Args) > 0 {
-			paramName = a.extractStringLiteral(call.Args[0])
+	case *ast.CallExpr:
+		// time.Duration(n) conversion
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Duration" && len(e.Args) == 1 {
+			return evalDuration(e.Args[0])
 		}
-	case "Bind":
-		// Request body binding: c.Bind(&user)
-		inputType = "Body"
-		required = true
-		if len(call.Args) > 0 {
-			paramName = a.extractVariableName(call.Args[0])
+	case *ast.BinaryExpr:
+		x, okX := evalDuration(e.X)
+		y, okY := evalDuration(e.Y)
+		if !okX || !okY {
+			return 0, false
 		}
+		switch e.Op {
+		case token.MUL:
+			return x * y, true
+		case token.ADD:
+			return x + y, true
+		case token.SUB:
+			return x - y, true
+		}
+	}
+	return 0, false
+}
+
+// contextNames lists the common names of the Echo context parameter
+var contextNames = map[string]bool{
+	"c": true, "ctx": true, "context": true, "ec": true,
+}
+
+// checkValidate checks if a call validates a bound body: c.Validate(&user)
+func (a *HandlerAnalyzer) checkValidate(sel *ast.SelectorExpr, call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	if sel.Sel.Name == "Validate" && types.IsContextCall(call) && len(call.Args) > 0 {
+		a.markBodyValidated(handlerInfo, a.extractVariableName(call.Args[0]))
+	}
+}
+
+// checkRequestInput checks if a call reads a request input, e.g.
+// c.Param("id") or a helper matched by a registered detector
+func (a *HandlerAnalyzer) checkRequestInput(call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	match, ok := a.Detectors.DetectRequest(call)
+	if !ok || match.Name == nil {
+		return
+	}
+
+	// A body is named by the variable it is bound to, other inputs by a
+	// string literal
+	var paramName string
+	if match.Type == "Body" {
+		paramName = a.extractVariableName(match.Name)
+	} else {
+		paramName = a.extractStringLiteral(match.Name)
+	}
+	if paramName == "" {
+		return
 	}
 
-	if inputType != "" && paramName != "" {
-		input := RequestInput{
-			Type:     inputType,
-			Name:     paramName,
-			DataType: "string", // Default type
-			Required: required,
-			Position: a.FileSet.Position(call.Pos()),
+	dataType := match.DataType
+	if dataType == "" {
+		dataType = "string"
+	}
+	input := RequestInput{
+		Type:     match.Type,
+		Name:     paramName,
+		DataType: dataType,
+		Required: match.Required,
+		Position: a.FileSet.Position(call.Pos()),
+	}
+	if match.Default != nil {
+		if value, ok := literalValue(match.Default); ok {
+			input.Default = value
 		}
+	}
+	a.addRequestInput(handlerInfo, input)
+}
 
-		// Check if this input already exists
-		exists := false
-		for _, existing := range handlerInfo.RequestInputs {
-			if existing.Type == input.Type && existing.Name == input.Name {
-				exists = true
-				break
-			}
+// trackQueryVars records the variables assigned a query parameter, e.g.
+// page := c.QueryParam("page")
+func (a *HandlerAnalyzer) trackQueryVars(assign *ast.AssignStmt, queryVars map[string]string) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, rhs := range assign.Rhs {
+		ident, ok := assign.Lhs[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		delete(queryVars, ident.Name)
+		if name := a.queryParamName(rhs); name != "" {
+			queryVars[ident.Name] = name
 		}
+	}
+}
 
-		if !exists {
-			handlerInfo.RequestInputs = append(handlerInfo.RequestInputs, input)
-			if a.Verbose {
-				fmt.Printf("    Found request input: %s %s\n", input.Type, input.Name)
-			}
+// queryParamName returns the name of the query parameter an expression
+// reads with c.QueryParam("name"), or "" if it doesn't read one
+func (a *HandlerAnalyzer) queryParamName(expr ast.Expr) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "QueryParam" {
+		return ""
+	}
+	if ident, ok := sel.X.(*ast.Ident); !ok || !contextNames[ident.Name] {
+		return ""
+	}
+	return a.extractStringLiteral(call.Args[0])
+}
+
+// paramRef identifies a path or query parameter read by a handler
+type paramRef struct {
+	Type string // Path or Query
+	Name string
+}
+
+// strconvConversions maps the strconv functions parsing a parameter to the
+// data type they produce
+var strconvConversions = map[string]string{
+	"Atoi":       "int",
+	"ParseInt":   "int64",
+	"ParseUint":  "uint64",
+	"ParseFloat": "float64",
+	"ParseBool":  "bool",
+}
+
+// trackParamVars records the variables assigned a path or query parameter,
+// e.g. id := c.Param("id")
+func (a *HandlerAnalyzer) trackParamVars(assign *ast.AssignStmt, paramVars map[string]paramRef) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, rhs := range assign.Rhs {
+		ident, ok := assign.Lhs[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if ref, ok := a.paramRead(rhs); ok {
+			paramVars[ident.Name] = ref
+		} else if assign.Tok == token.DEFINE {
+			// A reassignment like id = strings.TrimSpace(id) still holds
+			// the parameter, a new variable of the same name doesn't
+			delete(paramVars, ident.Name)
 		}
 	}
 }
 
-// checkResponseOutputMethod checks if a method call is a response output method
-func (a *HandlerAnalyzer) checkResponseOutputMethod(objName, methodName string, call *ast.CallExpr, handlerInfo *HandlerInfo) {
-	// Common context parameter names
-	contextNames := map[string]bool{
-		"c": true, "ctx": true, "context": true, "ec": true,
+// paramRead returns the path or query parameter an expression reads, e.g.
+// c.Param("id") or c.QueryParam("page")
+func (a *HandlerAnalyzer) paramRead(expr ast.Expr) (paramRef, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return paramRef{}, false
 	}
+	match, ok := a.Detectors.DetectRequest(call)
+	if !ok || match.Type != "Path" && match.Type != "Query" || match.Name == nil {
+		return paramRef{}, false
+	}
+	name := a.extractStringLiteral(match.Name)
+	if name == "" {
+		return paramRef{}, false
+	}
+	return paramRef{Type: match.Type, Name: name}, true
+}
 
-	if !contextNames[objName] {
+// checkParamConversion checks if a call parses a path or query parameter
+// with strconv, either directly or through the variable holding it:
+// strconv.Atoi(c.Param("id")) or id := c.Param("id"); strconv.Atoi(id)
+func (a *HandlerAnalyzer) checkParamConversion(sel *ast.SelectorExpr, call *ast.CallExpr, paramVars map[string]paramRef, conversions map[paramRef]string) {
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "strconv" || len(call.Args) == 0 {
+		return
+	}
+	dataType, exists := strconvConversions[sel.Sel.Name]
+	if !exists {
 		return
 	}
 
-	var outputType string
-	var statusCode int = 200 // Default status code
-
-	switch methodName {
-	case "String":
-		// String response: c.String(http.StatusOK, "Hello")
-		outputType = "String"
-	case "JSON":
-		// JSON response: c.JSON(http.StatusOK, user)
-		outputType = "JSON"
-	case "XML":
-		// XML response: c.XML(http.StatusOK, data)
-		outputType = "XML"
-	case "HTML":
-		// HTML response: c.HTML(http.StatusOK, "<html>...</html>")
-		outputType = "HTML"
-	case "File":
-		// File response: c.File("path/to/file")
-		outputType = "File"
-	case "Blob":
-		// Blob response: c.Blob(http.StatusOK, "application/octet-stream", data)
-		outputType = "Blob"
-	case "Stream":
-		// Stream response: c.Stream(http.StatusOK, "application/octet-stream", reader)
-		outputType = "Stream"
-	case "NoContent":
-		// No content response: c.NoContent(http.StatusNoContent)
-		outputType = "NoContent"
-	case "Redirect":
-		// Redirect response: c.Redirect(http.StatusFound, "/new-url")
-		outputType = "Redirect"
+	ref, ok := a.paramRead(call.Args[0])
+	if !ok {
+		ident, isIdent := call.Args[0].(*ast.Ident)
+		if !isIdent {
+			return
+		}
+		if ref, ok = paramVars[ident.Name]; !ok {
+			return
+		}
 	}
+	conversions[ref] = dataType
+}
 
-	if outputType != "" {
-		// Try to extract status code from first argument
-		if len(call.Args) > 0 {
-			statusCode = a.extractStatusCode(call.Args[0])
+// setInputDataType sets the data type of a path or query parameter
+func (a *HandlerAnalyzer) setInputDataType(handlerInfo *HandlerInfo, ref paramRef, dataType string) {
+	for i := range handlerInfo.RequestInputs {
+		input := &handlerInfo.RequestInputs[i]
+		if input.Type == ref.Type && input.Name == ref.Name && input.DataType == "string" {
+			input.DataType = dataType
+			a.Log.Debugf("    Found %s parameter %s converted to %s", strings.ToLower(ref.Type), ref.Name, dataType)
 		}
+	}
+}
+
+// checkQueryDefault checks if an if statement assigns a default value to a
+// missing query parameter: if page == "" { page = "1" }
+func (a *HandlerAnalyzer) checkQueryDefault(ifStmt *ast.IfStmt, queryVars map[string]string, handlerInfo *HandlerInfo) {
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.EQL {
+		return
+	}
+
+	// The variable may be on either side of the comparison
+	varExpr, emptyExpr := cond.X, cond.Y
+	if lit, ok := varExpr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		varExpr, emptyExpr = emptyExpr, varExpr
+	}
+	ident, ok := varExpr.(*ast.Ident)
+	if !ok || queryVars[ident.Name] == "" {
+		return
+	}
+	if lit, ok := emptyExpr.(*ast.BasicLit); !ok || lit.Kind != token.STRING || lit.Value != `""` && lit.Value != "``" {
+		return
+	}
+
+	for _, stmt := range ifStmt.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		if lhs, ok := assign.Lhs[0].(*ast.Ident); !ok || lhs.Name != ident.Name {
+			continue
+		}
+		if value, ok := literalValue(assign.Rhs[0]); ok {
+			a.setInputDefault(handlerInfo, queryVars[ident.Name], value)
+		}
+		return
+	}
+}
+
+// checkDefaultQueryHelper checks if a call reads a query parameter through
+// the default value helper: defaultQuery(c, "page", "1")
+func (a *HandlerAnalyzer) checkDefaultQueryHelper(funcName string, call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	if a.DefaultQueryHelper == "" || funcName != a.DefaultQueryHelper || len(call.Args) != 3 {
+		return
+	}
+	if ident, ok := call.Args[0].(*ast.Ident); !ok || !contextNames[ident.Name] {
+		return
+	}
+	paramName := a.extractStringLiteral(call.Args[1])
+	if paramName == "" {
+		return
+	}
+
+	a.addRequestInput(handlerInfo, RequestInput{
+		Type:     "Query",
+		Name:     paramName,
+		DataType: "string",
+		Required: false,
+		Position: a.FileSet.Position(call.Pos()),
+	})
+	if value, ok := literalValue(call.Args[2]); ok {
+		a.setInputDefault(handlerInfo, paramName, value)
+	}
+}
+
+// setInputDefault sets the default value of a query parameter
+func (a *HandlerAnalyzer) setInputDefault(handlerInfo *HandlerInfo, paramName, value string) {
+	for i := range handlerInfo.RequestInputs {
+		input := &handlerInfo.RequestInputs[i]
+		if input.Type == "Query" && input.Name == paramName && input.Default == "" {
+			input.Default = value
+			a.Log.Debugf("    Found default value of query parameter %s: %q", paramName, value)
+		}
+	}
+}
+
+// literalValue returns the value of a string or number literal, unquoted
+func literalValue(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	if lit.Kind == token.STRING {
+		value, err := strconv.Unquote(lit.Value)
+		return value, err == nil
+	}
+	return lit.Value, lit.Kind == token.INT || lit.Kind == token.FLOAT
+}
+
+// markBodyValidated marks the body bound to a variable as validated
+func (a *HandlerAnalyzer) markBodyValidated(handlerInfo *HandlerInfo, varName string) {
+	for i := range handlerInfo.RequestInputs {
+		input := &handlerInfo.RequestInputs[i]
+		if input.Type == "Body" && input.Name == varName {
+			input.Validated = true
+			a.Log.Debugf("    Found validation of request body: %s", varName)
+		}
+	}
+}
+
+// checkRequestHeaderGet checks if a call reads a request header through
+// the c.Request().Header.Get("name") chain
+func (a *HandlerAnalyzer) checkRequestHeaderGet(sel *ast.SelectorExpr, call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	if sel.Sel.Name != "Get" || len(call.Args) == 0 {
+		return
+	}
 
-		output := ResponseOutput{
-			Type:       outputType,
-			StatusCode: statusCode,
-			DataType:   "unknown", // Default type
-			Position:   a.FileSet.Position(call.Pos()),
+	// sel.X should be c.Request().Header
+	headerSel, ok := sel.X.(*ast.SelectorExpr)
+	if !ok || headerSel.Sel.Name != "Header" {
+		return
+	}
+
+	// headerSel.X should be the c.Request() call
+	requestCall, ok := headerSel.X.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	requestSel, ok := requestCall.Fun.(*ast.SelectorExpr)
+	if !ok || requestSel.Sel.Name != "Request" {
+		return
+	}
+	ident, ok := requestSel.X.(*ast.Ident)
+	if !ok || !contextNames[ident.Name] {
+		return
+	}
+
+	headerName := a.extractStringLiteral(call.Args[0])
+	if headerName == "" {
+		return
+	}
+
+	a.addRequestInput(handlerInfo, RequestInput{
+		Type:     "Header",
+		Name:     headerName,
+		DataType: "string",
+		Required: false,
+		Position: a.FileSet.Position(call.Pos()),
+	})
+}
+
+// addRequestInput adds a request input to the handler unless it was already recorded
+func (a *HandlerAnalyzer) addRequestInput(handlerInfo *HandlerInfo, input RequestInput) {
+	for _, existing := range handlerInfo.RequestInputs {
+		if existing.Type == input.Type && existing.Name == input.Name {
+			return
 		}
+	}
+
+	handlerInfo.RequestInputs = append(handlerInfo.RequestInputs, input)
+	a.Log.Debugf("    Found request input: %s %s", input.Type, input.Name)
+}
+
+// checkResponseOutput checks if a call writes a response, e.g.
+// c.JSON(http.StatusOK, user) or a helper matched by a registered detector
+func (a *HandlerAnalyzer) checkResponseOutput(call *ast.CallExpr, handlerInfo *HandlerInfo) {
+	match, ok := a.Detectors.DetectResponse(call)
+	if !ok {
+		return
+	}
+
+	// A redirect without a status code fails at runtime instead of
+	// responding, so it isn't documented
+	if match.Type == "Redirect" && match.Status == nil {
+		a.Log.Debugf("    Skipping redirect without a status code")
+		return
+	}
+
+	// Try to extract the status code; c.File takes none
+	statusCode := http.StatusOK
+	if code, exists := defaultStatusCodes[match.Type]; exists {
+		statusCode = code
+	}
+	statusUnknown := false
+	if match.Status != nil {
+		code, ok := types.ResolveStatusCode(match.Status, a.statusConstants)
+		if ok {
+			statusCode = code
+		}
+		statusUnknown = !ok
+	}
+
+	output := ResponseOutput{
+		Type:          match.Type,
+		StatusCode:    statusCode,
+		DataType:      "unknown", // Default type
+		Position:      a.FileSet.Position(call.Pos()),
+		StatusUnknown: statusUnknown,
+	}
+
+	// Try to determine data type for JSON/XML responses
+	// Blobs are already encoded; their type is resolved from json.Marshal
+	if (match.Type == "JSON" || match.Type == "XML") && !match.Encoded && match.Payload != nil {
+		output.DataType = a.extractDataType(match.Payload)
+	}
+
+	// Templates are named by a constant or, failing that, the expression;
+	// their model's type is resolved with the JSON response types
+	if match.Template != nil {
+		output.Template = a.extractStringLiteral(match.Template)
+		if output.Template == "" {
+			output.Template = gotypes.ExprString(match.Template)
+		}
+	}
+
+	// Determine the content type of the response body
+	output.ContentType = a.extractContentType(match.Type, match.ContentType)
 
-		// Try to determine data type for JSON/XML responses
-		if (outputType == "JSON" || outputType == "XML") && len(call.Args) > 1 {
-			output.DataType = a.extractDataType(call.Args[1])
+	a.addResponseOutput(handlerInfo, output)
+}
+
+// defaultStatusCodes maps response output types to the status code assumed
+// when their status argument is missing or can't be statically determined;
+// other types default to 200
+var defaultStatusCodes = map[string]int{
+	"NoContent": http.StatusNoContent,
+	"Redirect":  http.StatusFound,
+}
+
+// defaultContentTypes maps response output types to the content type Echo sets for them
+var defaultContentTypes = map[string]string{
+	"JSON":   "application/json",
+	"XML":    "application/xml",
+	"String": "text/plain",
+	"HTML":   "text/html",
+	"Blob":   "application/octet-stream",
+	"Stream": "application/octet-stream",
+	"File":   "application/octet-stream",
+}
+
+// echoMIMETypes maps Echo's MIME type constants to their values
+var echoMIMETypes = map[string]string{
+	"MIMEApplicationJSON":                  "application/json",
+	"MIMEApplicationJSONCharsetUTF8":       "application/json",
+	"MIMEApplicationJavaScript":            "application/javascript",
+	"MIMEApplicationJavaScriptCharsetUTF8": "application/javascript",
+	"MIMEApplicationXML":                   "application/xml",
+	"MIMEApplicationXMLCharsetUTF8":        "application/xml",
+	"MIMETextXML":                          "text/xml",
+	"MIMETextXMLCharsetUTF8":               "text/xml",
+	"MIMEApplicationForm":                  "application/x-www-form-urlencoded",
+	"MIMEApplicationProtobuf":              "application/protobuf",
+	"MIMEApplicationMsgpack":               "application/msgpack",
+	"MIMETextHTML":                         "text/html",
+	"MIMETextHTMLCharsetUTF8":              "text/html",
+	"MIMETextPlain":                        "text/plain",
+	"MIMETextPlainCharsetUTF8":             "text/plain",
+	"MIMEMultipartForm":                    "multipart/form-data",
+	"MIMEOctetStream":                      "application/octet-stream",
+}
+
+// extractContentType determines the content type of a response. Blob and
+// Stream take it as an argument, c.Blob(http.StatusOK, "application/pdf", data),
+// and File infers it from the file extension, c.File("invoice.pdf").
+func (a *HandlerAnalyzer) extractContentType(outputType string, arg ast.Expr) string {
+	contentType := ""
+	if arg != nil {
+		switch outputType {
+		case "Blob", "Stream":
+			contentType = a.extractMIMEType(arg)
+		case "File":
+			if ext := path.Ext(a.extractStringLiteral(arg)); ext != "" {
+				contentType = mime.TypeByExtension(ext)
+			}
 		}
+	}
 
+	// Drop parameters such as charset=utf-8
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return defaultContentTypes[outputType]
+}
+
+// extractMIMEType extracts a MIME type from a string literal or an Echo MIME constant
+func (a *HandlerAnalyzer) extractMIMEType(expr ast.Expr) string {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		return echoMIMETypes[sel.Sel.Name]
+	}
+	return a.extractStringLiteral(expr)
+}
+
+// addResponseOutput adds a response output to the handler, keeping a single
+// output per status code and content type, and updates the primary success
+// response. Outputs of a status with different content types are kept, as
+// written by handlers negotiating the content type with the Accept header.
+func (a *HandlerAnalyzer) addResponseOutput(handlerInfo *HandlerInfo, output ResponseOutput) {
+	a.Log.Debugf("    Found response output: %s (status %d)", output.Type, output.StatusCode)
+
+	// Replace an existing output with the same status code and content
+	// type, unless only the existing one has a resolved data type
+	replaced := false
+	for i, existing := range handlerInfo.ResponseOutputs {
+		if existing.StatusCode != output.StatusCode || existing.ContentType != output.ContentType {
+			continue
+		}
+		if output.DataType != "unknown" || existing.DataType == "unknown" {
+			handlerInfo.ResponseOutputs[i] = output
+		}
+		replaced = true
+		break
+	}
+	if !replaced {
 		handlerInfo.ResponseOutputs = append(handlerInfo.ResponseOutputs, output)
-		if a.Verbose {
-			fmt.Printf("    Found response output: %s (status %d)\n", output.Type, output.StatusCode)
+	}
+
+	// The 2xx response with the lowest status code is the primary success response
+	primary := -1
+	for i := range handlerInfo.ResponseOutputs {
+		handlerInfo.ResponseOutputs[i].Primary = false
+		code := handlerInfo.ResponseOutputs[i].StatusCode
+		if code >= 200 && code < 300 && (primary == -1 || code < handlerInfo.ResponseOutputs[primary].StatusCode) {
+			primary = i
+		}
+	}
+	if primary != -1 {
+		handlerInfo.ResponseOutputs[primary].Primary = true
+	}
+}
+
+// SetResponseDataType sets the data type of the JSON and XML response outputs
+// with the given status code, so they name the same type the response schema
+// is generated from
+func (h *HandlerInfo) SetResponseDataType(statusCode int, dataType string) {
+	for i := range h.ResponseOutputs {
+		output := &h.ResponseOutputs[i]
+		if output.StatusCode == statusCode && (output.Type == "JSON" || output.Type == "XML") {
+			output.DataType = dataType
+		}
+	}
+}
+
+// SetTemplateModel sets the data type of the HTML response outputs with
+// the given status code rendered from a template, naming the type of the
+// model the template is rendered with
+func (h *HandlerInfo) SetTemplateModel(statusCode int, template, dataType string) {
+	for i := range h.ResponseOutputs {
+		output := &h.ResponseOutputs[i]
+		if output.StatusCode == statusCode && output.Template == template {
+			output.DataType = dataType
+		}
+	}
+}
+
+// RequestBody returns the body input of the handler, or nil if it doesn't bind one
+func (h *HandlerInfo) RequestBody() *RequestInput {
+	for i := range h.RequestInputs {
+		if h.RequestInputs[i].Type == "Body" {
+			return &h.RequestInputs[i]
+		}
+	}
+	return nil
+}
+
+// BindParams replaces the body input with the parameters c.Bind fills from
+// the path, query string and headers, keeping the body only if fields are
+// bound from it
+func (h *HandlerInfo) BindParams(params []types.BoundParam, hasBody bool) {
+	body := h.RequestBody()
+	if body == nil || len(params) == 0 {
+		return
+	}
+	position := body.Position
+
+	inputs := []RequestInput{}
+	for _, input := range h.RequestInputs {
+		if input.Type != "Body" || hasBody {
+			inputs = append(inputs, input)
 		}
 	}
+	for _, param := range params {
+		exists := false
+		for _, input := range inputs {
+			if input.Type == param.Type && input.Name == param.Name {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+
+		dataType := "string"
+		if param.Field.Type != nil && param.Field.Type.Kind == types.KindBasic {
+			dataType = param.Field.Type.Name
+		}
+		inputs = append(inputs, RequestInput{
+			Type:        param.Type,
+			Name:        param.Name,
+			DataType:    dataType,
+			Description: param.Field.Description,
+			Required:    param.Required,
+			Position:    position,
+		})
+	}
+	h.RequestInputs = inputs
+}
+
+// Content types of form request bodies
+const (
+	FormURLEncodedContentType = "application/x-www-form-urlencoded"
+	MultipartFormContentType  = "multipart/form-data"
+)
+
+// FormInputs returns the form values and files the handler reads
+func (h *HandlerInfo) FormInputs() []RequestInput {
+	inputs := []RequestInput{}
+	for _, input := range h.RequestInputs {
+		if input.Type == "Form" || input.Type == "File" {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// FormContentType returns the content type of the form the handler reads:
+// multipart if it reads files, URL-encoded otherwise, and empty if it
+// doesn't read a form
+func (h *HandlerInfo) FormContentType() string {
+	contentType := ""
+	for _, input := range h.FormInputs() {
+		if input.Type == "File" {
+			return MultipartFormContentType
+		}
+		contentType = FormURLEncodedContentType
+	}
+	return contentType
+}
+
+// PrimaryResponse returns the primary success response of the handler, or nil
+// if the handler has no 2xx response
+func (h *HandlerInfo) PrimaryResponse() *ResponseOutput {
+	for i := range h.ResponseOutputs {
+		if h.ResponseOutputs[i].Primary {
+			return &h.ResponseOutputs[i]
+		}
+	}
+	return nil
 }
 
 // extractStringLiteral extracts a string literal from an AST expression
@@ -392,48 +1265,6 @@ func (a *HandlerAnalyzer) extractVariableName(expr ast.Expr) string {
 	return "unknown"
 }
 
-// extractStatusCode extracts an HTTP status code from an AST expression
-func (a *HandlerAnalyzer) extractStatusCode(expr ast.Expr) int {
-	// Handle direct integer literals
-	if lit, ok := expr.(*ast.BasicLit); ok {
-		if lit.Kind == token.INT {
-			var code int
-			fmt.Sscanf(lit.Value, "%d", &code)
-			return code
-		}
-	}
-
-	// Handle http.StatusXXX constants
-	if sel, ok := expr.(*ast.SelectorExpr); ok {
-		if ident, ok := sel.X.(*ast.Ident); ok {
-			if ident.Name == "http" {
-				switch sel.Sel.Name {
-				case "StatusOK":
-					return 200
-				case "StatusCreated":
-					return 201
-				case "StatusAccepted":
-					return 202
-				case "StatusNoContent":
-					return 204
-				case "StatusBadRequest":
-					return 400
-				case "StatusUnauthorized":
-					return 401
-				case "StatusForbidden":
-					return 403
-				case "StatusNotFound":
-					return 404
-				case "StatusInternalServerError":
-					return 500
-				}
-			}
-		}
-	}
-
-	return 200 // Default to 200 OK
-}
-
 // extractDataType extracts the data type from an AST expression
 func (a *HandlerAnalyzer) extractDataType(expr ast.Expr) string {
 	switch v := expr.(type) {
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"go/ast"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// netHTTPRequestMethods are the *http.Request methods reading a request
+// input named by their first argument
+var netHTTPRequestMethods = map[string]contextMethod{
+	"PathValue":     {InputType: "Path", Required: true},
+	"FormValue":     {InputType: "Form"},
+	"PostFormValue": {InputType: "Form"},
+	"FormFile":      {InputType: "File", DataType: "file"},
+	"Cookie":        {InputType: "Cookie"},
+}
+
+// UseNetHTTP switches the analyzer to handlers of net/http ServeMux routes,
+// with the signature func(w http.ResponseWriter, r *http.Request). Their
+// inputs are read from the *http.Request and their responses written
+// through the http.ResponseWriter, found in files.
+func (a *HandlerAnalyzer) UseNetHTTP(files []*ast.File) {
+	a.netHTTP = true
+	a.Detectors = types.NetHTTPDetectors(files)
+	a.registerNetHTTPRequestDetectors(a.Detectors)
+}
+
+// isNetHTTPHandler checks if a function has the net/http handler signature:
+// func(w http.ResponseWriter, r *http.Request)
+func (a *HandlerAnalyzer) isNetHTTPHandler(funcDecl *ast.FuncDecl) bool {
+	if funcDecl.Type.Results != nil && len(funcDecl.Type.Results.List) > 0 {
+		return false
+	}
+
+	paramTypes := []string{}
+	for _, param := range funcDecl.Type.Params.List {
+		count := len(param.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			paramTypes = append(paramTypes, a.getTypeString(param.Type))
+		}
+	}
+	return len(paramTypes) == 2 && paramTypes[0] == "http.ResponseWriter" && paramTypes[1] == "*http.Request"
+}
+
+// registerNetHTTPRequestDetectors registers the detectors of the
+// *http.Request methods and fields reading a request input
+func (a *HandlerAnalyzer) registerNetHTTPRequestDetectors(d *types.Detectors) {
+	// Inputs read by name, e.g. r.PathValue("id") or r.FormValue("name")
+	for name, method := range netHTTPRequestMethods {
+		method := method
+		d.RegisterRequest(name, types.RequestDetectorFunc(func(call *ast.CallExpr) (types.RequestMatch, bool) {
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || len(call.Args) != 1 {
+				return types.RequestMatch{}, false
+			}
+			if _, ok := sel.X.(*ast.Ident); !ok {
+				return types.RequestMatch{}, false
+			}
+			return types.RequestMatch{
+				Type:     method.InputType,
+				Name:     call.Args[0],
+				DataType: method.DataType,
+				Required: method.Required,
+			}, true
+		}))
+	}
+
+	// Query parameters, r.URL.Query().Get("page"), and headers,
+	// r.Header.Get("Authorization")
+	d.RegisterRequest("Get", types.RequestDetectorFunc(func(call *ast.CallExpr) (types.RequestMatch, bool) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || len(call.Args) != 1 {
+			return types.RequestMatch{}, false
+		}
+		switch x := sel.X.(type) {
+		case *ast.SelectorExpr:
+			if x.Sel.Name == "Header" {
+				return types.RequestMatch{Type: "Header", Name: call.Args[0]}, true
+			}
+		case *ast.CallExpr:
+			query, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok || query.Sel.Name != "Query" {
+				break
+			}
+			if url, ok := query.X.(*ast.SelectorExpr); ok && url.Sel.Name == "URL" {
+				return types.RequestMatch{Type: "Query", Name: call.Args[0]}, true
+			}
+		}
+		return types.RequestMatch{}, false
+	}))
+
+	// Request body decoding: json.NewDecoder(r.Body).Decode(&user)
+	d.RegisterRequest("Decode", types.RequestDetectorFunc(func(call *ast.CallExpr) (types.RequestMatch, bool) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || len(call.Args) != 1 {
+			return types.RequestMatch{}, false
+		}
+		decoder, ok := sel.X.(*ast.CallExpr)
+		if !ok || len(decoder.Args) != 1 || types.CallName(decoder) != "NewDecoder" {
+			return types.RequestMatch{}, false
+		}
+		if body, ok := decoder.Args[0].(*ast.SelectorExpr); !ok || body.Sel.Name != "Body" {
+			return types.RequestMatch{}, false
+		}
+		return types.RequestMatch{Type: "Body", Name: call.Args[0], Required: true}, true
+	}))
+}
@@ -1,19 +1,34 @@
 package aws
 
 import (
-	"fmt"
 	"go/ast"
 	"go/token"
 	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// Direction tells whether the analyzed code produces or consumes an event
+type Direction string
+
+const (
+	DirectionProduce Direction = "Produce"
+	DirectionConsume Direction = "Consume"
 )
 
 // EventInfo represents information about an AWS event
 type EventInfo struct {
-	Service       string         // AWS service (SNS, SQS)
-	Operation     string         // Operation (Publish, SendMessage)
-	TopicOrQueue  string         // Topic ARN or Queue URL/name
-	MessageFormat MessageFormat  // Message format details
-	Position      token.Position // Position in source code
+	Service       string                // AWS service (SNS, SQS, DynamoDB, S3, EventBridge)
+	Operation     string                // Operation (Publish, PublishBatch, SendMessage, SendMessageBatch, ReceiveMessage, PutItem, PutObject, PutEvents, LambdaEvent)
+	Direction     Direction             // Whether the event is produced or consumed
+	Target        string                // Topic ARN, queue URL, table name, bucket/key or event bus name
+	Handler       string                // Function consuming the event, for Lambda handlers
+	Function      string                // Function calling the SDK operation, for SDK calls inside a function
+	MessageFormat MessageFormat         // Message format details
+	MessageType   *types.TypeDefinition // Type JSON encoded into the message body, if resolvable
+	BatchSize     int                   // Number of batch entries with this message format, for batch operations
+	Position      token.Position        // Position in source code
 }
 
 // MessageFormat represents the format of a message
@@ -30,29 +45,108 @@ type MessageField struct {
 	Description string // Description from comments if available
 }
 
-// AWSAnalyzer analyzes AWS SDK usage for SNS/SQS
+// awsServices maps AWS SDK package names to the service they create clients for
+var awsServices = map[string]string{
+	"sns":         "SNS",
+	"sqs":         "SQS",
+	"dynamodb":    "DynamoDB",
+	"s3":          "S3",
+	"eventbridge": "EventBridge",
+}
+
+// awsOperations maps each service's client methods to the operation they perform
+var awsOperations = map[string]map[string]string{
+	"SNS": {
+		"Publish":                 "Publish",
+		"PublishWithContext":      "Publish",
+		"PublishRequest":          "Publish",
+		"PublishBatch":            "PublishBatch",
+		"PublishBatchWithContext": "PublishBatch",
+		"PublishBatchRequest":     "PublishBatch",
+	},
+	"SQS": {
+		"SendMessage":                 "SendMessage",
+		"SendMessageWithContext":      "SendMessage",
+		"SendMessageRequest":          "SendMessage",
+		"SendMessageBatch":            "SendMessageBatch",
+		"SendMessageBatchWithContext": "SendMessageBatch",
+		"SendMessageBatchRequest":     "SendMessageBatch",
+		"ReceiveMessage":              "ReceiveMessage",
+		"ReceiveMessageWithContext":   "ReceiveMessage",
+		"ReceiveMessageRequest":       "ReceiveMessage",
+		"DeleteMessage":               "DeleteMessage",
+		"DeleteMessageWithContext":    "DeleteMessage",
+		"DeleteMessageRequest":        "DeleteMessage",
+	},
+	"DynamoDB": {
+		"PutItem":               "PutItem",
+		"PutItemWithContext":    "PutItem",
+		"PutItemRequest":        "PutItem",
+		"UpdateItem":            "UpdateItem",
+		"UpdateItemWithContext": "UpdateItem",
+		"UpdateItemRequest":     "UpdateItem",
+	},
+	"S3": {
+		"PutObject":            "PutObject",
+		"PutObjectWithContext": "PutObject",
+		"PutObjectRequest":     "PutObject",
+	},
+	"EventBridge": {
+		"PutEvents":            "PutEvents",
+		"PutEventsWithContext": "PutEvents",
+		"PutEventsRequest":     "PutEvents",
+	},
+}
+
+// consumeOperations lists the operations that consume events rather than produce them
+var consumeOperations = map[string]bool{
+	"ReceiveMessage": true,
+	"DeleteMessage":  true,
+}
+
+// batchOperations maps the operations sending a slice of messages to the
+// input field holding the entries
+var batchOperations = map[string]string{
+	"PublishBatch":     "PublishBatchRequestEntries",
+	"SendMessageBatch": "Entries",
+}
+
+// lambdaEventsPackage is the import path of the aws-lambda-go event types
+const lambdaEventsPackage = "github.com/aws/aws-lambda-go/events"
+
+// lambdaEvents maps aws-lambda-go event types to the service that triggers them
+var lambdaEvents = map[string]string{
+	"SQSEvent":         "SQS",
+	"SNSEvent":         "SNS",
+	"DynamoDBEvent":    "DynamoDB",
+	"S3Event":          "S3",
+	"EventBridgeEvent": "EventBridge",
+}
+
+// AWSAnalyzer analyzes AWS SDK usage for SNS, SQS, DynamoDB, S3 and EventBridge
 type AWSAnalyzer struct {
 	FileSet       *token.FileSet
 	Events        []EventInfo
-	Verbose       bool
-	awsClientVars map[string]string // Maps variable names to AWS service types
+	Log           *logger.Logger
+	Registry      *types.TypeRegistry    // Resolves the types of JSON encoded messages, if set
+	FilePackages  map[string]string      // Package path of each file, by file name
+	awsClientVars map[string]string      // Maps variable names to AWS service types
+	tracker       *types.VariableTracker // Variables of the function being analyzed
 }
 
 // NewAWSAnalyzer creates a new AWSAnalyzer
-func NewAWSAnalyzer(fset *token.FileSet, verbose bool) *AWSAnalyzer {
+func NewAWSAnalyzer(fset *token.FileSet, log *logger.Logger) *AWSAnalyzer {
 	return &AWSAnalyzer{
 		FileSet:       fset,
 		Events:        []EventInfo{},
-		Verbose:       verbose,
+		Log:           log,
 		awsClientVars: make(map[string]string),
 	}
 }
 
 // Analyze analyzes files for AWS SDK usage
 func (a *AWSAnalyzer) Analyze(files []*ast.File) error {
-	if a.Verbose {
-		fmt.Println("Analyzing AWS SDK usage...")
-	}
+	a.Log.Debugf("Analyzing AWS SDK usage...")
 
 	for _, file := range files {
 		// First pass: identify AWS client variables
@@ -60,12 +154,13 @@ func (a *AWSAnalyzer) Analyze(files []*ast.File) error {
 
 		// Second pass: find AWS operations
 		a.findAWSOperations(file)
-	}
 
-	if a.Verbose {
-		fmt.Printf("Found %d AWS events\n", len(a.Events))
+		// Third pass: find Lambda handlers consuming AWS events
+		a.findLambdaHandlers(file)
 	}
 
+	a.Log.Debugf("Found %d AWS events", len(a.Events))
+
 	return nil
 }
 
@@ -83,9 +178,7 @@ func (a *AWSAnalyzer) identifyAWSClients(file *ast.File) {
 							service := a.getAWSService(ident.Name, sel.Sel.Name)
 							if service != "" && i < len(assign.Lhs) {
 								if lhsIdent, ok := assign.Lhs[i].(*ast.Ident); ok {
-									if a.Verbose {
-										fmt.Printf("  Found AWS client: %s (%s)\n", lhsIdent.Name, service)
-									}
+									a.Log.Debugf("  Found AWS client: %s (%s)", lhsIdent.Name, service)
 									a.awsClientVars[lhsIdent.Name] = service
 								}
 							}
@@ -100,28 +193,38 @@ func (a *AWSAnalyzer) identifyAWSClients(file *ast.File) {
 
 // getAWSService determines if a function call creates an AWS service client
 func (a *AWSAnalyzer) getAWSService(pkgName, funcName string) string {
-	// Check for AWS SDK v1 patterns
-	if pkgName == "sns" && funcName == "New" {
-		return "SNS"
-	}
-	if pkgName == "sqs" && funcName == "New" {
-		return "SQS"
-	}
-
-	// Check for AWS SDK v2 patterns
-	if pkgName == "sns" && funcName == "NewClient" {
-		return "SNS"
-	}
-	if pkgName == "sqs" && funcName == "NewClient" {
-		return "SQS"
+	// AWS SDK v1 uses New, AWS SDK v2 uses NewClient
+	if funcName != "New" && funcName != "NewClient" {
+		return ""
 	}
-
-	return ""
+	return awsServices[pkgName]
 }
 
 // findAWSOperations finds AWS operations (SNS Publish, SQS SendMessage, etc.)
 func (a *AWSAnalyzer) findAWSOperations(file *ast.File) {
-	ast.Inspect(file, func(n ast.Node) bool {
+	for _, decl := range file.Decls {
+		function := ""
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			function = funcDecl.Name.Name
+		}
+		a.findAWSOperationsIn(decl, function)
+	}
+}
+
+// findAWSOperationsIn finds the AWS operations of a declaration, attributing
+// them to the function it declares, if any
+func (a *AWSAnalyzer) findAWSOperationsIn(decl ast.Decl, function string) {
+	// Message bodies are resolved from the variables of the function
+	a.tracker = nil
+	if funcDecl, ok := decl.(*ast.FuncDecl); ok && a.Registry != nil && funcDecl.Body != nil {
+		tracker := types.NewVariableTracker(a.Registry, a.Log)
+		tracker.Package = a.FilePackages[a.FileSet.Position(funcDecl.Pos()).Filename]
+		if err := tracker.TrackFunction(funcDecl); err == nil {
+			a.tracker = tracker
+		}
+	}
+
+	ast.Inspect(decl, func(n ast.Node) bool {
 		// Look for method calls
 		if expr, ok := n.(*ast.CallExpr); ok {
 			if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
@@ -134,22 +237,29 @@ func (a *AWSAnalyzer) findAWSOperations(file *ast.File) {
 							event := EventInfo{
 								Service:   service,
 								Operation: operation,
+								Direction: DirectionProduce,
+								Function:  function,
 								Position:  a.FileSet.Position(expr.Pos()),
 							}
-
-							// Extract topic/queue and message format
-							if service == "SNS" {
-								a.extractSNSDetails(expr, &event)
-							} else if service == "SQS" {
-								a.extractSQSDetails(expr, &event)
+							if consumeOperations[operation] {
+								event.Direction = DirectionConsume
 							}
 
-							a.Events = append(a.Events, event)
+							// Extract target and message format
+							input := a.extractInputLiteral(expr)
+							if input != nil {
+								a.extractInput(input, &event)
+							}
 
-							if a.Verbose {
-								fmt.Printf("  Found AWS operation: %s %s -> %s\n",
-									event.Service, event.Operation, event.TopicOrQueue)
+							// Batch operations document each message format of their entries
+							events := []EventInfo{event}
+							if entriesField, isBatch := batchOperations[operation]; isBatch && input != nil {
+								events = a.extractBatchEntries(input, entriesField, event)
 							}
+
+							a.Events = append(a.Events, events...)
+
+							a.Log.Debugf("  Found AWS operation: %s %s -> %s", event.Service, event.Operation, event.Target)
 						}
 					}
 				}
@@ -159,53 +269,183 @@ func (a *AWSAnalyzer) findAWSOperations(file *ast.File) {
 	})
 }
 
-// getAWSOperation determines if a method call is an AWS operation of interest
-func (a *AWSAnalyzer) getAWSOperation(service, methodName string) string {
-	if service == "SNS" {
-		switch methodName {
-		case "Publish", "PublishWithContext", "PublishRequest":
-			return "Publish"
+// findLambdaHandlers finds functions taking an aws-lambda-go event, such as
+// func handler(ctx context.Context, event events.SQSEvent) error
+func (a *AWSAnalyzer) findLambdaHandlers(file *ast.File) {
+	// Find the name the events package is imported as
+	eventsPkg := ""
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != lambdaEventsPackage {
+			continue
 		}
-	} else if service == "SQS" {
-		switch methodName {
-		case "SendMessage", "SendMessageWithContext", "SendMessageRequest":
-			return "SendMessage"
-		case "SendMessageBatch", "SendMessageBatchWithContext", "SendMessageBatchRequest":
-			return "SendMessageBatch"
+		eventsPkg = "events"
+		if imp.Name != nil {
+			eventsPkg = imp.Name.Name
 		}
 	}
-	return ""
+	if eventsPkg == "" {
+		return
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Type.Params == nil {
+			continue
+		}
+
+		for _, param := range funcDecl.Type.Params.List {
+			paramType := param.Type
+			if star, ok := paramType.(*ast.StarExpr); ok {
+				paramType = star.X
+			}
+			sel, ok := paramType.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != eventsPkg {
+				continue
+			}
+			service, exists := lambdaEvents[sel.Sel.Name]
+			if !exists {
+				continue
+			}
+
+			event := EventInfo{
+				Service:   service,
+				Operation: "LambdaEvent",
+				Direction: DirectionConsume,
+				Handler:   funcDecl.Name.Name,
+				Position:  a.FileSet.Position(funcDecl.Pos()),
+			}
+			a.Events = append(a.Events, event)
+
+			a.Log.Debugf("  Found Lambda handler: %s consuming %s events", event.Handler, event.Service)
+		}
+	}
+}
+
+// getAWSOperation determines if a method call is an AWS operation of interest
+func (a *AWSAnalyzer) getAWSOperation(service, methodName string) string {
+	return awsOperations[service][methodName]
 }
 
-// extractSNSDetails extracts details from an SNS Publish call
-func (a *AWSAnalyzer) extractSNSDetails(call *ast.CallExpr, event *EventInfo) {
-	// Check for different patterns of SNS Publish calls
+// extractInputLiteral extracts the input struct literal from an AWS operation call
+func (a *AWSAnalyzer) extractInputLiteral(call *ast.CallExpr) *ast.CompositeLit {
+	var arg ast.Expr
 
 	// Pattern 1: Direct args - client.Publish(input)
 	if len(call.Args) == 1 {
-		if arg, ok := call.Args[0].(*ast.CompositeLit); ok {
-			a.extractSNSPublishInput(arg, event)
-		}
+		arg = call.Args[0]
 	}
 
 	// Pattern 2: With context - client.PublishWithContext(ctx, input)
 	if len(call.Args) == 2 {
-		if arg, ok := call.Args[1].(*ast.CompositeLit); ok {
-			a.extractSNSPublishInput(arg, event)
+		arg = call.Args[1]
+	}
+
+	// Handle address-of operator (&sns.PublishInput{...})
+	if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		arg = unary.X
+	}
+
+	if lit, ok := arg.(*ast.CompositeLit); ok {
+		return lit
+	}
+	return nil
+}
+
+// extractInput extracts the target and message format of an operation from
+// its input literal
+func (a *AWSAnalyzer) extractInput(lit *ast.CompositeLit, event *EventInfo) {
+	switch event.Service {
+	case "SNS":
+		a.extractSNSPublishInput(lit, event)
+	case "SQS":
+		a.extractSQSSendMessageInput(lit, event)
+	case "DynamoDB":
+		a.extractDynamoDBInput(lit, event)
+	case "S3":
+		a.extractS3Input(lit, event)
+	case "EventBridge":
+		a.extractEventBridgeInput(lit, event)
+	}
+}
+
+// extractBatchEntries extracts the messages of a batch operation, such as
+// SNS PublishBatch or SQS SendMessageBatch, returning one event per distinct
+// message format among its entries. Entries use the same keys as the
+// single-message inputs. If the entries aren't a literal, the event is
+// returned as is.
+func (a *AWSAnalyzer) extractBatchEntries(lit *ast.CompositeLit, entriesField string, event EventInfo) []EventInfo {
+	var entries *ast.CompositeLit
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == entriesField {
+				entries, _ = kv.Value.(*ast.CompositeLit)
+			}
 		}
 	}
+	if entries == nil {
+		return []EventInfo{event}
+	}
+
+	events := []EventInfo{}
+	formats := make(map[string]int) // Message format to index in events
+	for _, entry := range entries.Elts {
+		if unary, ok := entry.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			entry = unary.X
+		}
+		entryLit, ok := entry.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		entryEvent := event
+		entryEvent.MessageFormat = MessageFormat{}
+		entryEvent.MessageType = nil
+		a.extractInput(entryLit, &entryEvent)
+
+		format := messageFormatKey(entryEvent.MessageFormat)
+		if entryEvent.MessageType != nil {
+			format += ";" + entryEvent.MessageType.Name
+		}
+		if i, exists := formats[format]; exists {
+			events[i].BatchSize++
+			continue
+		}
+		entryEvent.BatchSize = 1
+		formats[format] = len(events)
+		events = append(events, entryEvent)
+	}
+
+	if len(events) == 0 {
+		return []EventInfo{event}
+	}
+	return events
 }
 
-// extractSNSPublishInput extracts details from an SNS PublishInput
+// messageFormatKey identifies the shape of a message by its fields, so batch
+// entries differing only in their content are grouped together
+func messageFormatKey(format MessageFormat) string {
+	fields := make([]string, 0, len(format.Fields))
+	for _, field := range format.Fields {
+		fields = append(fields, field.Name+":"+field.Type)
+	}
+	return strings.Join(fields, ",")
+}
+
+// extractSNSPublishInput extracts details from an SNS PublishInput, or a
+// PublishBatchInput and its entries
 func (a *AWSAnalyzer) extractSNSPublishInput(lit *ast.CompositeLit, event *EventInfo) {
 	for _, elt := range lit.Elts {
 		if kv, ok := elt.(*ast.KeyValueExpr); ok {
 			if key, ok := kv.Key.(*ast.Ident); ok {
 				switch key.Name {
 				case "TopicArn":
-					event.TopicOrQueue = a.extractStringValue(kv.Value)
+					event.Target = a.extractStringValue(kv.Value)
 				case "Message":
 					event.MessageFormat.RawMessage = a.extractStringValue(kv.Value)
+					event.MessageType = a.messageType(kv.Value)
 				case "MessageAttributes":
 					a.extractMessageAttributes(kv.Value, &event.MessageFormat)
 				}
@@ -214,41 +454,140 @@ func (a *AWSAnalyzer) extractSNSPublishInput(lit *ast.CompositeLit, event *Event
 	}
 }
 
-// extractSQSDetails extracts details from an SQS SendMessage call
-func (a *AWSAnalyzer) extractSQSDetails(call *ast.CallExpr, event *EventInfo) {
-	// Check for different patterns of SQS SendMessage calls
-
-	// Pattern 1: Direct args - client.SendMessage(input)
-	if len(call.Args) == 1 {
-		if arg, ok := call.Args[0].(*ast.CompositeLit); ok {
-			a.extractSQSSendMessageInput(arg, event)
+// extractSQSSendMessageInput extracts details from an SQS SendMessageInput,
+// a SendMessageBatchInput and its entries, or the queue URL of a ReceiveMessageInput or DeleteMessageInput
+func (a *AWSAnalyzer) extractSQSSendMessageInput(lit *ast.CompositeLit, event *EventInfo) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				switch key.Name {
+				case "QueueUrl":
+					event.Target = a.extractStringValue(kv.Value)
+				case "MessageBody":
+					event.MessageFormat.RawMessage = a.extractStringValue(kv.Value)
+					event.MessageType = a.messageType(kv.Value)
+				case "MessageAttributes":
+					a.extractMessageAttributes(kv.Value, &event.MessageFormat)
+				}
+			}
 		}
 	}
+}
 
-	// Pattern 2: With context - client.SendMessageWithContext(ctx, input)
-	if len(call.Args) == 2 {
-		if arg, ok := call.Args[1].(*ast.CompositeLit); ok {
-			a.extractSQSSendMessageInput(arg, event)
+// extractDynamoDBInput extracts details from a DynamoDB PutItemInput or UpdateItemInput
+func (a *AWSAnalyzer) extractDynamoDBInput(lit *ast.CompositeLit, event *EventInfo) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				switch key.Name {
+				case "TableName":
+					event.Target = a.extractStringValue(kv.Value)
+				case "Item", "Key":
+					a.extractItemAttributes(kv.Value, &event.MessageFormat)
+				}
+			}
 		}
 	}
 }
 
-// extractSQSSendMessageInput extracts details from an SQS SendMessageInput
-func (a *AWSAnalyzer) extractSQSSendMessageInput(lit *ast.CompositeLit, event *EventInfo) {
+// extractS3Input extracts details from an S3 PutObjectInput
+func (a *AWSAnalyzer) extractS3Input(lit *ast.CompositeLit, event *EventInfo) {
+	var bucket, objectKey string
 	for _, elt := range lit.Elts {
 		if kv, ok := elt.(*ast.KeyValueExpr); ok {
 			if key, ok := kv.Key.(*ast.Ident); ok {
 				switch key.Name {
-				case "QueueUrl":
-					event.TopicOrQueue = a.extractStringValue(kv.Value)
-				case "MessageBody":
-					event.MessageFormat.RawMessage = a.extractStringValue(kv.Value)
-				case "MessageAttributes":
-					a.extractMessageAttributes(kv.Value, &event.MessageFormat)
+				case "Bucket":
+					bucket = a.extractStringValue(kv.Value)
+				case "Key":
+					objectKey = a.extractStringValue(kv.Value)
 				}
 			}
 		}
 	}
+
+	event.Target = bucket
+	if objectKey != "" {
+		event.Target = bucket + "/" + objectKey
+	}
+}
+
+// extractEventBridgeInput extracts details from an EventBridge PutEventsInput
+func (a *AWSAnalyzer) extractEventBridgeInput(lit *ast.CompositeLit, event *EventInfo) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "Entries" {
+			continue
+		}
+
+		// Entries is a slice of PutEventsRequestEntry literals
+		entries, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, entry := range entries.Elts {
+			if unary, ok := entry.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				entry = unary.X
+			}
+			entryLit, ok := entry.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, entryElt := range entryLit.Elts {
+				if entryKV, ok := entryElt.(*ast.KeyValueExpr); ok {
+					if entryKey, ok := entryKV.Key.(*ast.Ident); ok {
+						switch entryKey.Name {
+						case "EventBusName":
+							event.Target = a.extractStringValue(entryKV.Value)
+						case "Detail":
+							event.MessageFormat.RawMessage = a.extractStringValue(entryKV.Value)
+							event.MessageType = a.messageType(entryKV.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Events without an explicit bus go to the account's default bus
+	if event.Target == "" {
+		event.Target = "default"
+	}
+}
+
+// extractItemAttributes extracts DynamoDB item attributes from an expression
+func (a *AWSAnalyzer) extractItemAttributes(expr ast.Expr, format *MessageFormat) {
+	// Handle composite literals (map[string]*dynamodb.AttributeValue{...})
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				fieldName := a.extractStringValue(kv.Key)
+				fieldType := "string" // Default type
+
+				// The attribute value field name (S, N, BOOL, ...) is the DynamoDB type
+				if valueLit, ok := kv.Value.(*ast.CompositeLit); ok {
+					for _, valueElt := range valueLit.Elts {
+						if valueKV, ok := valueElt.(*ast.KeyValueExpr); ok {
+							if key, ok := valueKV.Key.(*ast.Ident); ok {
+								fieldType = key.Name
+							}
+						}
+					}
+				}
+
+				format.Fields = append(format.Fields, MessageField{
+					Name: fieldName,
+					Type: fieldType,
+				})
+
+				format.IsStructured = true
+			}
+		}
+	}
 }
 
 // extractMessageAttributes extracts message attributes from an expression
@@ -293,10 +632,50 @@ func (a *AWSAnalyzer) extractStringValue(expr ast.Expr) string {
 		}
 	case *ast.Ident:
 		return v.Name // Variable name
+	case *ast.CallExpr:
+		// Unwrap pointer helpers like aws.String("value")
+		if sel, ok := v.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "String" && len(v.Args) == 1 {
+			return a.extractStringValue(v.Args[0])
+		}
 	}
 	return ""
 }
 
+// messageType returns the type JSON encoded into a message body, such as
+// Order for aws.String(string(message)) after message, _ := json.Marshal(order),
+// or nil if it can't be resolved
+func (a *AWSAnalyzer) messageType(expr ast.Expr) *types.TypeDefinition {
+	if a.tracker == nil {
+		return nil
+	}
+
+	// Unwrap aws.String(...) and string(...) conversions
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			break
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if fun.Sel.Name != "String" {
+				return nil
+			}
+		case *ast.Ident:
+			if fun.Name != "string" {
+				return nil
+			}
+		default:
+			return nil
+		}
+		expr = call.Args[0]
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok {
+		return a.tracker.MarshaledTypeOf(ident)
+	}
+	return nil
+}
+
 // GetEvents returns all found AWS events
 func (a *AWSAnalyzer) GetEvents() []EventInfo {
 	return a.Events
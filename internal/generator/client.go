@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+)
+
+// clientEndpoint describes a route for the generated API clients
+type clientEndpoint struct {
+	FuncName     string
+	Method       string
+	Path         string
+	PathParams   []string
+	QueryParams  []string
+	HeaderParams []string
+	HasBody      bool
+	RequestKey   string // Key into RequestTypes of the body type, if resolved
+	ResponseKey  string // Key into ResponseTypes of the success response, if resolved
+	NoContent    bool   // Whether the success response has no body
+}
+
+// clientEndpoints builds a client endpoint for every route
+func (g *DocGenerator) clientEndpoints() []clientEndpoint {
+	// Routes registered more than once only get one endpoint
+	routes := []scanner.RouteInfo{}
+	seen := make(map[string]bool)
+	for _, route := range g.Routes {
+		key := route.Method + " " + route.Path
+		if !seen[key] {
+			seen[key] = true
+			routes = append(routes, route)
+		}
+	}
+
+	// Handlers registered on several routes need a name per route
+	handlerCount := make(map[string]int)
+	for _, route := range routes {
+		handlerCount[route.HandlerName]++
+	}
+
+	endpoints := []clientEndpoint{}
+	for _, route := range routes {
+		funcName := route.HandlerName
+		if handlerCount[funcName] > 1 || !token.IsIdentifier(funcName) || strings.HasPrefix(funcName, "anonymous") {
+			funcName = operationName(route)
+		}
+
+		endpoint := clientEndpoint{
+			FuncName: funcName,
+			Method:   route.Method,
+			Path:     route.Path,
+		}
+
+		// Collect path parameters in order
+		endpoint.PathParams = pathParamNames(route.Path)
+
+		handler := g.getHandlerForRoute(route)
+		if handler != nil {
+			// Collect query, header and body inputs
+			for _, input := range handler.RequestInputs {
+				switch input.Type {
+				case "Query":
+					endpoint.QueryParams = appendUnique(endpoint.QueryParams, input.Name)
+				case "Header":
+					endpoint.HeaderParams = appendUnique(endpoint.HeaderParams, input.Name)
+				case "Body":
+					endpoint.HasBody = true
+				}
+			}
+			if endpoint.HasBody && g.RequestTypes[handler.Name] != nil {
+				endpoint.RequestKey = handler.Name
+			}
+
+			// Use the primary success response as the result type
+			if output := handler.PrimaryResponse(); output != nil {
+				responseKey := fmt.Sprintf("%s_%d", handler.Name, output.StatusCode)
+				if responseInfo, exists := g.ResponseTypes[responseKey]; exists && responseInfo.Type != nil && output.Type == "JSON" {
+					endpoint.ResponseKey = responseKey
+				} else if output.Type == "NoContent" {
+					endpoint.NoContent = true
+				}
+			}
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// urlTemplate returns the endpoint path with each path parameter replaced by
+// the result of param, which receives the parameter name
+func (e clientEndpoint) urlTemplate(param func(name string) string) string {
+	return pathTemplate(e.Path, param)
+}
+
+// responseType returns the result type of the endpoint, naming resolved
+// responses with typeName
+func (e clientEndpoint) responseType(typeName func(string) string, noContent, unknown string) string {
+	if e.ResponseKey != "" {
+		return typeName(e.ResponseKey)
+	}
+	if e.NoContent {
+		return noContent
+	}
+	return unknown
+}
+
+// requestType returns the body type of the endpoint, naming resolved
+// request bodies with typeName
+func (e clientEndpoint) requestType(typeName func(string) string, unknown string) string {
+	if e.RequestKey != "" {
+		return typeName(e.RequestKey)
+	}
+	return unknown
+}
+
+// requestTypeName returns the type name for a request key (handler name)
+func requestTypeName(requestKey string) string {
+	return exportedName(requestKey) + "Body"
+}
+
+// responseTypeName returns the type name for a response key (handler_status)
+func responseTypeName(responseKey string) string {
+	return exportedName(strings.Replace(responseKey, "_", "", -1)) + "Response"
+}
+
+// operationName derives a function name from a route's method and path
+func operationName(route scanner.RouteInfo) string {
+	name := strings.ToLower(route.Method)
+	for _, part := range scanner.SplitPath(route.Path) {
+		switch {
+		case part.Param:
+			name += "By" + exportedName(part.Text)
+		case part.Wildcard:
+			name += "By" + exportedName(WildcardParamName)
+		default:
+			name += exportedName(part.Text)
+		}
+	}
+	return name
+}
+
+// exportedName converts a name to PascalCase, dropping non-identifier characters
+func exportedName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			upper = true
+			continue
+		}
+		if upper {
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// appendUnique appends a value to a slice if it isn't already present
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// clientGenerator returns a generator of the given format for an API with a
+// createUser handler binding a *User body and responding with the User
+func clientGenerator(format string) *DocGenerator {
+	user := &types.TypeDefinition{
+		Name:    "User",
+		Kind:    types.KindStruct,
+		Package: "main",
+		Fields: []*types.FieldDefinition{
+			{Name: "Name", JSONName: "name", Type: &types.TypeDefinition{Name: "string", Kind: types.KindBasic, BasicType: "string"}},
+			{Name: "Email", JSONName: "email", Type: &types.TypeDefinition{Name: "string", Kind: types.KindBasic, BasicType: "string"}},
+			{Name: "Reference", JSONName: "reference", Type: &types.TypeDefinition{Name: "uuid.UUID", Kind: types.KindBasic, BasicType: "uuid.UUID"}},
+			{Name: "Timeout", JSONName: "timeout", Type: &types.TypeDefinition{Name: "time.Duration", Kind: types.KindBasic, BasicType: "time.Duration"}},
+			{Name: "Balance", JSONName: "balance", Type: &types.TypeDefinition{Name: "decimal.Decimal", Kind: types.KindBasic, BasicType: "decimal.Decimal"}},
+		},
+	}
+
+	route := scanner.RouteInfo{Method: "POST", Path: "/users", HandlerName: "createUser"}
+	g := NewDocGenerator("", format, nil)
+	g.SetData([]scanner.RouteInfo{route}, map[string]*analyzer.HandlerInfo{
+		"createUser": {
+			Name:            "createUser",
+			Route:           route,
+			RequestInputs:   []analyzer.RequestInput{{Type: "Body", Name: "user", Required: true}},
+			ResponseOutputs: []analyzer.ResponseOutput{{Type: "JSON", StatusCode: 201, Primary: true}},
+		},
+	}, nil)
+	g.SetRequestTypes(map[string]*types.TypeDefinition{
+		"createUser": {Kind: types.KindPointer, ElementType: user},
+	})
+	g.SetResponseTypes(map[string]*types.ResponseInfo{
+		"createUser_201": {StatusCode: 201, Type: user},
+	})
+	return g
+}
+
+// generateOutput runs a generator, writing to a temporary file, and returns
+// the output
+func generateOutput(t *testing.T, g *DocGenerator, name string) string {
+	t.Helper()
+
+	g.OutputFile = filepath.Join(t.TempDir(), name)
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Generate() = %v", err)
+	}
+	data, err := os.ReadFile(g.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestTypeScriptClientRequestBody(t *testing.T) {
+	g := clientGenerator("typescript")
+	g.TypeScriptClient = true
+	output := generateOutput(t, g, "api.ts")
+
+	for _, want := range []string{
+		"export interface User {",
+		"export type CreateUserBody = User;",
+		"  body: CreateUserBody;",
+		"Promise<CreateUser201Response>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TypeScript output has no %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestGoClientRequestBody(t *testing.T) {
+	output := generateOutput(t, clientGenerator("go-client"), "client.go")
+
+	for _, want := range []string{
+		"type CreateUserBody = User\n",
+		"\tBody CreateUserBody\n",
+		"\tReference string ",
+		"\tTimeout   time.Duration ",
+		"\tBalance   json.Number ",
+		"(*CreateUser201Response, error)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Go client has no %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Body interface{}") {
+		t.Errorf("Go client has an untyped body:\n%s", output)
+	}
+}
+
+func TestTypeScriptGenericTypeNames(t *testing.T) {
+	user := &types.TypeDefinition{
+		Name:    "User",
+		Kind:    types.KindStruct,
+		Package: "main",
+		Fields: []*types.FieldDefinition{
+			{Name: "Name", JSONName: "name", Type: &types.TypeDefinition{Name: "string", Kind: types.KindBasic, BasicType: "string"}},
+		},
+	}
+	page := &types.TypeDefinition{
+		Name:    "Page[User]",
+		Kind:    types.KindStruct,
+		Package: "main",
+		Fields: []*types.FieldDefinition{
+			{Name: "Items", JSONName: "items", Type: &types.TypeDefinition{Kind: types.KindArray, ElementType: user}},
+			{Name: "Total", JSONName: "total", Type: &types.TypeDefinition{Name: "int", Kind: types.KindBasic, BasicType: "int"}},
+		},
+	}
+
+	route := scanner.RouteInfo{Method: "GET", Path: "/users", HandlerName: "listUsers"}
+	g := NewDocGenerator("", "typescript", nil)
+	g.SetData([]scanner.RouteInfo{route}, map[string]*analyzer.HandlerInfo{
+		"listUsers": {
+			Name:            "listUsers",
+			Route:           route,
+			ResponseOutputs: []analyzer.ResponseOutput{{Type: "JSON", StatusCode: 200, Primary: true}},
+		},
+	}, nil)
+	g.SetResponseTypes(map[string]*types.ResponseInfo{
+		"listUsers_200": {StatusCode: 200, Type: page},
+	})
+	g.TypeScriptClient = true
+	output := generateOutput(t, g, "api.ts")
+
+	for _, want := range []string{
+		"export interface PageUser {",
+		"  items: User[];",
+		"export type ListUsers200Response = PageUser;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TypeScript output has no %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "[User]") {
+		t.Errorf("TypeScript output has a Go generic type name:\n%s", output)
+	}
+}
@@ -0,0 +1,360 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// APIDocument is the normalized form of an analyzed API, used to compare revisions
+type APIDocument struct {
+	Operations map[string]*APIOperation // Keyed by "METHOD /path"
+	Middleware []string                 // Global middleware
+}
+
+// APIOperation describes the contract of a single operation
+type APIOperation struct {
+	Method     string
+	Path       string
+	Parameters map[string]Parameter   // Keyed by "in name"
+	Responses  map[string]interface{} // Response schemas keyed by status code, with $refs resolved
+	Middleware []string               // Group and route-level middleware
+}
+
+// APIChange represents a difference between two revisions of an API
+type APIChange struct {
+	Operation   string // "METHOD /path"
+	Description string
+	Breaking    bool
+}
+
+// maxSchemaDepth bounds nested $ref resolution so recursive schemas terminate
+const maxSchemaDepth = 16
+
+// APIDocument builds the normalized API document from the analysis results
+func (g *DocGenerator) APIDocument() *APIDocument {
+	spec := g.createOpenAPISpec()
+
+	// Round-trip the components through JSON so schemas can be compared structurally
+	components := make(map[string]interface{})
+	for name, schema := range spec.Components.Schemas {
+		components[name] = normalizeSchema(schema)
+	}
+
+	doc := &APIDocument{
+		Operations: make(map[string]*APIOperation),
+		Middleware: spec.Middleware,
+	}
+	for path, item := range spec.Paths {
+		for method, operation := range item {
+			apiOperation := &APIOperation{
+				Method:     strings.ToUpper(strings.TrimPrefix(method, "x-")),
+				Path:       path,
+				Parameters: make(map[string]Parameter),
+				Responses:  make(map[string]interface{}),
+				Middleware: operation.Middleware,
+			}
+			for _, param := range operation.Parameters {
+				apiOperation.Parameters[param.In+" "+param.Name] = param
+			}
+			for status, response := range operation.Responses {
+				var schema interface{}
+				for _, media := range response.Content {
+					schema = resolveSchemaRefs(normalizeSchema(media.Schema), components, 0)
+					break
+				}
+				apiOperation.Responses[status] = schema
+			}
+			doc.Operations[apiOperation.Method+" "+path] = apiOperation
+		}
+	}
+
+	return doc
+}
+
+// DiffAPIDocuments compares two API documents and returns the changes from
+// baseline to current, sorted by operation
+func DiffAPIDocuments(baseline, current *APIDocument) []APIChange {
+	changes := diffMiddleware("*", baseline.Middleware, current.Middleware)
+
+	for key, operation := range current.Operations {
+		if _, exists := baseline.Operations[key]; !exists {
+			changes = append(changes, APIChange{Operation: key, Description: "Route added"})
+			continue
+		}
+		changes = append(changes, diffOperations(key, baseline.Operations[key], operation)...)
+	}
+	for key := range baseline.Operations {
+		if _, exists := current.Operations[key]; !exists {
+			changes = append(changes, APIChange{Operation: key, Description: "Route removed", Breaking: true})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Operation != changes[j].Operation {
+			return changes[i].Operation < changes[j].Operation
+		}
+		return changes[i].Description < changes[j].Description
+	})
+	return changes
+}
+
+// HasBreakingChanges checks if any of the changes is breaking
+func HasBreakingChanges(changes []APIChange) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// diffOperations compares the parameters and responses of an operation
+func diffOperations(key string, baseline, current *APIOperation) []APIChange {
+	changes := []APIChange{}
+
+	// Parameters: new required parameters break existing clients
+	for name, param := range current.Parameters {
+		old, exists := baseline.Parameters[name]
+		switch {
+		case !exists:
+			changes = append(changes, APIChange{
+				Operation:   key,
+				Description: fmt.Sprintf("Parameter %s added (required: %v)", name, param.Required),
+				Breaking:    param.Required,
+			})
+		case param.Required && !old.Required:
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Parameter %s is now required", name), Breaking: true})
+		case !param.Required && old.Required:
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Parameter %s is now optional", name)})
+		}
+	}
+	for name := range baseline.Parameters {
+		if _, exists := current.Parameters[name]; !exists {
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Parameter %s removed", name)})
+		}
+	}
+
+	changes = append(changes, diffMiddleware(key, baseline.Middleware, current.Middleware)...)
+
+	// Responses: removed statuses and fields clients may rely on are breaking
+	for status, schema := range current.Responses {
+		oldSchema, exists := baseline.Responses[status]
+		if !exists {
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Response %s added", status)})
+			continue
+		}
+		for _, change := range diffSchemas("", oldSchema, schema) {
+			change.Operation = key
+			change.Description = fmt.Sprintf("Response %s: %s", status, change.Description)
+			changes = append(changes, change)
+		}
+	}
+	for status := range baseline.Responses {
+		if _, exists := current.Responses[status]; !exists {
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Response %s removed", status), Breaking: true})
+		}
+	}
+
+	return changes
+}
+
+// diffMiddleware reports middleware added to or removed from an operation,
+// or globally. Middleware changes behavior but not the contract, so they
+// aren't breaking.
+func diffMiddleware(key string, baseline, current []string) []APIChange {
+	changes := []APIChange{}
+	for _, name := range current {
+		if !containsString(baseline, name) {
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Middleware %s added", name)})
+		}
+	}
+	for _, name := range baseline {
+		if !containsString(current, name) {
+			changes = append(changes, APIChange{Operation: key, Description: fmt.Sprintf("Middleware %s removed", name)})
+		}
+	}
+	return changes
+}
+
+// containsString checks if a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// diffSchemas compares two normalized schemas. Added properties are
+// non-breaking; removed properties and changed types are breaking.
+func diffSchemas(path string, baseline, current interface{}) []APIChange {
+	if reflect.DeepEqual(baseline, current) {
+		return nil
+	}
+
+	location := "body"
+	if path != "" {
+		location = "field " + path
+	}
+
+	oldSchema, oldOK := baseline.(map[string]interface{})
+	newSchema, newOK := current.(map[string]interface{})
+
+	// Schemas that couldn't be statically determined can't be compared
+	if isUnknownSchema(oldSchema) || isUnknownSchema(newSchema) {
+		return []APIChange{{Description: fmt.Sprintf("%s schema changed", location)}}
+	}
+	if !oldOK || !newOK || oldSchema["type"] != newSchema["type"] {
+		return []APIChange{{Description: fmt.Sprintf("%s type changed", location), Breaking: true}}
+	}
+
+	changes := []APIChange{}
+
+	// Compare object properties
+	oldProps, _ := oldSchema["properties"].(map[string]interface{})
+	newProps, _ := newSchema["properties"].(map[string]interface{})
+	for name, prop := range newProps {
+		propPath := joinSchemaPath(path, name)
+		if oldProp, exists := oldProps[name]; exists {
+			changes = append(changes, diffSchemas(propPath, oldProp, prop)...)
+		} else {
+			changes = append(changes, APIChange{Description: fmt.Sprintf("field %s added", propPath)})
+		}
+	}
+	for name := range oldProps {
+		if _, exists := newProps[name]; !exists {
+			changes = append(changes, APIChange{Description: fmt.Sprintf("field %s removed", joinSchemaPath(path, name)), Breaking: true})
+		}
+	}
+
+	// Compare array items and map values
+	if !reflect.DeepEqual(oldSchema["items"], newSchema["items"]) {
+		changes = append(changes, diffSchemas(path+"[]", oldSchema["items"], newSchema["items"])...)
+	}
+	if !reflect.DeepEqual(oldSchema["additionalProperties"], newSchema["additionalProperties"]) {
+		changes = append(changes, diffSchemas(path+"{}", oldSchema["additionalProperties"], newSchema["additionalProperties"])...)
+	}
+
+	// Anything else (formats, oneOf, ...) is reported without details
+	if len(changes) == 0 {
+		changes = append(changes, APIChange{Description: fmt.Sprintf("%s schema changed", location)})
+	}
+	return changes
+}
+
+// isUnknownSchema checks if a normalized schema is the fallback for types
+// that couldn't be statically determined
+func isUnknownSchema(schema map[string]interface{}) bool {
+	return schema != nil && schema["description"] == types.UnknownTypeDescription && schema["type"] == nil
+}
+
+// joinSchemaPath appends a property name to a schema path
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// normalizeSchema converts a schema to generic JSON values
+func normalizeSchema(schema interface{}) interface{} {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil
+	}
+	return normalized
+}
+
+// resolveSchemaRefs replaces component $refs in a normalized schema with the
+// referenced schemas, so schemas compare equal regardless of component names
+func resolveSchemaRefs(schema interface{}, components map[string]interface{}, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return schema
+	}
+
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		if ref, ok := s["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			if component, exists := components[name]; exists {
+				return resolveSchemaRefs(component, components, depth+1)
+			}
+		}
+		resolved := make(map[string]interface{}, len(s))
+		for key, value := range s {
+			resolved[key] = resolveSchemaRefs(value, components, depth)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(s))
+		for i, value := range s {
+			resolved[i] = resolveSchemaRefs(value, components, depth)
+		}
+		return resolved
+	}
+	return schema
+}
+
+// WriteDiffMarkdown writes a Markdown report of the changes between two revisions
+func WriteDiffMarkdown(outputFile, baseline, current string, changes []APIChange) error {
+	tmpl, err := template.New("diff").Parse(diffTemplate)
+	if err != nil {
+		return fmt.Errorf("error creating template: %v", err)
+	}
+
+	data := struct {
+		Baseline    string
+		Current     string
+		Changes     []APIChange
+		Breaking    bool
+		GeneratedAt string
+	}{
+		Baseline:    baseline,
+		Current:     current,
+		Changes:     changes,
+		Breaking:    HasBreakingChanges(changes),
+		GeneratedAt: time.Now().Format("January 2, 2006 15:04:05"),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("error executing template: %v", err)
+	}
+
+	if err := writeOutput(outputFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing diff report: %v", err)
+	}
+
+	return nil
+}
+
+// Markdown template for API diff reports
+const diffTemplate = `# API Changes
+
+*Generated at: {{.GeneratedAt}}*
+
+Comparing ` + "`{{.Baseline}}`" + ` (baseline) to ` + "`{{.Current}}`" + `.
+
+{{if .Changes}}
+{{- if .Breaking}}**This revision contains breaking changes.**{{else}}No breaking changes.{{end}}
+
+| Operation | Change | Breaking |
+|-----------|--------|----------|
+{{range .Changes}}| {{.Operation}} | {{.Description}} | {{if .Breaking}}yes{{else}}no{{end}} |
+{{end}}
+{{- else}}
+*No API changes*
+{{end}}`
@@ -1,25 +1,31 @@
 package generator
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/user/golang-echo-analyzer/internal/analyzer"
 	"github.com/user/golang-echo-analyzer/internal/aws"
+	"github.com/user/golang-echo-analyzer/internal/logger"
 	"github.com/user/golang-echo-analyzer/internal/scanner"
 	"github.com/user/golang-echo-analyzer/internal/types"
 )
 
 // Format constants
 const (
-	FormatMarkdown = "markdown"
-	FormatJSON     = "json"
-	FormatOpenAPI  = "openapi"
+	FormatMarkdown   = "markdown"
+	FormatJSON       = "json"
+	FormatOpenAPI    = "openapi"
+	FormatTypeScript = "typescript"
+	FormatGoClient   = "go-client"
 )
 
 // DocGenerator generates documentation from analysis results
@@ -29,21 +35,59 @@ type DocGenerator struct {
 	Events          []aws.EventInfo
 	OutputFile      string
 	Format          string
-	Verbose         bool
+	Log             *logger.Logger
 	SchemaGenerator *types.SchemaGenerator
 	ResponseTypes   map[string]*types.ResponseInfo
+	RequestTypes    map[string]*types.TypeDefinition // Request body types, by handler name
+
+	// TypeScriptClient adds a typed client function per endpoint to TypeScript output
+	TypeScriptClient bool
+
+	// ClientPackage is the package name of the generated Go client
+	ClientPackage string
+
+	// RepoRoot is the root of the analyzed repository; source locations are
+	// reported relative to it
+	RepoRoot string
+
+	// Middleware is the global middleware applied to every route
+	Middleware []string
+
+	// ErrorHandler is the HTTP error handler; its JSON response documents
+	// the errors returned by every route
+	ErrorHandler *analyzer.HandlerInfo
+
+	// ErrorType names the type of the error responses, optionally qualified
+	// by its package name. If empty, it is the type of most 4xx and 5xx
+	// responses. In OpenAPI output, error responses of the type reference a
+	// single component schema named after it.
+	ErrorType string
+
+	// SchemaDraft is the JSON Schema draft of the component schemas of
+	// OpenAPI output. If set, the output is OpenAPI 3.1, which declares it as
+	// jsonSchemaDialect; otherwise it is OpenAPI 3.0 with its own schema
+	// dialect.
+	SchemaDraft string
+
+	// SecurityMiddleware maps custom middleware constructors to the kind of
+	// security they enforce, in addition to Echo's own middleware
+	SecurityMiddleware map[string]string
+
+	// SkipValidation writes OpenAPI output without validating it first
+	SkipValidation bool
 }
 
 // NewDocGenerator creates a new DocGenerator
-func NewDocGenerator(outputFile, format string, verbose bool) *DocGenerator {
+func NewDocGenerator(outputFile, format string, log *logger.Logger) *DocGenerator {
 	return &DocGenerator{
 		Routes:        []scanner.RouteInfo{},
 		Handlers:      make(map[string]*analyzer.HandlerInfo),
 		Events:        []aws.EventInfo{},
 		OutputFile:    outputFile,
 		Format:        format,
-		Verbose:       verbose,
+		Log:           log,
 		ResponseTypes: make(map[string]*types.ResponseInfo),
+		RequestTypes:  make(map[string]*types.TypeDefinition),
 	}
 }
 
@@ -54,6 +98,16 @@ func (g *DocGenerator) SetData(routes []scanner.RouteInfo, handlers map[string]*
 	g.Events = events
 }
 
+// SetMiddleware sets the global middleware
+func (g *DocGenerator) SetMiddleware(middleware []string) {
+	g.Middleware = middleware
+}
+
+// SetErrorHandler sets the HTTP error handler
+func (g *DocGenerator) SetErrorHandler(errorHandler *analyzer.HandlerInfo) {
+	g.ErrorHandler = errorHandler
+}
+
 // SetSchemaGenerator sets the schema generator
 func (g *DocGenerator) SetSchemaGenerator(schemaGenerator *types.SchemaGenerator) {
 	g.SchemaGenerator = schemaGenerator
@@ -64,16 +118,21 @@ func (g *DocGenerator) SetResponseTypes(responseTypes map[string]*types.Response
 	g.ResponseTypes = responseTypes
 }
 
+// SetRequestTypes sets the request body types
+func (g *DocGenerator) SetRequestTypes(requestTypes map[string]*types.TypeDefinition) {
+	g.RequestTypes = requestTypes
+}
+
 // Generate generates documentation based on the analysis results
 func (g *DocGenerator) Generate() error {
-	if g.Verbose {
-		fmt.Println("Generating documentation...")
-	}
+	g.Log.Debugf("Generating documentation...")
 
 	// Create output directory if it doesn't exist
-	outputDir := filepath.Dir(g.OutputFile)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("error creating output directory: %v", err)
+	if g.OutputFile != StdoutOutput {
+		outputDir := filepath.Dir(g.OutputFile)
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("error creating output directory: %v", err)
+		}
 	}
 
 	// Generate documentation based on format
@@ -85,6 +144,10 @@ func (g *DocGenerator) Generate() error {
 		err = g.generateJSON()
 	case FormatOpenAPI:
 		err = g.generateOpenAPI()
+	case FormatTypeScript:
+		err = g.generateTypeScript()
+	case FormatGoClient:
+		err = g.generateGoClient()
 	default:
 		err = fmt.Errorf("unsupported format: %s", g.Format)
 	}
@@ -93,17 +156,24 @@ func (g *DocGenerator) Generate() error {
 		return err
 	}
 
-	if g.Verbose {
-		fmt.Printf("Documentation generated: %s\n", g.OutputFile)
-	}
+	g.Log.Debugf("Documentation generated: %s", g.OutputFile)
 
 	return nil
 }
 
 // generateMarkdown generates Markdown documentation
 func (g *DocGenerator) generateMarkdown() error {
+	// Link each route to its detailed section
+	anchors := routeAnchors(g.Routes, g.isDeprecated)
+
 	// Create the template
-	tmpl, err := template.New("markdown").Parse(markdownTemplate)
+	tmpl, err := template.New("markdown").Funcs(template.FuncMap{
+		"source":     g.sourceLocation,
+		"anchor":     func(i int) string { return anchors[i] },
+		"handler":    g.getHandlerForRoute,
+		"describe":   outputDescription,
+		"deprecated": g.isDeprecated,
+	}).Parse(markdownTemplate)
 	if err != nil {
 		return fmt.Errorf("error creating template: %v", err)
 	}
@@ -113,53 +183,171 @@ func (g *DocGenerator) generateMarkdown() error {
 		Routes          []scanner.RouteInfo
 		Handlers        map[string]*analyzer.HandlerInfo
 		Events          []aws.EventInfo
+		Middleware      []string
 		ResponseTypes   map[string]*types.ResponseInfo
+		RequestTypes    map[string]*types.TypeDefinition
 		SchemaGenerator *types.SchemaGenerator
+		UnknownType     *types.TypeDefinition
+		ErrorHandler    *analyzer.HandlerInfo
+		Contents        []tocGroup
+		ServiceHeadings []string
 		GeneratedAt     string
 	}{
 		Routes:          g.Routes,
 		Handlers:        g.Handlers,
 		Events:          g.Events,
+		Middleware:      g.Middleware,
 		ResponseTypes:   g.ResponseTypes,
+		RequestTypes:    g.RequestTypes,
 		SchemaGenerator: g.SchemaGenerator,
+		UnknownType:     types.UnknownType(),
+		ErrorHandler:    g.ErrorHandler,
+		Contents:        tableOfContents(g.Routes, anchors),
+		ServiceHeadings: serviceHeadings(g.Routes),
 		GeneratedAt:     time.Now().Format("January 2, 2006 15:04:05"),
 	}
 
-	// Create output file
-	file, err := os.Create(g.OutputFile)
-	if err != nil {
-		return fmt.Errorf("error creating output file: %v", err)
-	}
-	defer file.Close()
-
 	// Execute the template
-	if err := tmpl.Execute(file, data); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("error executing template: %v", err)
 	}
 
+	// Write to file
+	if err := writeOutput(g.OutputFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing output file: %v", err)
+	}
+
 	return nil
 }
 
-// generateJSON generates JSON documentation
-func (g *DocGenerator) generateJSON() error {
-	// For now, just generate Markdown as a fallback
-	// TODO: Implement proper JSON output
-	return g.generateMarkdown()
+// tocGroup is a section of the table of contents, listing the endpoints of a resource
+type tocGroup struct {
+	Name    string
+	Entries []tocEntry
+}
+
+// tocEntry links an endpoint in the table of contents to its detailed section
+type tocEntry struct {
+	Method string
+	Path   string
+	Anchor string
+}
+
+// tableOfContents groups routes by resource (the first path segment), in
+// the order the resources are first registered. Resources of merged
+// repositories are qualified with their service, e.g. "orders: items".
+func tableOfContents(routes []scanner.RouteInfo, anchors []string) []tocGroup {
+	groups := []tocGroup{}
+	index := make(map[string]int)
+	for i, route := range routes {
+		name := operationTag(route.Path)
+		if name == "" {
+			name = "/"
+		}
+		if route.Service != "" {
+			name = route.Service + ": " + name
+		}
+		if _, exists := index[name]; !exists {
+			index[name] = len(groups)
+			groups = append(groups, tocGroup{Name: name})
+		}
+		groups[index[name]].Entries = append(groups[index[name]].Entries, tocEntry{
+			Method: route.Method,
+			Path:   route.Path,
+			Anchor: anchors[i],
+		})
+	}
+	return groups
+}
+
+// serviceHeadings returns, for each route, the name of its service if it is
+// the first route of the service, so the detailed documentation of merged
+// repositories is grouped under a heading per service
+func serviceHeadings(routes []scanner.RouteInfo) []string {
+	headings := make([]string, len(routes))
+	for i, route := range routes {
+		if route.Service != "" && (i == 0 || routes[i-1].Service != route.Service) {
+			headings[i] = route.Service
+		}
+	}
+	return headings
+}
+
+// routeAnchors returns the anchors GitHub generates for the detailed section
+// heading of each route ("METHOD path", followed by "(deprecated)" for
+// deprecated routes), numbering repeated headings
+func routeAnchors(routes []scanner.RouteInfo, deprecated func(scanner.RouteInfo) bool) []string {
+	anchors := make([]string, len(routes))
+	seen := make(map[string]int)
+	for i, route := range routes {
+		heading := route.Method + " " + route.Path
+		if deprecated(route) {
+			heading += " (deprecated)"
+		}
+		anchor := slugify(heading)
+		if count := seen[anchor]; count > 0 {
+			anchors[i] = fmt.Sprintf("%s-%d", anchor, count)
+		} else {
+			anchors[i] = anchor
+		}
+		seen[anchor]++
+	}
+	return anchors
+}
+
+// slugify converts a heading to a GitHub heading anchor: lowercase, with
+// punctuation removed and spaces replaced by hyphens
+func slugify(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// sourceLocation formats a position as file:line, relative to the repository root
+func (g *DocGenerator) sourceLocation(pos token.Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", g.relativePath(pos.Filename), pos.Line)
+}
+
+// relativePath returns a path relative to the repository root, if it is inside it
+func (g *DocGenerator) relativePath(path string) string {
+	if g.RepoRoot != "" {
+		if rel, err := filepath.Rel(g.RepoRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
 }
 
 // generateOpenAPI generates OpenAPI documentation
 func (g *DocGenerator) generateOpenAPI() error {
 	// Create OpenAPI spec
 	spec := g.createOpenAPISpec()
+	if !g.SkipValidation {
+		if err := spec.Validate(); err != nil {
+			return err
+		}
+	}
 
 	// Convert to JSON
-	jsonData, err := json.MarshalIndent(spec, "", "  ")
+	jsonData, err := marshalJSON(spec)
 	if err != nil {
 		return fmt.Errorf("error marshaling OpenAPI spec: %v", err)
 	}
 
 	// Write to file
-	if err := os.WriteFile(g.OutputFile, jsonData, 0644); err != nil {
+	if err := writeOutput(g.OutputFile, jsonData); err != nil {
 		return fmt.Errorf("error writing OpenAPI spec: %v", err)
 	}
 
@@ -169,10 +357,13 @@ func (g *DocGenerator) generateOpenAPI() error {
 // OpenAPISpec represents an OpenAPI specification
 type OpenAPISpec struct {
 	OpenAPI    string              `json:"openapi"`
+	Dialect    string              `json:"jsonSchemaDialect,omitempty"`
 	Info       OpenAPIInfo         `json:"info"`
 	Servers    []OpenAPIServer     `json:"servers"`
 	Paths      map[string]PathItem `json:"paths"`
 	Components OpenAPIComponents   `json:"components"`
+	Middleware []string            `json:"x-middleware,omitempty"`
+	Events     []OpenAPIEvent      `json:"x-events,omitempty"`
 }
 
 // OpenAPIInfo represents the info section of an OpenAPI specification
@@ -193,13 +384,23 @@ type PathItem map[string]Operation
 
 // Operation represents an operation in an OpenAPI specification
 type Operation struct {
-	Summary     string              `json:"summary"`
-	Description string              `json:"description"`
-	OperationID string              `json:"operationId"`
-	Parameters  []Parameter         `json:"parameters,omitempty"`
-	RequestBody *RequestBody        `json:"requestBody,omitempty"`
-	Responses   map[string]Response `json:"responses"`
-	Tags        []string            `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Tags        []string              `json:"tags,omitempty"`
+	Timeout     string                `json:"x-timeout,omitempty"`
+	Source      string                `json:"x-source-location,omitempty"`
+	Middleware  []string              `json:"x-middleware,omitempty"`
+	Protocol    string                `json:"x-protocol,omitempty"` // websocket or sse; omitted for plain HTTP
+	Service     string                `json:"x-service,omitempty"`  // Service of the route, when several repositories are merged
+	Security    []map[string][]string `json:"security,omitempty"`
+	RateLimited bool                  `json:"x-rate-limited,omitempty"`
+	Dynamic     bool                  `json:"x-dynamic,omitempty"`    // The method or path isn't a constant in the source
+	StaticDir   string                `json:"x-static-dir,omitempty"` // Directory or file system served by Static or StaticFS
+	Deprecated  bool                  `json:"deprecated,omitempty"`
 }
 
 // Parameter represents a parameter in an OpenAPI specification
@@ -216,6 +417,7 @@ type RequestBody struct {
 	Description string                     `json:"description"`
 	Content     map[string]MediaTypeObject `json:"content"`
 	Required    bool                       `json:"required"`
+	Validated   bool                       `json:"x-validated"` // Whether the handler calls c.Validate on the body
 }
 
 // Response represents a response in an OpenAPI specification
@@ -231,7 +433,25 @@ type MediaTypeObject struct {
 
 // OpenAPIComponents represents the components section of an OpenAPI specification
 type OpenAPIComponents struct {
-	Schemas map[string]interface{} `json:"schemas"`
+	Schemas         map[string]interface{}    `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// formSchema returns the schema of a form with the given fields, with
+// files as binary strings
+func formSchema(inputs []analyzer.RequestInput) *types.JSONSchema {
+	schema := &types.JSONSchema{
+		Type:       types.JSONSchemaTypeObject,
+		Properties: make(map[string]*types.JSONSchemaProperty),
+	}
+	for _, input := range inputs {
+		property := &types.JSONSchemaProperty{Type: types.JSONSchemaTypeString}
+		if input.Type == "File" {
+			property.Format = types.JSONSchemaFormatBinary
+		}
+		schema.Properties[input.Name] = property
+	}
+	return schema
 }
 
 // createOpenAPISpec creates an OpenAPI specification
@@ -253,12 +473,24 @@ func (g *DocGenerator) createOpenAPISpec() OpenAPISpec {
 		Components: OpenAPIComponents{
 			Schemas: make(map[string]interface{}),
 		},
+		Middleware: g.Middleware,
+	}
+
+	// Error responses share the schema of the error type, if there is one
+	var errorType *types.TypeDefinition
+	if g.SchemaGenerator != nil {
+		errorType = g.errorType()
 	}
 
+	// Errors returned by handlers are written by the HTTP error handler
+	errorResponse := g.errorResponse(spec.Components.Schemas, errorType)
+
 	// Add paths
+	operationIDs := g.operationIDs()
 	for _, route := range g.Routes {
-		path := route.Path
-		method := strings.ToLower(route.Method)
+		// Echo routes sharing a path must end up in the same path item
+		path := toOpenAPIPath(route.Path)
+		method := openAPIMethodKey(route.Method)
 
 		// Create path item if it doesn't exist
 		if _, exists := spec.Paths[path]; !exists {
@@ -267,18 +499,52 @@ func (g *DocGenerator) createOpenAPISpec() OpenAPISpec {
 
 		// Create operation
 		operation := Operation{
-			Summary:     fmt.Sprintf("%s %s", route.Method, route.Path),
+			Summary:     fmt.Sprintf("%s %s", route.Method, path),
 			Description: fmt.Sprintf("Handler: %s", route.HandlerName),
-			OperationID: fmt.Sprintf("%s_%s", method, strings.Replace(path, "/", "_", -1)),
+			OperationID: operationIDs[route.Method+" "+route.Path],
 			Parameters:  []Parameter{},
 			Responses:   make(map[string]Response),
+			Middleware:  route.Middleware,
+			Service:     route.Service,
+			Dynamic:     route.Dynamic,
+			StaticDir:   route.StaticDir,
+		}
+		if tag := operationTag(route.Path); tag != "" {
+			operation.Tags = []string{tag}
+		}
+		if route.Protocol != "" && route.Protocol != scanner.ProtocolHTTP {
+			operation.Protocol = route.Protocol
+		}
+		if route.NotFound {
+			operation.Description += " (catch-all for routes that are not found)"
 		}
+		if route.StaticDir != "" {
+			operation.Description += fmt.Sprintf(" (serves the files under %s)", route.StaticDir)
+		}
+
+		// Point to the handler's code, or to the route registration if the
+		// handler wasn't found
+		operation.Source = g.sourceLocation(route.Position)
 
 		// Get handler info
 		handler := g.getHandlerForRoute(route)
+		operation.Deprecated = g.isDeprecated(route)
 		if handler != nil {
-			// Add parameters
+			operation.Source = g.sourceLocation(handler.Position)
+
+			// Note the request timeout, if the handler applies one
+			for _, timeout := range handler.Timeouts {
+				if timeout.Duration != "" {
+					operation.Timeout = timeout.Duration
+					break
+				}
+			}
+
+			// Add parameters; bodies, form values and files are documented as the request body
 			for _, input := range handler.RequestInputs {
+				if input.Type == "Body" || input.Type == "Form" || input.Type == "File" {
+					continue
+				}
 				param := Parameter{
 					Name:        input.Name,
 					Description: input.Description,
@@ -290,6 +556,9 @@ func (g *DocGenerator) createOpenAPISpec() OpenAPISpec {
 				case "Path":
 					param.In = "path"
 					param.Required = true
+					if param.Name == "*" {
+						param.Name = WildcardParamName
+					}
 				case "Query":
 					param.In = "query"
 				case "Header":
@@ -299,82 +568,173 @@ func (g *DocGenerator) createOpenAPISpec() OpenAPISpec {
 				}
 
 				// Set schema
-				param.Schema = map[string]string{
-					"type": "string", // Default
+				schema := map[string]string{
+					"type": parameterType(input.DataType),
+				}
+				if input.Default != "" {
+					schema["default"] = input.Default
 				}
+				param.Schema = schema
 
 				// Add parameter
 				operation.Parameters = append(operation.Parameters, param)
 			}
 
 			// Add request body if needed
-			for _, input := range handler.RequestInputs {
-				if input.Type == "Body" {
-					// Check if we have a schema for this type
-					var schema interface{} = map[string]string{
-						"type": "object", // Default
+			if input := handler.RequestBody(); input != nil {
+				// Check if we have a schema for this type
+				var schema interface{} = map[string]string{
+					"type": "object", // Default
+				}
+				if requestType, exists := g.RequestTypes[handler.Name]; exists && g.SchemaGenerator != nil {
+					if requestSchema := g.SchemaGenerator.GenerateSchema(requestType); requestSchema != nil {
+						// Add schema to components
+						schemaName := fmt.Sprintf("%s_Request", handler.Name)
+						spec.Components.Schemas[schemaName] = requestSchema
+						schema = map[string]string{
+							"$ref": fmt.Sprintf("#/components/schemas/%s", schemaName),
+						}
 					}
+				}
 
-					// Add request body
-					operation.RequestBody = &RequestBody{
-						Description: "Request body",
-						Content: map[string]MediaTypeObject{
-							"application/json": {
-								Schema: schema,
-							},
+				description := "Request body"
+				if !input.Validated {
+					description = "Request body (not validated)"
+				}
+
+				// Add request body
+				operation.RequestBody = &RequestBody{
+					Description: description,
+					Content: map[string]MediaTypeObject{
+						"application/json": {
+							Schema: schema,
 						},
-						Required: true,
-					}
-					break
+					},
+					Required:  input.Required,
+					Validated: input.Validated,
+				}
+			} else if contentType := handler.FormContentType(); contentType != "" {
+				operation.RequestBody = &RequestBody{
+					Description: "Form fields",
+					Content: map[string]MediaTypeObject{
+						contentType: {
+							Schema: formSchema(handler.FormInputs()),
+						},
+					},
 				}
 			}
 
 			// Add responses
 			for _, output := range handler.ResponseOutputs {
+				// WebSocket and SSE handlers succeed with the upgrade or the
+				// stream, documented below, rather than with a body
+				if operation.Protocol != "" && output.StatusCode < 300 {
+					continue
+				}
+
+				// Without a known status code, a redirect is documented as
+				// any redirection
+				if output.Type == "Redirect" && output.StatusUnknown {
+					operation.Responses[redirectStatusRange] = Response{
+						Description: statusRangeDescription(redirectStatusRange),
+					}
+					continue
+				}
+
+				// Outputs sharing a status code, such as c.JSON and c.XML in
+				// the branches of a handler negotiating the content type, are
+				// documented as one response with a media type each
 				statusCode := fmt.Sprintf("%d", output.StatusCode)
-				response := Response{
-					Description: fmt.Sprintf("%d response", output.StatusCode),
+				response, exists := operation.Responses[statusCode]
+				if !exists {
+					response = Response{
+						Description: responseDescription(output),
+					}
 				}
 
-				// Add content if it's a JSON response
-				if output.Type == "JSON" {
+				// Add content if it's a JSON or XML response
+				if output.Type == "JSON" || output.Type == "XML" {
+					contentType := output.ContentType
+					if contentType == "" {
+						contentType = defaultMediaType(output.Type)
+					}
+
 					// Check if we have a schema for this response
-					responseKey := fmt.Sprintf("%s_%s", route.HandlerName, statusCode)
-					if responseInfo, exists := g.ResponseTypes[responseKey]; exists && responseInfo.Type != nil {
-						// Generate JSON schema
+					responseKey := fmt.Sprintf("%s_%s", handler.Name, statusCode)
+					if responseInfo, exists := g.ResponseTypes[responseKey]; exists && output.Type == "JSON" && isErrorType(responseInfo.Type, errorType) &&
+						(output.StatusCode >= 400 || output.StatusUnknown) {
+						// Error responses reference the shared error schema
+						response.addContent(contentType, MediaTypeObject{
+							Schema: g.componentSchemaRef(errorType, spec.Components.Schemas),
+						})
+
+						// Without a known status code, it's documented as any error
+						if output.StatusUnknown {
+							for _, statusRange := range errorStatusRanges {
+								operation.Responses[statusRange] = Response{
+									Description: statusRangeDescription(statusRange),
+									Content:     response.Content,
+								}
+							}
+							continue
+						}
+					} else if responseInfo, exists := g.ResponseTypes[responseKey]; exists && responseInfo.Type != nil {
+						// Generate the schema, with element names for XML
 						if g.SchemaGenerator != nil {
 							schema := g.SchemaGenerator.GenerateSchema(responseInfo.Type)
+							if output.Type == "XML" {
+								schema = g.SchemaGenerator.GenerateXMLSchema(responseInfo.Type)
+							}
 							if schema != nil {
-								// Add schema to components
-								schemaName := fmt.Sprintf("%s_%s_Response", route.HandlerName, statusCode)
+								// Add schema to components, named after the output
+								// type if another media type has the status's name
+								schemaName := fmt.Sprintf("%s_%s_Response", handler.Name, statusCode)
+								if len(response.Content) > 0 {
+									schemaName += "_" + output.Type
+								}
 								spec.Components.Schemas[schemaName] = schema
 
 								// Reference the schema
-								response.Content = map[string]MediaTypeObject{
-									"application/json": {
-										Schema: map[string]string{
-											"$ref": fmt.Sprintf("#/components/schemas/%s", schemaName),
-										},
+								response.addContent(contentType, MediaTypeObject{
+									Schema: map[string]string{
+										"$ref": fmt.Sprintf("#/components/schemas/%s", schemaName),
 									},
-								}
+								})
 							}
 						}
 					} else {
-						// Default schema
-						response.Content = map[string]MediaTypeObject{
-							"application/json": {
-								Schema: map[string]string{
-									"type": "object",
-								},
-							},
-						}
+						// The response type wasn't analyzed, so it can't be statically determined
+						response.addContent(contentType, MediaTypeObject{
+							Schema: types.UnknownSchema(),
+						})
 					}
+				} else if output.ContentType != "" {
+					// Other bodies are documented by their content type
+					response.addContent(output.ContentType, MediaTypeObject{
+						Schema: bodySchema(output.Type),
+					})
 				}
 
 				// Add response
 				operation.Responses[statusCode] = response
 			}
 
+			switch operation.Protocol {
+			case scanner.ProtocolWebSocket:
+				operation.Responses["101"] = Response{
+					Description: "Switching Protocols to WebSocket",
+				}
+			case scanner.ProtocolSSE:
+				operation.Responses["200"] = Response{
+					Description: "Server-Sent Events stream",
+					Content: map[string]MediaTypeObject{
+						"text/event-stream": {
+							Schema: map[string]string{"type": "string"},
+						},
+					},
+				}
+			}
+
 			// Add default response if no responses are defined
 			if len(operation.Responses) == 0 {
 				operation.Responses["200"] = Response{
@@ -382,14 +742,212 @@ func (g *DocGenerator) createOpenAPISpec() OpenAPISpec {
 				}
 			}
 		}
+		if errorResponse != nil {
+			operation.Responses["default"] = *errorResponse
+		}
+
+		// OpenAPI requires a response, even when the handler wasn't found,
+		// e.g. echo.WrapHandler(h), or its responses weren't detected
+		if len(operation.Responses) == 0 {
+			operation.Responses["default"] = Response{
+				Description: "Response could not be determined statically",
+			}
+		}
+
+		// Authentication and rate limiting enforced by middleware
+		g.applySecurity(&operation, append(append([]string{}, g.Middleware...), route.Middleware...), &spec.Components)
+
+		// Every parameter of the path template must be declared, including
+		// those of a base path and those the handler never reads
+		operation.Parameters = declarePathParams(operation.Parameters, route.Path)
 
 		// Add operation to path
 		spec.Paths[path][method] = operation
 	}
 
+	spec.Events = g.openAPIEvents(spec.Components.Schemas)
+
+	// Add the schemas referenced from response schemas
+	if g.SchemaGenerator != nil {
+		for name, schema := range g.SchemaGenerator.Components {
+			spec.Components.Schemas[name] = schema
+		}
+	}
+
+	if g.SchemaDraft != "" {
+		g.applySchemaDraft(&spec)
+	}
+
 	return spec
 }
 
+// errorResponse returns the OpenAPI default response derived from the JSON
+// response of the HTTP error handler, adding its schema to the components, or
+// nil if there is no error handler writing JSON
+func (g *DocGenerator) errorResponse(schemas map[string]interface{}, errorType *types.TypeDefinition) *Response {
+	if g.ErrorHandler == nil {
+		return nil
+	}
+	output := g.ErrorHandler.ErrorResponse()
+	if output == nil {
+		return nil
+	}
+
+	var schema interface{} = types.UnknownSchema()
+	responseKey := fmt.Sprintf("%s_%d", g.ErrorHandler.Name, output.StatusCode)
+	if responseInfo, exists := g.ResponseTypes[responseKey]; exists && isErrorType(responseInfo.Type, errorType) {
+		schema = g.componentSchemaRef(errorType, schemas)
+	} else if exists && responseInfo.Type != nil && g.SchemaGenerator != nil {
+		if generated := g.SchemaGenerator.GenerateSchema(responseInfo.Type); generated != nil {
+			schemaName := fmt.Sprintf("%s_Error", g.ErrorHandler.Name)
+			schemas[schemaName] = generated
+			schema = map[string]string{
+				"$ref": fmt.Sprintf("#/components/schemas/%s", schemaName),
+			}
+		}
+	}
+
+	return &Response{
+		Description: fmt.Sprintf("Error response written by %s", g.ErrorHandler.Name),
+		Content: map[string]MediaTypeObject{
+			output.ContentType: {Schema: schema},
+		},
+	}
+}
+
+// operationIDs assigns a unique operation id to every route, keyed by
+// "METHOD path". Routes named in the code, as in
+// e.GET("/users/:id", getUser).Name = "get-user", keep their name. Other ids
+// are camelCase, after the handler when it's a plain function used by a
+// single route, and after the method and path otherwise. Ids shared by
+// several routes get a numeric suffix, assigned in method and path order so
+// they're stable across runs.
+func (g *DocGenerator) operationIDs() map[string]string {
+	routes := []scanner.RouteInfo{}
+	handlerCount := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, route := range g.Routes {
+		key := route.Method + " " + route.Path
+		if !seen[key] {
+			seen[key] = true
+			routes = append(routes, route)
+			handlerCount[route.HandlerName]++
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	ids := make(map[string]string)
+	used := make(map[string]bool)
+
+	// Route names given in the code are used as is, so generated ids avoid them
+	for _, route := range routes {
+		if route.Name == "" {
+			continue
+		}
+		unique := route.Name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", route.Name, i)
+		}
+		used[unique] = true
+		ids[route.Method+" "+route.Path] = unique
+	}
+
+	for _, route := range routes {
+		if route.Name != "" {
+			continue
+		}
+		id := route.HandlerName
+		if handlerCount[id] > 1 || !token.IsIdentifier(id) || strings.HasPrefix(id, "anonymous") {
+			id = operationName(route)
+			if id == strings.ToLower(route.Method) {
+				id += "Root"
+			}
+		}
+
+		unique := id
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", id, i)
+		}
+		used[unique] = true
+		ids[route.Method+" "+route.Path] = unique
+	}
+	return ids
+}
+
+// parameterType returns the JSON Schema type of a parameter's data type,
+// "string" unless the handler converts it to a number or boolean or binds
+// it to a field of one
+func parameterType(dataType string) string {
+	switch dataType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "string"
+}
+
+// operationTag returns the tag grouping the operations of a path: its first
+// segment, unless that is a parameter
+func operationTag(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.ContainsAny(segment, ":*") {
+			return ""
+		}
+		return segment
+	}
+	return ""
+}
+
+// WildcardParamName is the parameter name documenting Echo's unnamed *
+// wildcard, since OpenAPI path parameters must be named
+var WildcardParamName = "filepath"
+
+// toOpenAPIPath converts an Echo route path to OpenAPI path template syntax,
+// turning :param into {param} and the * wildcard into a named parameter
+func toOpenAPIPath(path string) string {
+	return pathTemplate(path, func(name string) string {
+		return "{" + name + "}"
+	})
+}
+
+// addContent adds the schema of a media type to a response
+func (r *Response) addContent(contentType string, media MediaTypeObject) {
+	if r.Content == nil {
+		r.Content = make(map[string]MediaTypeObject)
+	}
+	r.Content[contentType] = media
+}
+
+// defaultMediaType returns the media type of a JSON or XML response
+func defaultMediaType(outputType string) string {
+	if outputType == "XML" {
+		return "application/xml"
+	}
+	return "application/json"
+}
+
+// bodySchema returns the schema of a response body that isn't JSON
+func bodySchema(outputType string) interface{} {
+	switch outputType {
+	case "String", "HTML":
+		return map[string]string{"type": "string"}
+	case "Blob", "Stream", "File":
+		return map[string]string{"type": "string", "format": "binary"}
+	}
+	return types.UnknownSchema()
+}
+
 // getHandlerForRoute finds the handler info for a route
 func (g *DocGenerator) getHandlerForRoute(route scanner.RouteInfo) *analyzer.HandlerInfo {
 	// First try direct match by name
@@ -403,68 +961,175 @@ func (g *DocGenerator) getHandlerForRoute(route scanner.RouteInfo) *analyzer.Han
 		return handler
 	}
 
+	// Anonymous handlers of rewritten paths are found by their function literal
+	if route.HandlerNode != nil {
+		for _, handler := range g.Handlers {
+			if handler.Route.HandlerNode == route.HandlerNode && handler.Route.Method == route.Method {
+				return handler
+			}
+		}
+	}
+
 	return nil
 }
 
+// isDeprecated reports whether a route is deprecated, either by a
+// @deprecated comment on its registration or by its handler's doc comment
+func (g *DocGenerator) isDeprecated(route scanner.RouteInfo) bool {
+	if route.Deprecated {
+		return true
+	}
+	handler := g.getHandlerForRoute(route)
+	return handler != nil && handler.Deprecated
+}
+
+// outputDescription describes a response output by the template it's
+// rendered from, if any, and its description
+func outputDescription(output analyzer.ResponseOutput) string {
+	if output.Template == "" {
+		return output.Description
+	}
+	description := fmt.Sprintf("Rendered from template %s", output.Template)
+	if output.DataType != "unknown" {
+		description += fmt.Sprintf(" with a %s model", output.DataType)
+	}
+	return description
+}
+
+// responseDescription describes the OpenAPI response of an output
+func responseDescription(output analyzer.ResponseOutput) string {
+	if description := outputDescription(output); description != "" {
+		return description
+	}
+	return fmt.Sprintf("%d response", output.StatusCode)
+}
+
 // Markdown template for documentation
 const markdownTemplate = `# API Documentation
 
 *Generated at: {{.GeneratedAt}}*
 
+## Table of Contents
+
+- [Endpoints](#endpoints)
+{{range .Contents}}  - {{.Name}}
+{{range .Entries}}    - [{{.Method}} {{.Path}}](#{{.Anchor}})
+{{end}}{{end}}{{if .ErrorHandler}}- [Error Handler](#error-handler)
+{{end}}- [AWS Events](#aws-events)
+
 ## Endpoints
 
-| Method | Path | Handler | Description |
-|--------|------|---------|-------------|
-{{range .Routes}}| {{.Method}} | {{.Path}} | {{.HandlerName}} | |
+| Method | Path | Handler | Source | Description |
+|--------|------|---------|--------|-------------|
+{{range $i, $route := .Routes}}| {{.Method}} | [{{.Path}}](#{{anchor $i}}) | {{.HandlerName}} | {{source .Position}} | |
+{{end}}
+{{if .Middleware}}
+**Global middleware:** {{range $i, $m := .Middleware}}{{if $i}}, {{end}}{{$m}}{{end}}
 {{end}}
 
 ## Detailed Endpoint Documentation
 
-{{range .Routes}}
-### {{.Method}} {{.Path}}
+{{range $i, $route := .Routes}}{{with index $.ServiceHeadings $i}}
+## {{.}} service
+{{end}}
+### {{.Method}} {{.Path}}{{if deprecated .}} (deprecated){{end}}
 
 **Handler:** {{.HandlerName}}
+{{with .Name}}
+**Route Name:** ` + "`{{.}}`" + `
+{{end}}{{if .NotFound}}
+**Catch-all:** handles requests to paths no other route matches (RouteNotFound)
+{{end}}{{with .StaticDir}}
+**Static files:** serves the files under ` + "`{{.}}`" + `
+{{end}}{{if eq .Protocol "websocket"}}
+**Protocol:** WebSocket (the handler upgrades the connection)
+{{else if eq .Protocol "sse"}}
+**Protocol:** Server-Sent Events (` + "`text/event-stream`" + `)
+{{end}}
+{{$handler := handler .}}
+**Source:** {{source .Position}}{{if $handler}} (handler at {{source $handler.Position}}){{end}}
+{{if .Middleware}}
+**Middleware:** {{range $i, $m := .Middleware}}{{if $i}}, {{end}}{{$m}}{{end}}
+{{end}}
 
-{{$handler := index $.Handlers .HandlerName}}
 {{if $handler}}
+{{range $handler.Timeouts}}
+**Timeout:** {{if .Duration}}{{.Duration}}{{else}}dynamic{{end}} (context.{{.Function}})
+{{end}}
 #### Request Parameters
 
 {{if $handler.RequestInputs}}
 | Type | Name | Data Type | Required | Description |
 |------|------|-----------|----------|-------------|
-{{range $handler.RequestInputs}}| {{.Type}} | {{.Name}} | {{.DataType}} | {{.Required}} | {{.Description}} |
+{{range $handler.RequestInputs}}| {{.Type}} | {{.Name}} | {{.DataType}} | {{.Required}} | {{.Description}}{{with .Default}} (default: ` + "`{{.}}`" + `){{end}} |
 {{end}}
 {{else}}
 *No request parameters*
 {{end}}
 
+{{with $handler.FormContentType}}
+**Request Content Type:** ` + "`{{.}}`" + `
+{{end}}
+{{with $handler.RequestBody}}
+**Validation:** {{if .Validated}}the request body is validated with c.Validate{{else}}the request body is **not validated**{{end}}
+{{$requestType := index $.RequestTypes $handler.Name}}
+{{if $requestType}}{{if $.SchemaGenerator}}
+**Request Body Schema:**
+
+` + "```json" + `
+{{$.SchemaGenerator.GenerateSchemaDocumentString $requestType}}
+` + "```" + `
+{{end}}{{end}}
+{{end}}
 #### Response
 
 {{if $handler.ResponseOutputs}}
-| Type | Status Code | Data Type | Description |
-|------|------------|-----------|-------------|
-{{range $handler.ResponseOutputs}}| {{.Type}} | {{.StatusCode}} | {{.DataType}} | {{.Description}} |
+| Type | Status Code | Content Type | Data Type | Description |
+|------|------------|--------------|-----------|-------------|
+{{range $handler.ResponseOutputs}}| {{.Type}} | {{.StatusCode}}{{if .Primary}} (primary){{end}} | {{.ContentType}} | {{.DataType}} | {{describe .}} |
 {{end}}
 
-{{$responseKey := printf "%s_%d" $handler.Name 200}}
+{{range $handler.ResponseOutputs}}
+{{if eq .Type "JSON"}}
+{{$responseKey := printf "%s_%d" $handler.Name .StatusCode}}
 {{$responseInfo := index $.ResponseTypes $responseKey}}
-{{if $responseInfo}}
-{{if $responseInfo.Type}}
+{{$responseType := $.UnknownType}}
+{{if $responseInfo}}{{if $responseInfo.Type}}{{$responseType = $responseInfo.Type}}{{end}}{{end}}
 {{if $.SchemaGenerator}}
+##### {{.StatusCode}} Response
+
 **JSON Schema:**
 
 ` + "```json" + `
-{{$schema := $.SchemaGenerator.GenerateSchemaString $responseInfo.Type}}
+{{$schema := $.SchemaGenerator.GenerateSchemaDocumentString $responseType}}
 {{$schema}}
 ` + "```" + `
 
 **Example Response:**
 
 ` + "```json" + `
-{{$example := $.SchemaGenerator.GenerateExampleJSON $responseInfo.Type}}
+{{$example := $.SchemaGenerator.GenerateExampleJSON $responseType}}
 {{$example}}
 ` + "```" + `
 {{end}}
+{{else if eq .Type "XML"}}
+{{$responseKey := printf "%s_%d" $handler.Name .StatusCode}}
+{{$responseInfo := index $.ResponseTypes $responseKey}}
+{{if $responseInfo}}{{if $responseInfo.Type}}{{if $.SchemaGenerator}}
+##### {{.StatusCode}} Response
+
+**XML Schema:**
+
+` + "```json" + `
+{{$.SchemaGenerator.GenerateXMLSchemaString $responseInfo.Type}}
+` + "```" + `
+
+**Example Response:**
+
+` + "```xml" + `
+{{$.SchemaGenerator.GenerateExampleXML $responseInfo.Type}}
+` + "```" + `
+{{end}}{{end}}{{end}}
 {{end}}
 {{end}}
 
@@ -476,20 +1141,58 @@ const markdownTemplate = `# API Documentation
 {{end}}
 
 {{end}}
+{{with .ErrorHandler}}
+## Error Handler
 
+Errors returned by handlers are written by **{{.Name}}** ({{source .Position}}), the Echo instance's HTTPErrorHandler.
+{{with .ErrorResponse}}
+{{$responseKey := printf "%s_%d" $.ErrorHandler.Name .StatusCode}}
+{{$responseInfo := index $.ResponseTypes $responseKey}}
+{{$responseType := $.UnknownType}}
+{{if $responseInfo}}{{if $responseInfo.Type}}{{$responseType = $responseInfo.Type}}{{end}}{{end}}
+{{if $.SchemaGenerator}}
+**Error Response Schema:**
+
+` + "```json" + `
+{{$.SchemaGenerator.GenerateSchemaDocumentString $responseType}}
+` + "```" + `
+
+**Example Error Response:**
+
+` + "```json" + `
+{{$.SchemaGenerator.GenerateExampleJSON $responseType}}
+` + "```" + `
+{{end}}
+{{else}}
+*The error handler doesn't write a JSON response*
+{{end}}
+{{end}}
 ## AWS Events
 
 {{if .Events}}
-| Service | Operation | Topic/Queue | Message Format |
-|---------|-----------|-------------|----------------|
-{{range .Events}}| {{.Service}} | {{.Operation}} | {{.TopicOrQueue}} | {{if .MessageFormat.IsStructured}}Structured{{else}}Raw{{end}} |
+| Service | Operation | Direction | Target | Message Format | Source |
+|---------|-----------|-----------|--------|----------------|--------|
+{{range .Events}}| {{.Service}} | {{.Operation}}{{if .BatchSize}} (batch of {{.BatchSize}}){{end}} | {{.Direction}} | {{if .Handler}}{{.Handler}} (handler){{else}}{{.Target}}{{end}} | {{if .MessageFormat.IsStructured}}Structured{{else}}Raw{{end}} | {{source .Position}} |
 {{end}}
 
 ### Detailed Event Documentation
 
 {{range .Events}}
-#### {{.Service}} {{.Operation}} to {{.TopicOrQueue}}
+{{if .Handler -}}
+#### {{.Service}} {{.Operation}} consumed by {{.Handler}}
+{{- else -}}
+#### {{.Service}} {{.Operation}} {{if eq .Direction "Consume"}}from{{else}}to{{end}} {{.Target}}
+{{- end}}
+
+{{- if .MessageType}}{{if $.SchemaGenerator}}
+**Message Type:** ` + "`{{.MessageType.Name}}`" + `
+
+**Message Schema:**
 
+` + "```json" + `
+{{$.SchemaGenerator.GenerateSchemaDocumentString .MessageType}}
+` + "```" + `
+{{end}}{{end}}
 {{if .MessageFormat.IsStructured}}
 **Message Fields:**
 
@@ -503,7 +1206,7 @@ const markdownTemplate = `# API Documentation
 ` + "```" + `
 {{.MessageFormat.RawMessage}}
 ` + "```" + `
-{{else}}
+{{else if not .MessageType}}
 *No message format information available*
 {{end}}
 
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// errorType returns the type shared by the error responses of the API: the
+// type named by ErrorType, or else the struct type of the most 4xx and 5xx
+// responses, if at least two use it. It returns nil if there is none.
+func (g *DocGenerator) errorType() *types.TypeDefinition {
+	counts := make(map[string]int)
+	typeDefs := make(map[string]*types.TypeDefinition)
+	for _, responseInfo := range g.ResponseTypes {
+		if responseInfo.Type == nil {
+			continue
+		}
+		typeDef := derefType(responseInfo.Type)
+		if typeDef.Kind != types.KindStruct || typeDef.Name == "anonymous" {
+			continue
+		}
+
+		// A configured type is used by any response, whatever its status
+		if g.ErrorType != "" {
+			if typeDef.Name == g.ErrorType || path.Base(typeDef.Package)+"."+typeDef.Name == g.ErrorType {
+				return typeDef
+			}
+			continue
+		}
+		if responseInfo.StatusCode < 400 {
+			continue
+		}
+
+		name := typeDef.Service + ":" + typeDef.Package + "." + typeDef.Name
+		counts[name]++
+		typeDefs[name] = typeDef
+	}
+
+	// Pick the most used type, breaking ties by name so the choice is stable
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 || counts[names[0]] < 2 {
+		return nil
+	}
+	return typeDefs[names[0]]
+}
+
+// componentSchemaRef registers the schema of a named type, such as the
+// shared error type, under its own name in the components, once, and returns
+// a reference to it
+func (g *DocGenerator) componentSchemaRef(typeDef *types.TypeDefinition, schemas map[string]interface{}) interface{} {
+	name := types.ComponentName(typeDef)
+	if _, exists := schemas[name]; !exists {
+		schemas[name] = g.SchemaGenerator.GenerateSchema(typeDef)
+	}
+	return map[string]string{
+		"$ref": fmt.Sprintf("#/components/schemas/%s", name),
+	}
+}
+
+// isErrorType reports whether a response type is the shared error type,
+// possibly through a pointer
+func isErrorType(typeDef, errorType *types.TypeDefinition) bool {
+	if typeDef == nil || errorType == nil {
+		return false
+	}
+	typeDef = derefType(typeDef)
+	return typeDef.Name == errorType.Name && typeDef.Package == errorType.Package && typeDef.Service == errorType.Service
+}
+
+// derefType returns the type a pointer type points to, through any number of pointers
+func derefType(typeDef *types.TypeDefinition) *types.TypeDefinition {
+	for typeDef.Kind == types.KindPointer && typeDef.ElementType != nil {
+		typeDef = typeDef.ElementType
+	}
+	return typeDef
+}
+
+// errorStatusRanges are the OpenAPI status code ranges documenting error
+// responses whose status code can't be statically determined
+var errorStatusRanges = []string{"4XX", "5XX"}
+
+// redirectStatusRange is the OpenAPI status code range documenting
+// redirects whose status code can't be statically determined
+const redirectStatusRange = "3XX"
+
+// statusRangeDescription describes a status code range response
+func statusRangeDescription(statusRange string) string {
+	switch statusRange {
+	case redirectStatusRange:
+		return "Redirection response"
+	case "4XX":
+		return "Client error response"
+	}
+	return "Server error response"
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// OpenAPIEvent describes an AWS event produced or consumed by the code, in
+// the x-events extension of an OpenAPI specification
+type OpenAPIEvent struct {
+	Service   string      `json:"service"`
+	Operation string      `json:"operation"`
+	Direction string      `json:"direction"`
+	Target    string      `json:"target,omitempty"`
+	Handler   string      `json:"handler,omitempty"`
+	Function  string      `json:"function,omitempty"`
+	BatchSize int         `json:"batchSize,omitempty"`
+	Message   interface{} `json:"message,omitempty"` // Schema of the message body, if its type is known
+	Source    string      `json:"source,omitempty"`
+}
+
+// openAPIEvents describes the events for an OpenAPI specification. Message
+// bodies of named types reference a schema in the components, so events
+// sharing a message type share its schema.
+func (g *DocGenerator) openAPIEvents(schemas map[string]interface{}) []OpenAPIEvent {
+	var events []OpenAPIEvent
+	for _, event := range g.Events {
+		openAPIEvent := OpenAPIEvent{
+			Service:   event.Service,
+			Operation: event.Operation,
+			Direction: string(event.Direction),
+			Target:    event.Target,
+			Handler:   event.Handler,
+			Function:  event.Function,
+			BatchSize: event.BatchSize,
+			Source:    g.sourceLocation(event.Position),
+		}
+		if event.MessageType != nil && g.SchemaGenerator != nil {
+			openAPIEvent.Message = g.messageSchema(event.MessageType, schemas)
+		}
+		events = append(events, openAPIEvent)
+	}
+	return events
+}
+
+// messageSchema returns the schema of a message body: a reference to the
+// component schema of a named struct, through any pointers, or an inline
+// schema otherwise
+func (g *DocGenerator) messageSchema(messageType *types.TypeDefinition, schemas map[string]interface{}) interface{} {
+	named := derefType(messageType)
+	if named.Kind == types.KindStruct && named.Name != "" && named.Name != "anonymous" {
+		return g.componentSchemaRef(named, schemas)
+	}
+	return g.SchemaGenerator.GenerateSchema(messageType)
+}
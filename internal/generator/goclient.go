@@ -0,0 +1,509 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// DefaultClientPackage is the package name used for generated Go clients
+const DefaultClientPackage = "client"
+
+// goStruct represents a Go struct declared in the generated client
+type goStruct struct {
+	Name   string
+	Fields []goField
+}
+
+// goField represents a field of a generated Go struct
+type goField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// goAlias represents a type alias for a handler's request body or response
+type goAlias struct {
+	Name string
+	Type string
+}
+
+// goEndpoint represents a client method for a route
+type goEndpoint struct {
+	clientEndpoint
+	MethodName   string
+	RequestName  string
+	PathFormat   string // fmt format with a %s verb per path parameter
+	PathArgs     []goParam
+	QueryArgs    []goParam
+	HeaderArgs   []goParam
+	BodyType     string
+	ResponseType string
+}
+
+// goParam maps a request parameter to a field of the request struct
+type goParam struct {
+	Name  string // Parameter name in the request
+	Field string // Field name in the request struct
+}
+
+// goClientBuilder converts resolved type definitions to Go declarations
+type goClientBuilder struct {
+	structs   []*goStruct
+	names     map[string]string              // type key to struct name
+	used      map[string]bool                // struct names already taken
+	wellKnown map[string]types.WellKnownType // JSON representation of types from outside the analyzed code
+	needTime  bool
+}
+
+// goStdlibTypes are the well-known types the client declares with their own
+// type, from a package it imports
+var goStdlibTypes = map[string]bool{
+	"time.Time":       true,
+	"time.Duration":   true,
+	"json.RawMessage": true,
+	"json.Number":     true,
+}
+
+// generateGoClient generates a Go client package with a method per endpoint
+func (g *DocGenerator) generateGoClient() error {
+	builder := &goClientBuilder{
+		names:     make(map[string]string),
+		used:      map[string]bool{"Client": true, "Error": true},
+		wellKnown: types.DefaultWellKnownTypes,
+	}
+	if g.SchemaGenerator != nil {
+		builder.wellKnown = g.SchemaGenerator.WellKnownTypes
+	}
+
+	// Declare a type alias for every resolved request body and analyzed
+	// response, in a stable order
+	requestKeys := make([]string, 0, len(g.RequestTypes))
+	for key, requestType := range g.RequestTypes {
+		if requestType != nil {
+			requestKeys = append(requestKeys, key)
+		}
+	}
+	sort.Strings(requestKeys)
+
+	aliases := []goAlias{}
+	for _, key := range requestKeys {
+		aliases = append(aliases, goAlias{
+			Name: requestTypeName(key),
+			Type: builder.typeOf(derefType(g.RequestTypes[key])),
+		})
+	}
+
+	keys := make([]string, 0, len(g.ResponseTypes))
+	for key, responseInfo := range g.ResponseTypes {
+		if responseInfo.Type != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		aliases = append(aliases, goAlias{
+			Name: responseTypeName(key),
+			Type: builder.typeOf(g.ResponseTypes[key].Type),
+		})
+	}
+
+	// Build the client methods
+	endpoints := []goEndpoint{}
+	for _, endpoint := range g.clientEndpoints() {
+		endpoints = append(endpoints, newGoEndpoint(endpoint))
+	}
+
+	packageName := g.ClientPackage
+	if packageName == "" {
+		packageName = DefaultClientPackage
+	}
+	if !token.IsIdentifier(packageName) {
+		return fmt.Errorf("invalid client package name: %s", packageName)
+	}
+
+	// Create the template
+	tmpl, err := template.New("goclient").Parse(goClientTemplate)
+	if err != nil {
+		return fmt.Errorf("error creating template: %v", err)
+	}
+
+	data := struct {
+		Package     string
+		NeedTime    bool
+		Structs     []*goStruct
+		Aliases     []goAlias
+		Endpoints   []goEndpoint
+		GeneratedAt string
+	}{
+		Package:     packageName,
+		NeedTime:    builder.needTime,
+		Structs:     builder.structs,
+		Aliases:     aliases,
+		Endpoints:   endpoints,
+		GeneratedAt: time.Now().Format("January 2, 2006 15:04:05"),
+	}
+
+	// Execute the template and format the result
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("error executing template: %v", err)
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting Go client: %v", err)
+	}
+
+	// Write to file
+	if err := writeOutput(g.OutputFile, source); err != nil {
+		return fmt.Errorf("error writing Go client: %v", err)
+	}
+
+	return nil
+}
+
+// newGoEndpoint builds the Go client method for an endpoint
+func newGoEndpoint(endpoint clientEndpoint) goEndpoint {
+	// Escape literal percent signs before substituting the path parameters
+	escaped := endpoint
+	escaped.Path = strings.Replace(endpoint.Path, "%", "%%", -1)
+
+	methodName := exportedName(endpoint.FuncName)
+	goEndpoint := goEndpoint{
+		clientEndpoint: endpoint,
+		MethodName:     methodName,
+		RequestName:    methodName + "Request",
+		PathFormat:     escaped.urlTemplate(func(string) string { return "%s" }),
+		BodyType:       endpoint.requestType(requestTypeName, "interface{}"),
+		ResponseType:   endpoint.responseType(responseTypeName, "", "json.RawMessage"),
+	}
+
+	// Request struct fields must be unique, so inputs sharing a name are
+	// qualified with their location
+	used := map[string]bool{"Body": true}
+	field := func(name, location string) string {
+		fieldName := exportedName(name)
+		if fieldName == "" || used[fieldName] {
+			fieldName += location
+		}
+		used[fieldName] = true
+		return fieldName
+	}
+
+	for _, name := range endpoint.PathParams {
+		goEndpoint.PathArgs = append(goEndpoint.PathArgs, goParam{Name: name, Field: field(name, "Path")})
+	}
+	for _, name := range endpoint.QueryParams {
+		goEndpoint.QueryArgs = append(goEndpoint.QueryArgs, goParam{Name: name, Field: field(name, "Query")})
+	}
+	for _, name := range endpoint.HeaderParams {
+		goEndpoint.HeaderArgs = append(goEndpoint.HeaderArgs, goParam{Name: name, Field: field(name, "Header")})
+	}
+
+	return goEndpoint
+}
+
+// typeOf returns the Go type expression for a type definition, declaring
+// structs for the named structs it references
+func (b *goClientBuilder) typeOf(typeDef *types.TypeDefinition) string {
+	if typeDef == nil {
+		return "interface{}"
+	}
+
+	switch typeDef.Kind {
+	case types.KindStruct:
+		if typeDef.Name == "" || typeDef.Name == "anonymous" {
+			return b.inlineStruct(typeDef)
+		}
+		return b.declareStruct(typeDef)
+	case types.KindArray:
+		return "[]" + b.typeOf(typeDef.ElementType)
+	case types.KindMap:
+		return "map[string]" + b.typeOf(typeDef.ValueType)
+	case types.KindPointer:
+		return "*" + b.typeOf(typeDef.ElementType)
+	case types.KindBasic:
+		return b.basicType(typeDef.BasicType)
+	}
+
+	return "interface{}"
+}
+
+// declareStruct declares a struct for a named struct type and returns its name
+func (b *goClientBuilder) declareStruct(typeDef *types.TypeDefinition) string {
+	key := typeDef.Service + ":" + typeDef.Package + "." + typeDef.Name
+	if name, exists := b.names[key]; exists {
+		return name
+	}
+
+	// Generic instantiations and clashing names get a derived name
+	name := exportedName(typeDef.Name)
+	if b.used[name] && typeDef.Service != "" {
+		name = exportedName(typeDef.Service) + name
+	}
+	if b.used[name] {
+		pkg := typeDef.Package[strings.LastIndex(typeDef.Package, "/")+1:]
+		name = exportedName(pkg) + name
+	}
+	b.names[key] = name
+	b.used[name] = true
+
+	// Register the struct before resolving fields so recursive types terminate
+	goStruct := &goStruct{Name: name}
+	b.structs = append(b.structs, goStruct)
+	goStruct.Fields = b.fields(typeDef)
+
+	return name
+}
+
+// inlineStruct returns an inline struct type for an anonymous struct
+func (b *goClientBuilder) inlineStruct(typeDef *types.TypeDefinition) string {
+	parts := []string{}
+	for _, field := range b.fields(typeDef) {
+		parts = append(parts, fmt.Sprintf("%s %s %s", field.Name, field.Type, field.Tag))
+	}
+	return "struct {" + strings.Join(parts, "; ") + "}"
+}
+
+// fields converts the fields of a struct type to Go struct fields
+func (b *goClientBuilder) fields(typeDef *types.TypeDefinition) []goField {
+	fields := []goField{}
+	for _, field := range typeDef.Fields {
+		// Unexported and ignored fields are never serialized
+		if field.Type == nil || field.JSONName == "-" || !token.IsExported(field.Name) {
+			continue
+		}
+
+		// Pointer fields stay pointers even if their type didn't resolve to one
+		fieldType := b.typeOf(field.Type)
+		if field.IsPointer && !strings.HasPrefix(fieldType, "*") {
+			fieldType = "*" + fieldType
+		}
+
+		tag := ""
+		if field.JSONName != "" || field.Omitempty {
+			jsonTag := field.JSONName
+			if field.Omitempty {
+				jsonTag += ",omitempty"
+			}
+			tag = "`json:" + strconv.Quote(jsonTag) + "`"
+		}
+
+		fields = append(fields, goField{
+			Name: field.Name,
+			Type: fieldType,
+			Tag:  tag,
+		})
+	}
+	return fields
+}
+
+// basicType maps a basic type definition to a Go type usable in the client
+func (b *goClientBuilder) basicType(basicType string) string {
+	switch basicType {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "byte", "rune":
+		return basicType
+	}
+
+	wellKnown, exists := b.wellKnown[basicType]
+	switch {
+	case exists && goStdlibTypes[basicType]:
+		if strings.HasPrefix(basicType, "time.") {
+			b.needTime = true
+		}
+		return basicType
+	case exists && wellKnown.Schema != nil:
+		return goSchemaType(wellKnown.Schema)
+	}
+	return "interface{}"
+}
+
+// goSchemaType returns the Go type decoding the JSON values of a schema, for
+// well-known types the client can't import, e.g. string for uuid.UUID
+func goSchemaType(schema *types.JSONSchema) string {
+	switch schema.Type {
+	case types.JSONSchemaTypeString:
+		return "string"
+	case types.JSONSchemaTypeInteger:
+		return "int64"
+	case types.JSONSchemaTypeNumber:
+		return "float64"
+	case types.JSONSchemaTypeBoolean:
+		return "bool"
+	case types.JSONSchemaTypeObject:
+		return "map[string]interface{}"
+	case types.JSONSchemaTypeArray:
+		return "[]interface{}"
+	}
+
+	// Numbers that may be encoded as strings, e.g. decimal.Decimal
+	numeric := len(schema.OneOf) > 0
+	for _, option := range schema.OneOf {
+		if option.Type != types.JSONSchemaTypeString && option.Type != types.JSONSchemaTypeNumber && option.Type != types.JSONSchemaTypeInteger {
+			numeric = false
+		}
+	}
+	if numeric {
+		return "json.Number"
+	}
+	return "json.RawMessage"
+}
+
+// Go client template. The output is passed through go/format.
+const goClientTemplate = `// Code generated by Echo Framework Static Analyzer at {{.GeneratedAt}}. DO NOT EDIT.
+
+// Package {{.Package}} is a client for the analyzed API.
+package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+{{- if .NeedTime}}
+	"time"
+{{- end}}
+)
+{{range .Structs}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.Tag}}
+{{- end}}
+}
+{{end}}
+{{- range .Aliases}}
+type {{.Name}} = {{.Type}}
+{{end}}
+// Client calls the API endpoints
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client for the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Error is returned when the API responds with a non-2xx status
+type Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// do sends a request and decodes the JSON response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out interface{}) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return &Error{StatusCode: resp.StatusCode, Body: data}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %v", err)
+	}
+	return nil
+}
+{{range .Endpoints}}
+// {{.RequestName}} holds the parameters of {{.Method}} {{.Path}}
+type {{.RequestName}} struct {
+{{- range .PathArgs}}
+	{{.Field}} string
+{{- end}}
+{{- range .QueryArgs}}
+	{{.Field}} string
+{{- end}}
+{{- range .HeaderArgs}}
+	{{.Field}} string
+{{- end}}
+{{- if .HasBody}}
+	Body {{.BodyType}}
+{{- end}}
+}
+
+// {{.MethodName}} calls {{.Method}} {{.Path}}
+func (c *Client) {{.MethodName}}(ctx context.Context, req {{.RequestName}}) {{if .ResponseType}}(*{{.ResponseType}}, error){{else}}error{{end}} {
+{{- if .PathArgs}}
+	path := fmt.Sprintf({{printf "%q" .PathFormat}}{{range .PathArgs}}, url.PathEscape(req.{{.Field}}){{end}})
+{{- else}}
+	path := {{printf "%q" .Path}}
+{{- end}}
+
+	query := url.Values{}
+{{- range .QueryArgs}}
+	if req.{{.Field}} != "" {
+		query.Set({{printf "%q" .Name}}, req.{{.Field}})
+	}
+{{- end}}
+
+	header := http.Header{}
+{{- range .HeaderArgs}}
+	if req.{{.Field}} != "" {
+		header.Set({{printf "%q" .Name}}, req.{{.Field}})
+	}
+{{- end}}
+{{if .ResponseType}}
+	var out {{.ResponseType}}
+	if err := c.do(ctx, {{printf "%q" .Method}}, path, query, header, {{if .HasBody}}req.Body{{else}}nil{{end}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+{{- else}}
+	return c.do(ctx, {{printf "%q" .Method}}, path, query, header, {{if .HasBody}}req.Body{{else}}nil{{end}}, nil)
+{{- end}}
+}
+{{end}}`
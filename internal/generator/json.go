@@ -0,0 +1,232 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// jsonDocument is the root of the JSON output format
+type jsonDocument struct {
+	GeneratedAt  string            `json:"generatedAt"`
+	Middleware   []string          `json:"middleware,omitempty"`
+	Endpoints    []jsonEndpoint    `json:"endpoints"`
+	ErrorHandler *jsonErrorHandler `json:"errorHandler,omitempty"`
+	Events       []jsonEvent       `json:"events,omitempty"`
+}
+
+// jsonEndpoint describes a route and what its handler reads and writes
+type jsonEndpoint struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Handler     string          `json:"handler"`
+	Service     string          `json:"service,omitempty"`
+	Repository  string          `json:"repository,omitempty"`
+	Protocol    string          `json:"protocol,omitempty"`
+	NotFound    bool            `json:"notFound,omitempty"`
+	StaticDir   string          `json:"staticDir,omitempty"`
+	Deprecated  bool            `json:"deprecated,omitempty"`
+	Source      string          `json:"source,omitempty"`
+	Middleware  []string        `json:"middleware,omitempty"`
+	Parameters  []jsonParameter `json:"parameters,omitempty"`
+	RequestBody *jsonBody       `json:"requestBody,omitempty"`
+	Responses   []jsonResponse  `json:"responses,omitempty"`
+}
+
+// jsonErrorHandler describes the HTTP error handler and the errors it writes
+type jsonErrorHandler struct {
+	Handler   string         `json:"handler"`
+	Source    string         `json:"source,omitempty"`
+	Responses []jsonResponse `json:"responses,omitempty"`
+}
+
+// jsonParameter describes a request input read by a handler
+type jsonParameter struct {
+	In       string `json:"in"`
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required"`
+	Default  string `json:"default,omitempty"`
+}
+
+// jsonBody describes a request body bound by a handler
+type jsonBody struct {
+	Type      string      `json:"type,omitempty"`
+	Required  bool        `json:"required"`
+	Validated bool        `json:"validated"`
+	Schema    interface{} `json:"schema,omitempty"`
+}
+
+// jsonResponse describes a response written by a handler
+type jsonResponse struct {
+	Status      int         `json:"status"`
+	Type        string      `json:"type"`
+	ContentType string      `json:"contentType,omitempty"`
+	Primary     bool        `json:"primary,omitempty"`
+	Template    string      `json:"template,omitempty"`
+	Model       string      `json:"model,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+}
+
+// jsonEvent describes an AWS event produced or consumed by the code
+type jsonEvent struct {
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+	Direction string `json:"direction"`
+	Target    string `json:"target,omitempty"`
+	Handler   string `json:"handler,omitempty"`
+	BatchSize int    `json:"batchSize,omitempty"`
+	Source    string `json:"source,omitempty"`
+
+	MessageType string      `json:"messageType,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+}
+
+// generateJSON generates JSON documentation, with a standalone JSON Schema
+// document for every request body and JSON response
+func (g *DocGenerator) generateJSON() error {
+	doc := jsonDocument{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Middleware:  g.Middleware,
+		Endpoints:   []jsonEndpoint{},
+	}
+
+	for _, route := range g.Routes {
+		endpoint := jsonEndpoint{
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    route.HandlerName,
+			Service:    route.Service,
+			Protocol:   route.Protocol,
+			NotFound:   route.NotFound,
+			StaticDir:  route.StaticDir,
+			Deprecated: g.isDeprecated(route),
+			Source:     g.sourceLocation(route.Position),
+			Middleware: route.Middleware,
+		}
+		if route.Source != "" {
+			endpoint.Repository = g.relativePath(route.Source)
+		}
+
+		if handler := g.getHandlerForRoute(route); handler != nil {
+			for _, input := range handler.RequestInputs {
+				if input.Type == "Body" {
+					continue
+				}
+				endpoint.Parameters = append(endpoint.Parameters, jsonParameter{
+					In:       input.Type,
+					Name:     input.Name,
+					Type:     input.DataType,
+					Required: input.Required,
+					Default:  input.Default,
+				})
+			}
+
+			if body := handler.RequestBody(); body != nil {
+				endpoint.RequestBody = &jsonBody{
+					Type:      body.DataType,
+					Required:  body.Required,
+					Validated: body.Validated,
+					Schema:    g.jsonSchemaDocument(g.RequestTypes[handler.Name]),
+				}
+			}
+
+			endpoint.Responses = g.jsonResponses(handler)
+		}
+
+		doc.Endpoints = append(doc.Endpoints, endpoint)
+	}
+
+	if g.ErrorHandler != nil {
+		doc.ErrorHandler = &jsonErrorHandler{
+			Handler:   g.ErrorHandler.Name,
+			Source:    g.sourceLocation(g.ErrorHandler.Position),
+			Responses: g.jsonResponses(g.ErrorHandler),
+		}
+	}
+
+	for _, event := range g.Events {
+		jsonEvent := jsonEvent{
+			Service:   event.Service,
+			Operation: event.Operation,
+			Direction: string(event.Direction),
+			Target:    event.Target,
+			Handler:   event.Handler,
+			BatchSize: event.BatchSize,
+			Source:    g.sourceLocation(event.Position),
+		}
+		if event.MessageType != nil {
+			jsonEvent.MessageType = event.MessageType.Name
+			jsonEvent.Schema = g.jsonSchemaDocument(event.MessageType)
+		}
+		doc.Events = append(doc.Events, jsonEvent)
+	}
+
+	jsonData, err := marshalJSON(doc)
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON documentation: %v", err)
+	}
+
+	if err := writeOutput(g.OutputFile, jsonData); err != nil {
+		return fmt.Errorf("error writing JSON documentation: %v", err)
+	}
+
+	return nil
+}
+
+// jsonResponses describes the responses written by a handler
+func (g *DocGenerator) jsonResponses(handler *analyzer.HandlerInfo) []jsonResponse {
+	var responses []jsonResponse
+	for _, output := range handler.ResponseOutputs {
+		response := jsonResponse{
+			Status:      output.StatusCode,
+			Type:        output.Type,
+			ContentType: output.ContentType,
+			Primary:     output.Primary,
+			Template:    output.Template,
+		}
+		if output.Template != "" && output.DataType != "unknown" {
+			response.Model = output.DataType
+		}
+		if output.Type == "JSON" {
+			responseType := types.UnknownType()
+			key := fmt.Sprintf("%s_%d", handler.Name, output.StatusCode)
+			if responseInfo, exists := g.ResponseTypes[key]; exists && responseInfo.Type != nil {
+				responseType = responseInfo.Type
+			}
+			response.Schema = g.jsonSchemaDocument(responseType)
+		}
+		if output.Type == "XML" && g.SchemaGenerator != nil {
+			key := fmt.Sprintf("%s_%d", handler.Name, output.StatusCode)
+			if responseInfo, exists := g.ResponseTypes[key]; exists && responseInfo.Type != nil {
+				if value, err := types.JSONSchemaValue(g.SchemaGenerator.GenerateXMLSchema(responseInfo.Type)); err == nil {
+					response.Schema = value
+				}
+			}
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// jsonSchemaDocument generates the schema document of a type as generic JSON
+// values, or nil if there is no type or schema generator
+func (g *DocGenerator) jsonSchemaDocument(typeDef *types.TypeDefinition) interface{} {
+	if typeDef == nil || g.SchemaGenerator == nil {
+		return nil
+	}
+
+	doc := g.SchemaGenerator.GenerateSchemaDocument(typeDef)
+	if doc == nil {
+		return nil
+	}
+
+	value, err := types.JSONSchemaValue(doc)
+	if err != nil {
+		g.Log.Debugf("Error converting schema for type %s: %v", typeDef.Name, err)
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// StdoutOutput is the output file name that writes the output to standard output
+const StdoutOutput = "-"
+
+// Stdout receives output written to StdoutOutput
+var Stdout io.Writer = os.Stdout
+
+// JSONIndent is the indentation of every JSON output, such as "  " or "\t";
+// empty writes compact JSON
+var JSONIndent = "  "
+
+// ParseJSONIndent parses an indentation given as a number of spaces, "tab"
+// or "compact"
+func ParseJSONIndent(value string) (string, error) {
+	switch value {
+	case "tab":
+		return "\t", nil
+	case "compact":
+		return "", nil
+	}
+	spaces, err := strconv.Atoi(value)
+	if err != nil || spaces < 0 {
+		return "", fmt.Errorf("invalid JSON indentation %q: expected a number of spaces, tab or compact", value)
+	}
+	return strings.Repeat(" ", spaces), nil
+}
+
+// marshalJSON encodes a value as JSON indented with JSONIndent. Compact
+// JSON has the same keys in the same order as indented JSON: map keys are
+// sorted and struct fields keep their declared order, so it's deterministic.
+func marshalJSON(v interface{}) ([]byte, error) {
+	if JSONIndent == "" {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", JSONIndent)
+}
+
+// writeOutput writes generated output to a file atomically: the data goes to
+// a temporary file in the same directory, which replaces the target once it
+// is complete, so a failure never leaves a truncated file behind. The output
+// file StdoutOutput writes to Stdout instead.
+func writeOutput(outputFile string, data []byte) error {
+	if outputFile == StdoutOutput {
+		_, err := Stdout.Write(data)
+		return err
+	}
+
+	// The temporary file must be on the same file system for the rename
+	dir, base := filepath.Split(outputFile)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), outputFile)
+}
+
+// WriteJSON writes a value as JSON indented with JSONIndent to a file, or to
+// Stdout if the file is StdoutOutput
+func WriteJSON(outputFile string, v interface{}) error {
+	data, err := marshalJSON(v)
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %v", err)
+	}
+	return writeOutput(outputFile, append(data, '\n'))
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSONKeyOrder(t *testing.T) {
+	defer func(indent string) { JSONIndent = indent }(JSONIndent)
+
+	spec := OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    OpenAPIInfo{Title: "API", Version: "1.0.0"},
+		Paths: map[string]PathItem{
+			"/users":    {"post": {OperationID: "createUser"}, "get": {OperationID: "listUsers"}},
+			"/accounts": {"get": {OperationID: "listAccounts"}},
+		},
+	}
+
+	JSONIndent = ""
+	compact, err := marshalJSON(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Map keys are sorted and struct fields keep their order in compact JSON
+	previous := -1
+	for _, key := range []string{`{"openapi":`, `"info":`, `"paths":`, `"/accounts":`, `"/users":{"get":`, `"post":`, `"components":`} {
+		index := bytes.Index(compact, []byte(key))
+		if index <= previous {
+			t.Errorf("compact JSON = %s, want %s after the previous keys", compact, key)
+		}
+		previous = index
+	}
+
+	// Every indentation has the same keys in the same order
+	for _, indent := range []string{"  ", "\t"} {
+		JSONIndent = indent
+		indented, err := marshalJSON(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, indented); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != string(compact) {
+			t.Errorf("JSON indented with %q compacts to %s, want %s", indent, buf.String(), compact)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+)
+
+// SetPathRewrite rewrites the paths of the routes set with SetData to their
+// public form: stripPrefix is removed from the start of the paths it
+// matches, and basePath is prepended to every path, e.g. /api/v2 when the
+// application is mounted there by a gateway. Either may be empty.
+func (g *DocGenerator) SetPathRewrite(stripPrefix, basePath string) {
+	if stripPrefix == "" && basePath == "" {
+		return
+	}
+
+	routes := make([]scanner.RouteInfo, len(g.Routes))
+	for i, route := range g.Routes {
+		route.Path = rewritePath(route.Path, stripPrefix, basePath)
+		routes[i] = route
+	}
+	g.Routes = routes
+}
+
+// rewritePath removes a prefix from a route path, if the path starts with
+// it as whole segments, and prepends a base path
+func rewritePath(path, stripPrefix, basePath string) string {
+	if stripPrefix = strings.TrimSuffix(stripPrefix, "/"); stripPrefix != "" {
+		if path == stripPrefix {
+			path = "/"
+		} else if strings.HasPrefix(path, stripPrefix+"/") {
+			path = strings.TrimPrefix(path, stripPrefix)
+		}
+	}
+
+	if basePath = strings.TrimSuffix(basePath, "/"); basePath != "" {
+		if !strings.HasPrefix(basePath, "/") {
+			basePath = "/" + basePath
+		}
+		path = basePath + path
+	}
+	return path
+}
+
+// declarePathParams adds a required string parameter for each parameter of
+// a route path that isn't declared yet
+func declarePathParams(params []Parameter, path string) []Parameter {
+	declared := make(map[string]bool)
+	for _, param := range params {
+		if param.In == "path" {
+			declared[param.Name] = true
+		}
+	}
+
+	for _, name := range pathParamNames(path) {
+		if declared[name] {
+			continue
+		}
+		declared[name] = true
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   map[string]string{"type": "string"},
+		})
+	}
+	return params
+}
+
+// pathParamNames returns the names of the parameters of a route path, in
+// order, with the * wildcard named WildcardParamName
+func pathParamNames(path string) []string {
+	var names []string
+	for _, part := range scanner.SplitPath(path) {
+		if part.Param {
+			names = append(names, part.Text)
+		} else if part.Wildcard {
+			names = append(names, WildcardParamName)
+		}
+	}
+	return names
+}
+
+// pathTemplate returns a route path with each parameter replaced by the
+// result of param, which receives the parameter name
+func pathTemplate(path string, param func(name string) string) string {
+	var sb strings.Builder
+	for _, part := range scanner.SplitPath(path) {
+		switch {
+		case part.Param:
+			sb.WriteString(param(part.Text))
+		case part.Wildcard:
+			sb.WriteString(param(WildcardParamName))
+		default:
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToOpenAPIPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		params []string
+	}{
+		{"/", "/", nil},
+		{"/users", "/users", nil},
+		{"/users/", "/users/", nil},
+		{"/users/:id", "/users/{id}", []string{"id"}},
+		{"/users/:id/", "/users/{id}/", []string{"id"}},
+		{"/a/:b/c/:d", "/a/{b}/c/{d}", []string{"b", "d"}},
+		{"/a/:b/c/:d/", "/a/{b}/c/{d}/", []string{"b", "d"}},
+		{"/files/:name.json", "/files/{name.json}", []string{"name.json"}},
+		{"/v:version/users", "/v{version}/users", []string{"version"}},
+		{"/files/*", "/files/{filepath}", []string{"filepath"}},
+		{"/files*", "/files{filepath}", []string{"filepath"}},
+		{"/x/*rest", "/x/{filepath}", []string{"filepath"}},
+		{"/x/*/y", "/x/{filepath}", []string{"filepath"}},
+		{"/users/:id/files/*", "/users/{id}/files/{filepath}", []string{"id", "filepath"}},
+		{"/a:/b", "/a:/b", nil},
+	}
+	for _, test := range tests {
+		if got := toOpenAPIPath(test.path); got != test.want {
+			t.Errorf("toOpenAPIPath(%q) = %q, want %q", test.path, got, test.want)
+		}
+		if got := pathParamNames(test.path); !reflect.DeepEqual(got, test.params) {
+			t.Errorf("pathParamNames(%q) = %v, want %v", test.path, got, test.params)
+		}
+	}
+}
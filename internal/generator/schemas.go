@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// SchemaTypes returns the named struct types of every package in a registry,
+// keyed by name. Names declared in several packages are qualified with the
+// package name, e.g. models.User. Generic types are left out, since their
+// schema depends on their type arguments.
+func SchemaTypes(registry *types.TypeRegistry) map[string]*types.TypeDefinition {
+	structs := []*types.TypeDefinition{}
+	count := make(map[string]int)
+	for _, pkg := range registry.Packages {
+		for name, typeDef := range pkg.Types {
+			if typeDef.Kind != types.KindStruct || len(typeDef.TypeParams) > 0 || strings.Contains(name, "[") {
+				continue
+			}
+			structs = append(structs, typeDef)
+			count[typeDef.Name]++
+		}
+	}
+
+	schemaTypes := make(map[string]*types.TypeDefinition)
+	for _, typeDef := range structs {
+		name := typeDef.Name
+		if count[name] > 1 {
+			name = path.Base(typeDef.Package) + "." + name
+		}
+		schemaTypes[name] = typeDef
+	}
+	return schemaTypes
+}
+
+// WriteSchemas writes a JSON Schema document for each named struct type of a
+// registry. If the output ends with .json, or is StdoutOutput, it is a single
+// JSON object mapping type names to their schemas; otherwise it is a
+// directory with one Name.schema.json file per type. It returns the number
+// of schemas written.
+func WriteSchemas(output string, registry *types.TypeRegistry, schemaGenerator *types.SchemaGenerator) (int, error) {
+	schemaTypes := SchemaTypes(registry)
+	names := make([]string, 0, len(schemaTypes))
+	for name := range schemaTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if output == StdoutOutput || strings.HasSuffix(output, ".json") {
+		schemas := make(map[string]interface{})
+		for _, name := range names {
+			schema, err := types.JSONSchemaValue(schemaGenerator.GenerateSchemaDocument(schemaTypes[name]))
+			if err != nil {
+				return 0, fmt.Errorf("error generating schema for type %s: %v", name, err)
+			}
+			schemas[name] = schema
+		}
+		data, err := marshalJSON(schemas)
+		if err != nil {
+			return 0, fmt.Errorf("error marshaling schemas: %v", err)
+		}
+		if err := writeOutput(output, append(data, '\n')); err != nil {
+			return 0, fmt.Errorf("error writing output file: %v", err)
+		}
+		return len(names), nil
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return 0, fmt.Errorf("error creating output directory: %v", err)
+	}
+	for _, name := range names {
+		doc := schemaGenerator.GenerateSchemaDocument(schemaTypes[name])
+		if doc == nil {
+			return 0, fmt.Errorf("error generating schema for type %s", name)
+		}
+		schema, err := types.JSONSchemaValue(doc)
+		if err != nil {
+			return 0, fmt.Errorf("error generating schema for type %s: %v", name, err)
+		}
+		data, err := marshalJSON(schema)
+		if err != nil {
+			return 0, fmt.Errorf("error marshaling schema for type %s: %v", name, err)
+		}
+		if err := writeOutput(filepath.Join(output, name+".schema.json"), append(data, '\n')); err != nil {
+			return 0, fmt.Errorf("error writing schema for type %s: %v", name, err)
+		}
+	}
+	return len(names), nil
+}
+
+// applySchemaDraft turns an OpenAPI 3.0 specification into OpenAPI 3.1 with
+// component and message schemas in the JSON Schema draft of SchemaDraft, e.g.
+// nullable properties typed as ["string", "null"]
+func (g *DocGenerator) applySchemaDraft(spec *OpenAPISpec) {
+	dialect, err := types.SchemaDialect(g.SchemaDraft)
+	if err != nil {
+		g.Log.Debugf("Warning: %v", err)
+		return
+	}
+	spec.OpenAPI = "3.1.0"
+	spec.Dialect = dialect
+
+	for name, schema := range spec.Components.Schemas {
+		if value, err := types.JSONSchemaValue(schema); err == nil {
+			spec.Components.Schemas[name] = value
+		}
+	}
+	for i, event := range spec.Events {
+		if event.Message == nil {
+			continue
+		}
+		if value, err := types.JSONSchemaValue(event.Message); err == nil {
+			spec.Events[i].Message = value
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of middleware documented in the security of an operation
+const (
+	SecurityBearer    = "bearer"    // Bearer token, e.g. a JWT
+	SecurityBasic     = "basic"     // HTTP basic authentication
+	SecurityAPIKey    = "apiKey"    // API key in a header
+	SecurityRateLimit = "rateLimit" // Rate limited requests
+)
+
+// defaultSecurityMiddleware maps the constructors of Echo's authentication
+// and rate limiting middleware to the kind of security they enforce
+var defaultSecurityMiddleware = map[string]string{
+	"middleware.JWT":                   SecurityBearer,
+	"middleware.JWTWithConfig":         SecurityBearer,
+	"echojwt.JWT":                      SecurityBearer,
+	"echojwt.WithConfig":               SecurityBearer,
+	"middleware.BasicAuth":             SecurityBasic,
+	"middleware.BasicAuthWithConfig":   SecurityBasic,
+	"middleware.KeyAuth":               SecurityAPIKey,
+	"middleware.KeyAuthWithConfig":     SecurityAPIKey,
+	"middleware.RateLimiter":           SecurityRateLimit,
+	"middleware.RateLimiterWithConfig": SecurityRateLimit,
+}
+
+// securitySchemes are the OpenAPI security schemes of the authentication
+// kinds, named as they appear in components/securitySchemes
+var securitySchemes = map[string]struct {
+	Name   string
+	Scheme SecurityScheme
+}{
+	SecurityBearer: {"bearerAuth", SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}},
+	SecurityBasic:  {"basicAuth", SecurityScheme{Type: "http", Scheme: "basic"}},
+	SecurityAPIKey: {"apiKeyAuth", SecurityScheme{Type: "apiKey", In: "header", Name: "Authorization"}},
+}
+
+// SecurityScheme represents a security scheme in an OpenAPI specification
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// ParseSecurityMiddleware parses a middleware mapping given as Name=kind,
+// e.g. auth.RequireUser=bearer, where kind is bearer, basic, apiKey or
+// rateLimit
+func ParseSecurityMiddleware(spec string) (string, string, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid security middleware %q, expected Name=kind", spec)
+	}
+	switch parts[1] {
+	case SecurityBearer, SecurityBasic, SecurityAPIKey, SecurityRateLimit:
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("invalid kind %q in security middleware %q, expected bearer, basic, apiKey or rateLimit", parts[1], spec)
+}
+
+// RegisterSecurityMiddleware maps a middleware constructor, named as it is
+// called, e.g. auth.RequireUser, to the kind of security it enforces
+func (g *DocGenerator) RegisterSecurityMiddleware(name, kind string) {
+	if g.SecurityMiddleware == nil {
+		g.SecurityMiddleware = make(map[string]string)
+	}
+	g.SecurityMiddleware[name] = kind
+}
+
+// securityKinds returns the kinds of security enforced by a list of
+// middleware, sorted, without duplicates
+func (g *DocGenerator) securityKinds(middleware []string) []string {
+	seen := make(map[string]bool)
+	kinds := []string{}
+	for _, name := range middleware {
+		kind, exists := g.SecurityMiddleware[name]
+		if !exists {
+			kind, exists = defaultSecurityMiddleware[name]
+		}
+		if exists && !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// applySecurity sets the security requirements of an operation from the
+// middleware applied to its route, global middleware included, and adds the
+// schemes it requires to the components. Every scheme is required, so they
+// form a single requirement.
+func (g *DocGenerator) applySecurity(operation *Operation, middleware []string, components *OpenAPIComponents) {
+	requirement := map[string][]string{}
+	for _, kind := range g.securityKinds(middleware) {
+		if kind == SecurityRateLimit {
+			operation.RateLimited = true
+			continue
+		}
+		scheme := securitySchemes[kind]
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = make(map[string]SecurityScheme)
+		}
+		components.SecuritySchemes[scheme.Name] = scheme.Scheme
+		requirement[scheme.Name] = []string{}
+	}
+	if len(requirement) > 0 {
+		operation.Security = []map[string][]string{requirement}
+	}
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/user/golang-echo-analyzer/internal/analyzer"
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+)
+
+// Sort orders the routes set with SetData by service, path and method
+// instead of registration order, the parameters of every handler by name
+// and its responses by status code, so the output of an unchanged API stays
+// the same when its code is reorganized. Handlers are copied, leaving the
+// analysis results untouched.
+func (g *DocGenerator) Sort() {
+	routes := append([]scanner.RouteInfo{}, g.Routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Service != routes[j].Service {
+			return routes[i].Service < routes[j].Service
+		}
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	g.Routes = routes
+
+	handlers := make(map[string]*analyzer.HandlerInfo, len(g.Handlers))
+	for name, handler := range g.Handlers {
+		handlers[name] = sortedHandler(handler)
+	}
+	g.Handlers = handlers
+	if g.ErrorHandler != nil {
+		g.ErrorHandler = sortedHandler(g.ErrorHandler)
+	}
+}
+
+// sortedHandler returns a copy of a handler with its parameters sorted by
+// name and its responses by status code
+func sortedHandler(handler *analyzer.HandlerInfo) *analyzer.HandlerInfo {
+	sorted := *handler
+
+	sorted.RequestInputs = append([]analyzer.RequestInput{}, handler.RequestInputs...)
+	sort.SliceStable(sorted.RequestInputs, func(i, j int) bool {
+		return sorted.RequestInputs[i].Name < sorted.RequestInputs[j].Name
+	})
+
+	sorted.ResponseOutputs = append([]analyzer.ResponseOutput{}, handler.ResponseOutputs...)
+	sort.SliceStable(sorted.ResponseOutputs, func(i, j int) bool {
+		return sorted.ResponseOutputs[i].StatusCode < sorted.ResponseOutputs[j].StatusCode
+	})
+	return &sorted
+}
@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/user/golang-echo-analyzer/internal/types"
+)
+
+// tsInterface represents a TypeScript interface generated from a Go struct
+type tsInterface struct {
+	Name   string
+	Fields []tsField
+}
+
+// tsField represents a property of a TypeScript interface
+type tsField struct {
+	Name       string
+	Type       string
+	Optional   bool
+	Deprecated bool
+}
+
+// tsAlias represents a TypeScript type alias for a handler's request body or response
+type tsAlias struct {
+	Name string
+	Type string
+}
+
+// tsEndpoint represents a typed client function for a route
+type tsEndpoint struct {
+	clientEndpoint
+	RequestName  string
+	URL          string
+	BodyType     string
+	ResponseType string
+}
+
+// typeScriptBuilder converts resolved type definitions to TypeScript declarations
+type typeScriptBuilder struct {
+	interfaces []*tsInterface
+	names      map[string]string // type key to interface name
+	used       map[string]bool   // interface names already taken
+}
+
+// generateTypeScript generates TypeScript type definitions and, if enabled, a typed client
+func (g *DocGenerator) generateTypeScript() error {
+	builder := &typeScriptBuilder{
+		names: make(map[string]string),
+		used:  make(map[string]bool),
+	}
+
+	// Declare a type alias for every resolved request body and analyzed
+	// response, in a stable order
+	requestKeys := make([]string, 0, len(g.RequestTypes))
+	for key, requestType := range g.RequestTypes {
+		if requestType != nil {
+			requestKeys = append(requestKeys, key)
+		}
+	}
+	sort.Strings(requestKeys)
+
+	aliases := []tsAlias{}
+	for _, key := range requestKeys {
+		aliases = append(aliases, tsAlias{
+			Name: requestTypeName(key),
+			Type: builder.typeOf(derefType(g.RequestTypes[key])),
+		})
+	}
+
+	keys := make([]string, 0, len(g.ResponseTypes))
+	for key, responseInfo := range g.ResponseTypes {
+		if responseInfo.Type != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		aliases = append(aliases, tsAlias{
+			Name: responseTypeName(key),
+			Type: builder.typeOf(g.ResponseTypes[key].Type),
+		})
+	}
+
+	// Build the client endpoints
+	endpoints := []tsEndpoint{}
+	if g.TypeScriptClient {
+		for _, endpoint := range g.clientEndpoints() {
+			endpoints = append(endpoints, tsEndpoint{
+				clientEndpoint: endpoint,
+				RequestName:    exportedName(endpoint.FuncName) + "Request",
+				URL:            endpoint.urlTemplate(tsPathParam),
+				BodyType:       endpoint.requestType(requestTypeName, "unknown"),
+				ResponseType:   endpoint.responseType(responseTypeName, "void", "unknown"),
+			})
+		}
+	}
+
+	// Create the template
+	tmpl, err := template.New("typescript").Parse(typeScriptTemplate)
+	if err != nil {
+		return fmt.Errorf("error creating template: %v", err)
+	}
+
+	data := struct {
+		Interfaces  []*tsInterface
+		Aliases     []tsAlias
+		Endpoints   []tsEndpoint
+		GeneratedAt string
+	}{
+		Interfaces:  builder.interfaces,
+		Aliases:     aliases,
+		Endpoints:   endpoints,
+		GeneratedAt: time.Now().Format("January 2, 2006 15:04:05"),
+	}
+
+	// Execute the template
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("error executing template: %v", err)
+	}
+
+	// Write to file
+	if err := writeOutput(g.OutputFile, buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing TypeScript definitions: %v", err)
+	}
+
+	return nil
+}
+
+// tsPathParam returns the URL template expression for a path parameter
+func tsPathParam(name string) string {
+	return fmt.Sprintf("${encodeURIComponent(request.path[%s])}", strconv.Quote(name))
+}
+
+// typeOf returns the TypeScript type expression for a type definition,
+// declaring interfaces for the named structs it references
+func (b *typeScriptBuilder) typeOf(typeDef *types.TypeDefinition) string {
+	if typeDef == nil {
+		return "unknown"
+	}
+
+	switch typeDef.Kind {
+	case types.KindStruct:
+		if typeDef.Name == "" || typeDef.Name == "anonymous" {
+			return b.inlineStruct(typeDef)
+		}
+		return b.declareInterface(typeDef)
+	case types.KindArray:
+		elemType := b.typeOf(typeDef.ElementType)
+		if strings.Contains(elemType, "|") {
+			elemType = "(" + elemType + ")"
+		}
+		return elemType + "[]"
+	case types.KindMap:
+		return fmt.Sprintf("Record<string, %s>", b.typeOf(typeDef.ValueType))
+	case types.KindPointer:
+		return b.typeOf(typeDef.ElementType) + " | null"
+	case types.KindBasic:
+		return tsBasicType(typeDef.BasicType)
+	}
+
+	return "unknown"
+}
+
+// declareInterface declares an interface for a named struct and returns its name
+func (b *typeScriptBuilder) declareInterface(typeDef *types.TypeDefinition) string {
+	key := typeDef.Service + ":" + typeDef.Package + "." + typeDef.Name
+	if name, exists := b.names[key]; exists {
+		return name
+	}
+
+	// Qualify the name with the service, and then the package, if another
+	// service or package already uses it. Instantiated generic types, such
+	// as Page[User], are named like in the Go client (PageUser).
+	name := exportedName(typeDef.Name)
+	if b.used[name] && typeDef.Service != "" {
+		name = exportedName(typeDef.Service) + name
+	}
+	if b.used[name] {
+		pkg := typeDef.Package[strings.LastIndex(typeDef.Package, "/")+1:]
+		name = exportedName(pkg) + name
+	}
+	b.names[key] = name
+	b.used[name] = true
+
+	// Register the interface before resolving fields so recursive types terminate
+	iface := &tsInterface{Name: name}
+	b.interfaces = append(b.interfaces, iface)
+	iface.Fields = b.fields(typeDef)
+
+	return name
+}
+
+// inlineStruct returns an inline object type for an anonymous struct
+func (b *typeScriptBuilder) inlineStruct(typeDef *types.TypeDefinition) string {
+	parts := []string{}
+	for _, field := range b.fields(typeDef) {
+		optional := ""
+		if field.Optional {
+			optional = "?"
+		}
+		parts = append(parts, fmt.Sprintf("%s%s: %s", field.Name, optional, field.Type))
+	}
+	if len(parts) == 0 {
+		return "Record<string, unknown>"
+	}
+	return "{ " + strings.Join(parts, "; ") + " }"
+}
+
+// fields converts the fields of a struct type to interface properties
+func (b *typeScriptBuilder) fields(typeDef *types.TypeDefinition) []tsField {
+	fields := []tsField{}
+	for _, field := range typeDef.Fields {
+		if field.Type == nil || field.JSONName == "-" {
+			continue
+		}
+
+		name := field.Name
+		if field.JSONName != "" {
+			name = field.JSONName
+		}
+		if !token.IsIdentifier(name) {
+			name = strconv.Quote(name)
+		}
+
+		// Pointer fields may be null even if their type didn't resolve to a pointer
+		fieldType := b.typeOf(field.Type)
+		if field.IsPointer && !strings.HasSuffix(fieldType, " | null") {
+			fieldType += " | null"
+		}
+
+		fields = append(fields, tsField{
+			Name:       name,
+			Type:       fieldType,
+			Optional:   field.Omitempty,
+			Deprecated: field.Deprecated,
+		})
+	}
+	return fields
+}
+
+// tsBasicType maps a Go basic type to a TypeScript type
+func tsBasicType(basicType string) string {
+	switch basicType {
+	case "string", "time.Time":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "interface{}", "any":
+		return "unknown"
+	}
+	return "string"
+}
+
+// TypeScript template for type definitions and the client
+const typeScriptTemplate = `// API type definitions
+// Generated by Echo Framework Static Analyzer at {{.GeneratedAt}}
+{{range .Interfaces}}
+export interface {{.Name}} {
+{{- range .Fields}}
+{{- if .Deprecated}}
+  /** @deprecated */
+{{- end}}
+  {{.Name}}{{if .Optional}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{end}}
+{{- range .Aliases}}
+export type {{.Name}} = {{.Type}};
+{{end}}
+{{- if .Endpoints}}
+// Client
+
+export let baseUrl = "";
+{{range .Endpoints}}
+export interface {{.RequestName}} {
+  path{{if not .PathParams}}?{{end}}: { {{- range .PathParams}} {{printf "%q" .}}: string;{{end}} };
+  query?: { {{- range .QueryParams}} {{printf "%q" .}}?: string;{{end}} };
+  headers?: { {{- range .HeaderParams}} {{printf "%q" .}}?: string;{{end}} };
+  body{{if not .HasBody}}?{{end}}: {{if .HasBody}}{{.BodyType}}{{else}}undefined{{end}};
+}
+
+/** {{.Method}} {{.Path}} */
+export async function {{.FuncName}}(request: {{.RequestName}}{{if and (not .PathParams) (not .HasBody)}} = {}{{end}}, init?: RequestInit): Promise<{{.ResponseType}}> {
+  const query = new URLSearchParams(request.query as Record<string, string>).toString();
+  const response = await fetch(` + "`" + `${baseUrl}{{.URL}}${query ? "?" + query : ""}` + "`" + `, {
+    ...init,
+    method: "{{.Method}}",
+    headers: { "Content-Type": "application/json", ...init?.headers, ...request.headers },
+    body: request.body === undefined ? undefined : JSON.stringify(request.body),
+  });
+  if (!response.ok) {
+    throw new Error(` + "`" + `{{.Method}} {{.Path}} failed with status ${response.status}` + "`" + `);
+  }
+{{- if eq .ResponseType "void"}}
+}
+{{- else}}
+  return (await response.json()) as {{.ResponseType}};
+}
+{{- end}}
+{{end}}
+{{- end}}`
@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// componentSchemaRefPrefix is the prefix of $refs to component schemas
+const componentSchemaRefPrefix = "#/components/schemas/"
+
+// openAPIMethods are the HTTP methods with an operation key in an OpenAPI
+// path item
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// openAPIMethodKey returns the key of a route's operation in its path item:
+// the lowercase method, or an x- extension for Echo's Any routes and the
+// methods OpenAPI has no key for, e.g. x-any or x-propfind
+func openAPIMethodKey(method string) string {
+	key := strings.ToLower(method)
+	if !openAPIMethods[key] {
+		key = "x-" + key
+	}
+	return key
+}
+
+// openAPIParameterLocations are the valid locations of a parameter
+var openAPIParameterLocations = map[string]bool{
+	"path": true, "query": true, "header": true, "cookie": true,
+}
+
+// pathTemplateParam matches the parameters of a path template, as in /users/{id}
+var pathTemplateParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// SpecError is the error of an OpenAPI specification that isn't valid
+type SpecError struct {
+	Problems []string // What is wrong, each prefixed by where in the spec
+}
+
+// Error formats the error with every problem found
+func (e *SpecError) Error() string {
+	return fmt.Sprintf("invalid OpenAPI spec: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks that the specification is a valid OpenAPI document: the
+// required fields are set, path items only have operations of HTTP methods
+// OpenAPI knows or extensions, every operation has a unique operationId and at
+// least one response, the parameters of path templates are declared, and
+// every $ref resolves to a schema of components.schemas. It returns a
+// *SpecError listing the problems found, or nil.
+func (s OpenAPISpec) Validate() error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if s.OpenAPI == "" {
+		addProblem("openapi: version is missing")
+	}
+	if s.Info.Title == "" {
+		addProblem("info: title is missing")
+	}
+	if s.Info.Version == "" {
+		addProblem("info: version is missing")
+	}
+
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	operationIDs := make(map[string]string)
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			addProblem("paths.%s: path doesn't start with /", path)
+		}
+
+		item := s.Paths[path]
+		methods := make([]string, 0, len(item))
+		for method := range item {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operation := item[method]
+			where := fmt.Sprintf("paths.%s.%s", path, method)
+			if !openAPIMethods[method] && !strings.HasPrefix(method, "x-") {
+				addProblem("%s: %q is not an operation of a path item", where, method)
+			}
+
+			if operation.OperationID == "" {
+				addProblem("%s: operationId is missing", where)
+			} else if other, exists := operationIDs[operation.OperationID]; exists {
+				addProblem("%s: operationId %q is already used by %s", where, operation.OperationID, other)
+			} else {
+				operationIDs[operation.OperationID] = where
+			}
+
+			if len(operation.Responses) == 0 {
+				addProblem("%s: responses are missing", where)
+			}
+			statuses := make([]string, 0, len(operation.Responses))
+			for status := range operation.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				if operation.Responses[status].Description == "" {
+					addProblem("%s.responses.%s: description is missing", where, status)
+				}
+			}
+
+			declared := make(map[string]bool)
+			for i, param := range operation.Parameters {
+				if param.Name == "" {
+					addProblem("%s.parameters[%d]: name is missing", where, i)
+				}
+				if !openAPIParameterLocations[param.In] {
+					addProblem("%s.parameters[%d]: invalid location %q", where, i, param.In)
+				}
+				if param.In == "path" {
+					declared[param.Name] = true
+					if !param.Required {
+						addProblem("%s.parameters[%d]: path parameter %s isn't required", where, i, param.Name)
+					}
+				}
+			}
+			for _, match := range pathTemplateParam.FindAllStringSubmatch(path, -1) {
+				if !declared[match[1]] {
+					addProblem("%s: path parameter %s isn't declared", where, match[1])
+				}
+			}
+		}
+	}
+
+	// Every $ref must point to a component schema
+	checkRefs := func(where string, value interface{}) {
+		for _, ref := range schemaRefs(normalizeSchema(value)) {
+			name := strings.TrimPrefix(ref, componentSchemaRefPrefix)
+			if _, exists := s.Components.Schemas[name]; !exists || name == ref {
+				addProblem("%s: $ref %s doesn't resolve to a component schema", where, ref)
+			}
+		}
+	}
+	for _, path := range paths {
+		checkRefs("paths."+path, s.Paths[path])
+	}
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		checkRefs("components.schemas."+name, s.Components.Schemas[name])
+	}
+	for i, event := range s.Events {
+		if event.Message != nil {
+			checkRefs(fmt.Sprintf("x-events[%d].message", i), event.Message)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &SpecError{Problems: problems}
+	}
+	return nil
+}
+
+// schemaRefs returns the distinct $refs of a normalized value, sorted
+func schemaRefs(value interface{}) []string {
+	found := make(map[string]bool)
+	var walk func(interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				found[ref] = true
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+
+	refs := make([]string, 0, len(found))
+	for ref := range found {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
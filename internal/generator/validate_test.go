@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/user/golang-echo-analyzer/internal/scanner"
+)
+
+func TestValidateReportsProblems(t *testing.T) {
+	spec := OpenAPISpec{
+		OpenAPI: "3.0.0",
+		Info:    OpenAPIInfo{Title: "API"},
+		Paths: map[string]PathItem{
+			"/users/{id}": {
+				"get": {
+					OperationID: "getUser",
+					Responses: map[string]Response{"200": {
+						Description: "OK",
+						Content: map[string]MediaTypeObject{"application/json": {
+							Schema: map[string]string{"$ref": "#/components/schemas/Missing"},
+						}},
+					}},
+				},
+				"post": {
+					OperationID: "getUser",
+					Parameters:  []Parameter{{Name: "id", In: "path"}},
+				},
+				"propfind": {
+					OperationID: "propfindUser",
+					Parameters:  []Parameter{{Name: "id", In: "path", Required: true}},
+					Responses:   map[string]Response{"207": {Description: "Multi-Status"}},
+				},
+			},
+		},
+		Components: OpenAPIComponents{Schemas: map[string]interface{}{
+			"List": map[string]interface{}{"items": map[string]interface{}{"$ref": "#/definitions/Item"}},
+		}},
+	}
+
+	err := spec.Validate()
+	var specErr *SpecError
+	if !errors.As(err, &specErr) {
+		t.Fatalf("Validate() = %v, want a *SpecError", err)
+	}
+
+	want := []string{
+		"info: version is missing",
+		"paths./users/{id}.get: path parameter id isn't declared",
+		`paths./users/{id}.post: operationId "getUser" is already used by paths./users/{id}.get`,
+		"paths./users/{id}.post: responses are missing",
+		"paths./users/{id}.post.parameters[0]: path parameter id isn't required",
+		`paths./users/{id}.propfind: "propfind" is not an operation of a path item`,
+		"paths./users/{id}: $ref #/components/schemas/Missing doesn't resolve to a component schema",
+		"components.schemas.List: $ref #/definitions/Item doesn't resolve to a component schema",
+	}
+	if got := strings.Join(specErr.Problems, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("problems:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestCreateOpenAPISpecIsValid(t *testing.T) {
+	g := NewDocGenerator(StdoutOutput, "openapi", nil)
+	g.SetData([]scanner.RouteInfo{
+		// echo.WrapHandler(http.NotFoundHandler()) isn't a handler the analyzer finds
+		{Method: "GET", Path: "/metrics", HandlerName: "echo.WrapHandler"},
+		{Method: "ANY", Path: "/any/:id", HandlerName: "h.any"},
+		{Method: "PROPFIND", Path: "/dav/*", HandlerName: "h.propfind"},
+	}, nil, nil)
+
+	spec := g.createOpenAPISpec()
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	for path, key := range map[string]string{"/metrics": "get", "/any/{id}": "x-any", "/dav/{filepath}": "x-propfind"} {
+		operation, exists := spec.Paths[path][key]
+		if !exists {
+			t.Errorf("paths.%s has no %s operation: %v", path, key, spec.Paths[path])
+			continue
+		}
+		if _, exists := operation.Responses["default"]; !exists {
+			t.Errorf("paths.%s.%s responses = %v, want a default response", path, key, operation.Responses)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log message
+type Level int
+
+// Log levels, from the least to the most verbose
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+// levelNames maps level names to levels, in the form accepted by ParseLevel
+var levelNames = map[string]Level{
+	"error": LevelError,
+	"warn":  LevelWarn,
+	"info":  LevelInfo,
+	"debug": LevelDebug,
+}
+
+// ParseLevel parses a level name: error, warn, info or debug
+func ParseLevel(name string) (Level, error) {
+	level, exists := levelNames[strings.ToLower(name)]
+	if !exists {
+		return LevelInfo, fmt.Errorf("invalid log level %q, expected error, warn, info or debug", name)
+	}
+	return level, nil
+}
+
+// Logger writes the messages at or above its level. Errors and warnings are
+// prefixed with their level, so they stand out in CI logs.
+type Logger struct {
+	Level Level
+	Out   io.Writer // Receives info and debug messages
+	Err   io.Writer // Receives errors, warnings and progress
+
+	mu sync.Mutex
+
+	// progressLine is set while a progress line is waiting to be finished
+	progressLine bool
+}
+
+// New creates a new Logger
+func New(out, err io.Writer, level Level) *Logger {
+	return &Logger{
+		Level: level,
+		Out:   out,
+		Err:   err,
+	}
+}
+
+// Enabled reports whether messages of a level are written. A nil Logger
+// writes nothing.
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && level <= l.Level
+}
+
+// Errorf writes an error message
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, "Error: ", format, args...)
+}
+
+// Warnf writes a warning message
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, "Warning: ", format, args...)
+}
+
+// Infof writes an informational message, such as the start of an analysis step
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, "", format, args...)
+}
+
+// Debugf writes a debug message
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, "", format, args...)
+}
+
+// logf writes a message on its own line if its level is enabled
+func (l *Logger) logf(level Level, prefix, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finishProgress()
+	fmt.Fprintf(l.writer(level), prefix+strings.TrimSuffix(format, "\n")+"\n", args...)
+}
+
+// Writer returns a writer for messages of a level, discarding them if the
+// level isn't enabled
+func (l *Logger) Writer(level Level) io.Writer {
+	if !l.Enabled(level) {
+		return io.Discard
+	}
+	return &logWriter{logger: l, w: l.writer(level)}
+}
+
+// writer returns the output of messages of a level: Err for errors and
+// warnings, Out for the others
+func (l *Logger) writer(level Level) io.Writer {
+	if level <= LevelWarn {
+		return l.Err
+	}
+	return l.Out
+}
+
+// logWriter writes to the output of a logger, finishing any progress line first
+type logWriter struct {
+	logger *Logger
+	w      io.Writer
+}
+
+func (lw *logWriter) Write(p []byte) (int, error) {
+	lw.logger.mu.Lock()
+	defer lw.logger.mu.Unlock()
+
+	lw.logger.finishProgress()
+	return lw.w.Write(p)
+}
+
+// Progress rewrites a progress line for a step, e.g. "  Parsing files: 12/40".
+// A total of 0 means it isn't known in advance. The line is finished once
+// done reaches the total, or by the next message.
+func (l *Logger) Progress(step string, done, total int) {
+	if !l.Enabled(LevelInfo) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if total > 0 {
+		fmt.Fprintf(l.Err, "\r  %s: %d/%d", step, done, total)
+	} else {
+		fmt.Fprintf(l.Err, "\r  %s: %d", step, done)
+	}
+	l.progressLine = true
+	if total > 0 && done >= total {
+		l.finishProgress()
+	}
+}
+
+// finishProgress ends the pending progress line, with the lock held
+func (l *Logger) finishProgress() {
+	if l.progressLine {
+		fmt.Fprintln(l.Err)
+		l.progressLine = false
+	}
+}
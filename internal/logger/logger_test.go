@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNilLoggerDiscards(t *testing.T) {
+	var l *Logger
+	if l.Enabled(LevelError) {
+		t.Error("a nil Logger is enabled")
+	}
+	l.Errorf("error")
+	l.Debugf("debug")
+	fmt.Fprintln(l.Writer(LevelInfo), "info")
+}
+
+func TestConcurrentMessagesDontInterleave(t *testing.T) {
+	var out bytes.Buffer
+	l := New(&out, &out, LevelDebug)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Debugf("worker %d message %d\n", worker, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 800 {
+		t.Fatalf("logged %d lines, want 800", len(lines))
+	}
+	for _, line := range lines {
+		var worker, message int
+		if n, err := fmt.Sscanf(line, "worker %d message %d", &worker, &message); n != 2 || err != nil {
+			t.Errorf("malformed line %q", line)
+		}
+	}
+}
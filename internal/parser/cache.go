@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"runtime"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
+)
+
+// CacheFileName is the default name of the parse cache file in the repository root
+const CacheFileName = ".analyzer-cache"
+
+// CacheVersion identifies the cache format. It must be bumped whenever the
+// parser or the structures stored in the cache change, so caches written by
+// older versions of the tool are discarded instead of being loaded.
+const CacheVersion = "1"
+
+// ParseCache stores parsed files keyed by path and content hash so unchanged
+// files don't need to be parsed again on subsequent runs. Only syntax trees
+// are cached: the types collected from them refer to types of other files,
+// which may have changed, so they're collected and resolved on every run.
+type ParseCache struct {
+	Path    string
+	FileSet *token.FileSet
+	Files   map[string]*CachedFile
+	Log     *logger.Logger
+}
+
+// CachedFile represents a cached parse result for a single file
+type CachedFile struct {
+	Hash    string
+	Package string
+	File    *ast.File
+}
+
+// cacheData is the on-disk representation of the cache
+type cacheData struct {
+	Version string
+	Files   map[string]*CachedFile
+}
+
+func init() {
+	// Register all AST node types stored behind interfaces so gob can encode them
+	nodes := []interface{}{
+		&ast.BadExpr{}, &ast.Ident{}, &ast.Ellipsis{}, &ast.BasicLit{}, &ast.FuncLit{},
+		&ast.CompositeLit{}, &ast.ParenExpr{}, &ast.SelectorExpr{}, &ast.IndexExpr{},
+		&ast.IndexListExpr{}, &ast.SliceExpr{}, &ast.TypeAssertExpr{}, &ast.CallExpr{},
+		&ast.StarExpr{}, &ast.UnaryExpr{}, &ast.BinaryExpr{}, &ast.KeyValueExpr{},
+		&ast.ArrayType{}, &ast.StructType{}, &ast.FuncType{}, &ast.InterfaceType{},
+		&ast.MapType{}, &ast.ChanType{},
+		&ast.BadStmt{}, &ast.DeclStmt{}, &ast.EmptyStmt{}, &ast.LabeledStmt{},
+		&ast.ExprStmt{}, &ast.SendStmt{}, &ast.IncDecStmt{}, &ast.AssignStmt{},
+		&ast.GoStmt{}, &ast.DeferStmt{}, &ast.ReturnStmt{}, &ast.BranchStmt{},
+		&ast.BlockStmt{}, &ast.IfStmt{}, &ast.CaseClause{}, &ast.SwitchStmt{},
+		&ast.TypeSwitchStmt{}, &ast.CommClause{}, &ast.SelectStmt{}, &ast.ForStmt{},
+		&ast.RangeStmt{},
+		&ast.ImportSpec{}, &ast.ValueSpec{}, &ast.TypeSpec{},
+		&ast.BadDecl{}, &ast.GenDecl{}, &ast.FuncDecl{},
+	}
+	for _, node := range nodes {
+		gob.Register(node)
+	}
+}
+
+// cacheVersion returns the version key stored in the cache. The Go version is
+// included because the shape of the AST can change between Go releases.
+func cacheVersion() string {
+	return CacheVersion + "/" + runtime.Version()
+}
+
+// NewParseCache creates an empty ParseCache that will be persisted to path
+func NewParseCache(path string, log *logger.Logger) *ParseCache {
+	return &ParseCache{
+		Path:    path,
+		FileSet: token.NewFileSet(),
+		Files:   make(map[string]*CachedFile),
+		Log:     log,
+	}
+}
+
+// LoadParseCache loads a ParseCache from path. A missing, unreadable or
+// outdated cache file results in an empty cache rather than an error.
+func LoadParseCache(path string, log *logger.Logger) *ParseCache {
+	cache := NewParseCache(path, log)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return cache
+	}
+	defer file.Close()
+
+	decoder := gob.NewDecoder(file)
+
+	var data cacheData
+	if err := decoder.Decode(&data); err != nil || data.Version != cacheVersion() {
+		log.Debugf("Discarding outdated or invalid cache: %s", path)
+		return cache
+	}
+
+	// The file set must be restored so positions in cached ASTs stay valid
+	fset := token.NewFileSet()
+	if err := fset.Read(decoder.Decode); err != nil {
+		log.Debugf("Discarding invalid cache file set: %v", err)
+		return cache
+	}
+
+	cache.FileSet = fset
+	cache.Files = data.Files
+
+	log.Debugf("Loaded %d cached files from %s", len(cache.Files), path)
+
+	return cache
+}
+
+// Lookup returns the cached AST for a file if its content hash is unchanged
+func (c *ParseCache) Lookup(path, hash string) *CachedFile {
+	if cached, exists := c.Files[path]; exists && cached.Hash == hash {
+		return cached
+	}
+	return nil
+}
+
+// Store records the parse result for a file
+func (c *ParseCache) Store(path, hash, pkgName string, file *ast.File) {
+	c.Files[path] = &CachedFile{
+		Hash:    hash,
+		Package: pkgName,
+		File:    file,
+	}
+}
+
+// Save writes the cache to disk, keeping only the files in live
+func (c *ParseCache) Save(live map[string]bool) error {
+	files := make(map[string]*CachedFile)
+	for path, cached := range c.Files {
+		if live[path] {
+			files[path] = cached
+		}
+	}
+
+	// Changed files are appended to the file set on every run; once stale
+	// entries outnumber live ones, start over so the cache doesn't grow forever
+	fset := c.FileSet
+	if countFiles(fset) > 2*len(files) {
+		c.Log.Debugf("Compacting parse cache")
+		files = make(map[string]*CachedFile)
+		fset = token.NewFileSet()
+	}
+
+	out, err := os.Create(c.Path)
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %v", err)
+	}
+	defer out.Close()
+
+	encoder := gob.NewEncoder(out)
+	if err := encoder.Encode(cacheData{Version: cacheVersion(), Files: files}); err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	if err := fset.Write(encoder.Encode); err != nil {
+		return fmt.Errorf("error writing cache file set: %v", err)
+	}
+
+	return nil
+}
+
+// countFiles returns the number of files in a file set
+func countFiles(fset *token.FileSet) int {
+	count := 0
+	fset.Iterate(func(*token.File) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// hashContent returns the hex-encoded SHA-256 hash of a file's content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"encoding/gob"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles writes files, by path relative to root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// parseWithCache parses a repository with the cache file in its root, saves
+// the cache and returns the parser
+func parseWithCache(t *testing.T, root string) *CodeParser {
+	t.Helper()
+
+	p := NewCodeParser(root, nil)
+	p.SetCache(LoadParseCache(filepath.Join(root, CacheFileName), nil))
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Parse() = %v", err)
+	}
+	if err := p.SaveCache(); err != nil {
+		t.Fatalf("SaveCache() = %v", err)
+	}
+	return p
+}
+
+// funcLine returns the file name and line of a function declaration of the
+// parsed files
+func funcLine(t *testing.T, p *CodeParser, name string) (string, int) {
+	t.Helper()
+
+	for _, file := range p.GetAllFiles() {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Name == name {
+				position := p.GetFilePosition(funcDecl.Pos())
+				return filepath.Base(position.Filename), position.Line
+			}
+		}
+	}
+	t.Fatalf("no function %s", name)
+	return "", 0
+}
+
+func TestParseCache(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.18\n",
+		"main.go":         "package main\n\nfunc main() {}\n",
+		"api/users.go":    "package api\n\n\nfunc GetUser() {}\n",
+		"api/products.go": "package api\n\nfunc GetProduct() {}\n",
+	})
+
+	if p := parseWithCache(t, root); p.cachedCount != 0 {
+		t.Errorf("first run loaded %d files from the cache, want 0", p.cachedCount)
+	}
+
+	// Unchanged files are loaded from the cache, with valid positions
+	p := parseWithCache(t, root)
+	if p.cachedCount != 3 {
+		t.Errorf("second run loaded %d files from the cache, want 3", p.cachedCount)
+	}
+	if file, line := funcLine(t, p, "GetUser"); file != "users.go" || line != 4 {
+		t.Errorf("cached GetUser is at %s:%d, want users.go:4", file, line)
+	}
+
+	// Only a changed file is parsed again
+	writeFiles(t, root, map[string]string{
+		"api/products.go": "package api\n\n// GetProduct moved down\n\nfunc GetProduct() {}\n",
+	})
+	p = parseWithCache(t, root)
+	if p.cachedCount != 2 {
+		t.Errorf("run after a change loaded %d files from the cache, want 2", p.cachedCount)
+	}
+	if file, line := funcLine(t, p, "GetProduct"); file != "products.go" || line != 5 {
+		t.Errorf("reparsed GetProduct is at %s:%d, want products.go:5", file, line)
+	}
+	if file, line := funcLine(t, p, "GetUser"); file != "users.go" || line != 4 {
+		t.Errorf("cached GetUser is at %s:%d, want users.go:4", file, line)
+	}
+}
+
+func TestParseCacheDiscardsOtherVersions(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+	parseWithCache(t, root)
+
+	// A cache written by another version of the tool is ignored
+	path := filepath.Join(root, CacheFileName)
+	files := LoadParseCache(path, nil).Files
+	if len(files) != 1 {
+		t.Fatalf("loaded %d files from the cache, want 1", len(files))
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gob.NewEncoder(out).Encode(cacheData{Version: "0/go1.0", Files: files}); err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+
+	if cache := LoadParseCache(path, nil); len(cache.Files) != 0 {
+		t.Errorf("loaded %d files from an outdated cache, want 0", len(cache.Files))
+	}
+	if p := parseWithCache(t, root); p.cachedCount != 0 {
+		t.Errorf("loaded %d files from an outdated cache, want 0", p.cachedCount)
+	}
+}
@@ -4,39 +4,155 @@ import (
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // CodeParser is responsible for parsing Go source files into ASTs
 type CodeParser struct {
 	RootPath string
 	FileSet  *token.FileSet
+	Log      *logger.Logger
+	Cache    *ParseCache
+
+	// Packages maps import paths to the packages parsed from each directory.
+	// Without a go.mod file in RootPath, packages are keyed by package name.
 	Packages map[string]*ast.Package
-	Verbose  bool
+
+	// ModulePath is the module path declared in RootPath's go.mod file, if any
+	ModulePath string
+
+	// Progress, if set, is called with the number of files read so far
+	// after each file
+	Progress func(files int)
+
+	// ExcludeDirs holds glob patterns of directories to skip, matched
+	// against the directory name and its path relative to RootPath
+	ExcludeDirs []string
+
+	// Scope selects the packages in these directories, for ScopePackages.
+	// The whole repository is parsed regardless, since the groups routes of
+	// the selected packages are registered on may be created anywhere.
+	Scope []string
+
+	// parsedFiles records the files seen during the last Parse call
+	parsedFiles map[string]bool
+
+	// cachedCount is the number of files loaded from the cache during the
+	// last Parse call
+	cachedCount int
+
+	// errors records the files that couldn't be read or parsed during the
+	// last Parse call
+	errors []error
 }
 
 // NewCodeParser creates a new CodeParser instance
-func NewCodeParser(rootPath string, verbose bool) *CodeParser {
+func NewCodeParser(rootPath string, log *logger.Logger) *CodeParser {
 	return &CodeParser{
 		RootPath: rootPath,
 		FileSet:  token.NewFileSet(),
 		Packages: make(map[string]*ast.Package),
-		Verbose:  verbose,
+		Log:      log,
+	}
+}
+
+// SetCache enables the parse cache. The parser adopts the cache's file set so
+// positions in cached ASTs remain valid alongside newly parsed files.
+func (p *CodeParser) SetCache(cache *ParseCache) {
+	p.Cache = cache
+	p.FileSet = cache.FileSet
+}
+
+// SaveCache persists the parse cache for the files seen in the last Parse call
+func (p *CodeParser) SaveCache() error {
+	if p.Cache == nil {
+		return nil
+	}
+	return p.Cache.Save(p.parsedFiles)
+}
+
+// SetExcludeDirs sets the glob patterns of directories to skip while parsing
+func (p *CodeParser) SetExcludeDirs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid directory pattern %q: %v", pattern, err)
+		}
+	}
+	p.ExcludeDirs = patterns
+	return nil
+}
+
+// isExcludedDir checks if a directory matches one of the exclude patterns
+func (p *CodeParser) isExcludedDir(path, name string) bool {
+	relPath, err := filepath.Rel(p.RootPath, path)
+	if err != nil || relPath == "." {
+		return false
 	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, pattern := range p.ExcludeDirs {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, relPath); matched {
+			return true
+		}
+	}
+	return false
 }
 
-// Parse parses all Go files in the repository
+// Parse parses all Go files in the repository. Files that can't be read or
+// parsed are skipped and reported by Errors, so a single malformed file
+// doesn't stop the analysis.
 func (p *CodeParser) Parse() error {
-	if p.Verbose {
-		fmt.Println("Parsing Go files in repository...")
+	p.Log.Debugf("Parsing Go files in repository...")
+
+	p.parsedFiles = make(map[string]bool)
+	p.errors = nil
+	p.cachedCount = 0
+
+	// Packages are keyed by import path when the module path is known
+	p.ModulePath = ReadModulePath(p.RootPath)
+	if p.ModulePath != "" {
+		p.Log.Debugf("Module path: %s", p.ModulePath)
+	}
+
+	if err := p.parseAll(); err != nil {
+		return fmt.Errorf("error walking repository: %v", err)
+	}
+
+	if p.Log.Enabled(logger.LevelDebug) {
+		if p.Cache != nil {
+			p.Log.Debugf("Loaded %d of %d files from cache", p.cachedCount, len(p.parsedFiles))
+		}
+		p.Log.Debugf("Parsed %d packages", len(p.Packages))
+		for pkgName, pkg := range p.Packages {
+			p.Log.Debugf("  Package %s: %d files", pkgName, len(pkg.Files))
+		}
 	}
 
-	err := filepath.Walk(p.RootPath, func(path string, info os.FileInfo, err error) error {
+	return nil
+}
+
+// parseAll parses every Go file of the repository
+func (p *CodeParser) parseAll() error {
+	return filepath.Walk(p.RootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
+			// The root must be readable, anything below it is skipped
+			if path == p.RootPath {
+				return err
+			}
+			p.addError(&FileError{Path: path, Op: "error reading", Err: err})
+			return nil
 		}
 
 		// Skip directories and non-Go files
@@ -45,6 +161,12 @@ func (p *CodeParser) Parse() error {
 			if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" {
 				return filepath.SkipDir
 			}
+
+			// Skip directories excluded by the user
+			if p.isExcludedDir(path, info.Name()) {
+				p.Log.Debugf("  Excluding directory: %s", path)
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -58,54 +180,215 @@ func (p *CodeParser) Parse() error {
 			return nil
 		}
 
-		if p.Verbose {
-			fmt.Printf("  Parsing file: %s\n", path)
+		p.parseFile(path)
+		return nil
+	})
+}
+
+// ScopePackages returns the import paths of the parsed packages in the Scope
+// directories and of the packages of the module they import, directly or
+// not, whose types documenting their routes need. It returns nil if Scope is
+// empty or the module path is unknown, since imports can't be mapped to
+// packages without it, so every package is needed.
+func (p *CodeParser) ScopePackages() map[string]bool {
+	if len(p.Scope) == 0 || p.ModulePath == "" {
+		return nil
+	}
+
+	queue := []string{}
+	for _, dir := range p.Scope {
+		queue = append(queue, p.importPath(dir, ""))
+	}
+	selected := make(map[string]bool)
+	for len(queue) > 0 {
+		pkgPath := queue[0]
+		queue = queue[1:]
+		pkg, exists := p.Packages[pkgPath]
+		if !exists || selected[pkgPath] {
+			continue
+		}
+		selected[pkgPath] = true
+
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				if importPath, err := strconv.Unquote(imp.Path.Value); err == nil && !selected[importPath] {
+					queue = append(queue, importPath)
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// parseFile parses a Go file, or loads it from the cache, and adds it to
+// the package of its directory. It returns nil if the file can't be read
+// or parsed.
+func (p *CodeParser) parseFile(path string) *ast.File {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		p.addError(&FileError{Path: path, Op: "error reading file", Err: err})
+		return nil
+	}
+	p.parsedFiles[path] = true
+
+	// Reuse the cached AST if the file content is unchanged
+	var file *ast.File
+	hash := hashContent(content)
+	if p.Cache != nil {
+		if cached := p.Cache.Lookup(path, hash); cached != nil {
+			file = cached.File
+			p.cachedCount++
+			p.Log.Debugf("  Using cached file: %s", path)
 		}
+	}
 
-		// Parse the file
-		file, err := parser.ParseFile(p.FileSet, path, nil, parser.ParseComments)
+	if file == nil {
+		p.Log.Debugf("  Parsing file: %s", path)
+
+		// Parse the file. Object resolution is skipped because the
+		// analyzers don't use it and its cyclic data can't be cached.
+		file, err = parser.ParseFile(p.FileSet, path, content, parser.ParseComments|parser.SkipObjectResolution)
 		if err != nil {
-			return fmt.Errorf("error parsing file %s: %v", path, err)
+			p.addError(&FileError{Path: path, Op: "error parsing file", Line: errorLine(err), Err: err})
+			return nil
 		}
 
-		// Get the package name
-		pkgName := file.Name.Name
-		pkg, exists := p.Packages[pkgName]
-		if !exists {
-			pkg = &ast.Package{
-				Name:  pkgName,
-				Files: make(map[string]*ast.File),
-			}
-			p.Packages[pkgName] = pkg
+		if p.Cache != nil {
+			p.Cache.Store(path, hash, file.Name.Name, file)
 		}
+	}
 
-		// Add the file to the package
-		pkg.Files[path] = file
+	// Get the package of the file's directory
+	pkgName := file.Name.Name
+	pkgPath := p.importPath(filepath.Dir(path), pkgName)
+	pkg, exists := p.Packages[pkgPath]
+	if !exists {
+		pkg = &ast.Package{
+			Name:  pkgName,
+			Files: make(map[string]*ast.File),
+		}
+		p.Packages[pkgPath] = pkg
+	}
 
-		return nil
-	})
+	// Add the file to the package
+	pkg.Files[path] = file
+
+	if p.Progress != nil {
+		p.Progress(len(p.parsedFiles))
+	}
 
+	return file
+}
+
+// importPath returns the import path of the package in a directory: the
+// module path joined with the directory relative to the module root, or the
+// package name if the module path is unknown
+func (p *CodeParser) importPath(dir, pkgName string) string {
+	if p.ModulePath == "" {
+		return pkgName
+	}
+	relPath, err := filepath.Rel(p.RootPath, dir)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return pkgName
+	}
+	return path.Join(p.ModulePath, filepath.ToSlash(relPath))
+}
+
+// ReadModulePath returns the module path declared in the go.mod file of a
+// directory, or an empty string if there is no go.mod file or it doesn't
+// declare one
+func ReadModulePath(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
 	if err != nil {
-		return fmt.Errorf("error walking repository: %v", err)
+		return ""
 	}
 
-	if p.Verbose {
-		fmt.Printf("Parsed %d packages\n", len(p.Packages))
-		for pkgName, pkg := range p.Packages {
-			fmt.Printf("  Package %s: %d files\n", pkgName, len(pkg.Files))
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
 		}
+		// The module path may be quoted
+		if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+			return unquoted
+		}
+		return fields[1]
 	}
+	return ""
+}
 
-	return nil
+// FileError is the error of a file that couldn't be read or parsed
+type FileError struct {
+	Path string // Path of the file
+	Line int    // Line of the first syntax error, 0 if unknown
+	Op   string // What failed, e.g. "error parsing file"
+	Err  error
+}
+
+// Error formats the error with the path of the file
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// errorLine returns the line of the first error reported by go/parser, or 0
+func errorLine(err error) int {
+	if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+		return list[0].Pos.Line
+	}
+	return 0
+}
+
+// addError records a file that couldn't be read or parsed
+func (p *CodeParser) addError(err error) {
+	p.Log.Debugf("  Skipping file: %v", err)
+	p.errors = append(p.errors, err)
 }
 
-// GetAllFiles returns all parsed files across all packages
+// Errors returns the errors of the files skipped during the last Parse call
+func (p *CodeParser) Errors() []error {
+	return p.errors
+}
+
+// GetAllFiles returns all parsed files across all packages, ordered by
+// package path and file name so the analysis doesn't depend on map
+// iteration order
 func (p *CodeParser) GetAllFiles() []*ast.File {
 	var files []*ast.File
-	for _, pkg := range p.Packages {
-		for _, file := range pkg.Files {
-			files = append(files, file)
-		}
+	for _, pkgPath := range p.PackagePaths() {
+		files = append(files, PackageFiles(p.Packages[pkgPath])...)
+	}
+	return files
+}
+
+// PackagePaths returns the paths of the parsed packages in sorted order
+func (p *CodeParser) PackagePaths() []string {
+	pkgPaths := make([]string, 0, len(p.Packages))
+	for pkgPath := range p.Packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+	return pkgPaths
+}
+
+// PackageFiles returns the files of a parsed package, ordered by file name
+func PackageFiles(pkg *ast.Package) []*ast.File {
+	fileNames := make([]string, 0, len(pkg.Files))
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		files = append(files, pkg.Files[fileName])
 	}
 	return files
 }
@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicateRoute reports a method and path registered more than once
+type DuplicateRoute struct {
+	Route    RouteInfo // The later registration
+	Previous RouteInfo // The first registration of the method and path
+}
+
+// String formats the duplicate with the positions of both registrations
+func (d DuplicateRoute) String() string {
+	return fmt.Sprintf("%s: %s %s is already registered at %s",
+		d.Route.Position, d.Route.Method, d.Route.Path, d.Previous.Position)
+}
+
+// Validate returns the routes registering a method and path that an earlier
+// route already registered. Paths differing only in the names of their
+// parameters, like /users/:id and /users/:name, match the same requests, so
+// they are reported too.
+func (s *RouteScanner) Validate() []DuplicateRoute {
+	duplicates := []DuplicateRoute{}
+	registered := make(map[string]RouteInfo)
+	for _, route := range s.Routes {
+		key := route.Method + " " + routePattern(route.Path)
+		if previous, exists := registered[key]; exists {
+			duplicates = append(duplicates, DuplicateRoute{Route: route, Previous: previous})
+			s.Log.Debugf("Duplicate route: %s", DuplicateRoute{Route: route, Previous: previous})
+			continue
+		}
+		registered[key] = route
+	}
+	return duplicates
+}
+
+// routePattern returns a route path with its parameter names removed
+func routePattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = ":"
+		}
+	}
+	return strings.Join(segments, "/")
+}
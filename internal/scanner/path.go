@@ -0,0 +1,47 @@
+package scanner
+
+// PathPart is a piece of an Echo route path: static text, a :name
+// parameter or the * wildcard
+type PathPart struct {
+	Text     string // Static text, or the name of a parameter without the colon
+	Param    bool   // Whether the part is a :name parameter
+	Wildcard bool   // Whether the part is the * wildcard
+}
+
+// SplitPath splits an Echo route path into static text, parameters and
+// wildcards. As in Echo's router, a parameter starts with ':' anywhere in a
+// segment, as in /files/:name.json or /v:version, and runs to the next '/',
+// and a '*' matches the rest of the path, as in /files/* or /assets*, so
+// anything after it, as in /files/*name, is dropped.
+func SplitPath(path string) []PathPart {
+	parts := []PathPart{}
+	start := 0
+	addStatic := func(end int) {
+		if end > start {
+			parts = append(parts, PathPart{Text: path[start:end]})
+		}
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case ':':
+			end := i + 1
+			for end < len(path) && path[end] != '/' {
+				end++
+			}
+			if end == i+1 {
+				// A lone colon is static text
+				continue
+			}
+			addStatic(i)
+			parts = append(parts, PathPart{Text: path[i+1 : end], Param: true})
+			start = end
+			i = end - 1
+		case '*':
+			// Echo's router ignores whatever follows the wildcard
+			addStatic(i)
+			return append(parts, PathPart{Text: "*", Wildcard: true})
+		}
+	}
+	addStatic(len(path))
+	return parts
+}
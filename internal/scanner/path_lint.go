@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
+)
+
+// Path parameter naming conventions with a predefined pattern
+var pathParamConventions = map[string]string{
+	"camelCase":  `^[a-z][a-zA-Z0-9]*$`,
+	"snake_case": `^[a-z][a-z0-9]*(_[a-z0-9]+)*$`,
+	"kebab-case": `^[a-z][a-z0-9]*(-[a-z0-9]+)*$`,
+	"lowercase":  `^[a-z][a-z0-9]*$`,
+}
+
+// PathParamDiagnostic reports a path parameter that doesn't follow the naming convention
+type PathParamDiagnostic struct {
+	Route RouteInfo
+	Param string
+}
+
+// String formats the diagnostic with the position of the route definition
+func (d PathParamDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s %s: path parameter %q doesn't follow the naming convention",
+		d.Route.Position, d.Route.Method, d.Route.Path, d.Param)
+}
+
+// PathParamLinter checks that path parameter names follow a naming convention
+type PathParamLinter struct {
+	Convention string
+	Pattern    *regexp.Regexp
+	Log        *logger.Logger
+}
+
+// NewPathParamLinter creates a new PathParamLinter. The convention is either
+// one of camelCase, snake_case, kebab-case and lowercase, or a regular
+// expression parameter names must match (e.g. ^id$)
+func NewPathParamLinter(convention string, log *logger.Logger) (*PathParamLinter, error) {
+	expr, exists := pathParamConventions[convention]
+	if !exists {
+		expr = convention
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path parameter convention %q: %v", convention, err)
+	}
+
+	return &PathParamLinter{
+		Convention: convention,
+		Pattern:    pattern,
+		Log:        log,
+	}, nil
+}
+
+// Lint returns a diagnostic for every path parameter that doesn't match the convention
+func (l *PathParamLinter) Lint(routes []RouteInfo) []PathParamDiagnostic {
+	diagnostics := []PathParamDiagnostic{}
+	for _, route := range routes {
+		for _, param := range route.PathParams() {
+			if l.Pattern.MatchString(param) {
+				continue
+			}
+			diagnostics = append(diagnostics, PathParamDiagnostic{
+				Route: route,
+				Param: param,
+			})
+		}
+
+		l.Log.Debugf("  Checked path parameters of %s %s", route.Method, route.Path)
+	}
+	return diagnostics
+}
+
+// PathParams returns the names of the path parameters of a route, in
+// order, without the * wildcard
+func (r RouteInfo) PathParams() []string {
+	params := []string{}
+	for _, part := range SplitPath(r.Path) {
+		if part.Param {
+			params = append(params, part.Text)
+		}
+	}
+	return params
+}
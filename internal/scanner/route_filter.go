@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
+)
+
+// RouteFilter selects routes by matching their paths against glob patterns
+type RouteFilter struct {
+	Include []string // Only routes matching one of these patterns are kept, if any are given
+	Exclude []string // Routes matching one of these patterns are dropped
+	Log     *logger.Logger
+}
+
+// NewRouteFilter creates a new RouteFilter, validating the glob patterns
+func NewRouteFilter(include, exclude []string, log *logger.Logger) (*RouteFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %v", pattern, err)
+		}
+	}
+
+	return &RouteFilter{
+		Include: include,
+		Exclude: exclude,
+		Log:     log,
+	}, nil
+}
+
+// Filter returns the routes selected by the filter
+func (f *RouteFilter) Filter(routes []RouteInfo) []RouteInfo {
+	filtered := []RouteInfo{}
+	for _, route := range routes {
+		if len(f.Include) > 0 && !matchAnyRoutePath(f.Include, route.Path) {
+			f.Log.Debugf("  Excluding route %s %s: not matched by --include-path", route.Method, route.Path)
+			continue
+		}
+		if matchAnyRoutePath(f.Exclude, route.Path) {
+			f.Log.Debugf("  Excluding route %s %s: matched by --exclude-path", route.Method, route.Path)
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+	return filtered
+}
+
+// matchAnyRoutePath checks if a route path matches any of the patterns
+func matchAnyRoutePath(patterns []string, routePath string) bool {
+	for _, pattern := range patterns {
+		if matchRoutePath(pattern, routePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoutePath checks if a route path or one of its parent paths matches a
+// glob pattern, so /api/v1/* also matches /api/v1/users/:id
+func matchRoutePath(pattern, routePath string) bool {
+	for p := routePath; p != ""; {
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+
+		i := strings.LastIndex(p, "/")
+		if i <= 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return false
+}
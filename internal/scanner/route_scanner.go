@@ -4,145 +4,912 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"strconv"
 	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // RouteInfo represents information about an Echo route
 type RouteInfo struct {
-	Method      string      // HTTP method (GET, POST, etc.)
-	Path        string      // Route path
-	HandlerName string      // Name of the handler function
-	HandlerNode ast.Node    // AST node of the handler function
+	Method      string         // HTTP method (GET, POST, etc.)
+	Path        string         // Route path
+	HandlerName string         // Name of the handler function
+	HandlerNode ast.Node       // AST node of the handler function
 	Position    token.Position // Position in source code
+	Middleware  []string       // Group and route-level middleware, in order
+	Protocol    string         // Protocol spoken by the handler (http, websocket or sse)
+	NotFound    bool           // Whether the route is a catch-all registered with RouteNotFound
+	Name        string         // Route name set with e.GET(...).Name = "name", if any
+	Service     string         // Service the route belongs to, when several repositories are merged
+	Source      string         // Root of the repository the route was found in, when several are merged
+	StaticDir   string         // Directory, or file system expression, served by Static or StaticFS
+	Deprecated  bool           // Whether the registration is marked with a // @deprecated comment
+
+	// Dynamic is set for routes registered through a registrar whose method
+	// or path isn't a constant. The method is then ANY, and the path is the
+	// source of the expression in angle brackets, e.g. /<prefix + "/x">.
+	Dynamic bool
+}
+
+// ErrorHandlerInfo represents a function assigned to an Echo instance's
+// HTTPErrorHandler, e.g. e.HTTPErrorHandler = customHTTPErrorHandler
+type ErrorHandlerInfo struct {
+	HandlerName string         // Name of the handler function, or "anonymous"
+	HandlerNode ast.Expr       // AST node of the handler function
+	Position    token.Position // Position of the assignment
+}
+
+// Protocols spoken by route handlers
+const (
+	ProtocolHTTP      = "http"
+	ProtocolWebSocket = "websocket"
+	ProtocolSSE       = "sse"
+)
+
+// RegistrarMethod describes a custom route registration method, such as
+// registrar.Handle("GET", "/x", h), or helper function, such as
+// register(e, "GET", "/x", h, mws...), by the positions of its arguments
+type RegistrarMethod struct {
+	Name       string // Method name to match on any receiver, or function name
+	MethodArg  int    // Position of the HTTP method argument
+	PathArg    int    // Position of the route path argument
+	HandlerArg int    // Position of the handler argument
+}
+
+// ParseRegistrarMethod parses a registrar specification of the form
+// Name:methodArg:pathArg:handlerArg (e.g. Handle:0:1:2)
+func ParseRegistrarMethod(spec string) (RegistrarMethod, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 4 || parts[0] == "" {
+		return RegistrarMethod{}, fmt.Errorf("invalid registrar method %q, expected Name:methodArg:pathArg:handlerArg", spec)
+	}
+
+	positions := make([]int, 3)
+	for i, part := range parts[1:] {
+		pos, err := strconv.Atoi(part)
+		if err != nil || pos < 0 {
+			return RegistrarMethod{}, fmt.Errorf("invalid argument position %q in registrar method %q", part, spec)
+		}
+		positions[i] = pos
+	}
+
+	return RegistrarMethod{
+		Name:       parts[0],
+		MethodArg:  positions[0],
+		PathArg:    positions[1],
+		HandlerArg: positions[2],
+	}, nil
+}
+
+// routerGroup is the state of an Echo group variable: the prefix of the
+// routes registered on it and the middleware applying to them, in order
+type routerGroup struct {
+	prefix     string
+	middleware []string
+	dynamic    bool // Whether the prefix isn't a constant
+}
+
+// routerFunc is a function or method taking or returning an Echo instance or
+// group, such as registerAdminRoutes(g *echo.Group), or a function literal
+// assigned to a variable, such as register := func(g *echo.Group) { ... }.
+// Its routes are scanned at each call, with the groups passed to it.
+type routerFunc struct {
+	decl    *ast.FuncDecl // Declaration, standing in for the literal if lit is set
+	lit     *ast.FuncLit
+	pkg     string // Package of the file declaring the function
+	echoPkg string // Name the Echo package is imported as in that file
+	called  bool   // Whether a call to the function was scanned
+	active  bool   // Whether the function is being scanned, to stop recursion
+}
+
+// routerCall is the router returned by a call to a router function, if any
+type routerCall struct {
+	group    routerGroup
+	isRouter bool
 }
 
 // RouteScanner scans AST for Echo route definitions
 type RouteScanner struct {
-	FileSet     *token.FileSet
-	Routes      []RouteInfo
-	Verbose     bool
-	echoVarNames map[string]bool // Tracks variables that might be Echo instances
+	FileSet          *token.FileSet
+	Routes           []RouteInfo
+	Log              *logger.Logger
+	Middleware       []string                   // Global middleware registered with Use or Pre
+	ErrorHandler     *ErrorHandlerInfo          // HTTP error handler, if one is assigned
+	echoVarNames     map[string]bool            // Tracks variables that might be Echo instances
+	groups           map[string]routerGroup     // Prefix and middleware of Echo group variables, by name
+	registrarMethods map[string]RegistrarMethod // Custom registration methods by name
+
+	// ServeMux scans for routes registered on net/http ServeMux instances,
+	// as in mux.HandleFunc("GET /users/{id}", getUser), instead of Echo routes
+	ServeMux bool
+
+	// constants maps package names to the string constants declared at
+	// their top level, so routes can reference paths and methods by name
+	constants      map[string]map[string]string
+	currentPackage string // Package of the file being scanned
+
+	// routeNames maps route registration calls to the name assigned to
+	// the route they return
+	routeNames map[*ast.CallExpr]string
+
+	// deprecatedLines maps file names to the lines of route registrations
+	// marked deprecated by a comment
+	deprecatedLines map[string]map[int]bool
+
+	// funcs maps the names of router functions to their declarations,
+	// "."+name for methods, or to nil if several share the name
+	funcs     map[string]*routerFunc
+	funcOrder []*routerFunc
+
+	// calls caches the routers returned by the router function calls of the
+	// body being scanned, so each call is scanned once
+	calls map[*ast.CallExpr]routerCall
+
+	// returned is the router returned by the router function being
+	// scanned, once its first return statement returning one is found
+	returned *routerCall
 }
 
 // NewRouteScanner creates a new RouteScanner
-func NewRouteScanner(fset *token.FileSet, verbose bool) *RouteScanner {
+func NewRouteScanner(fset *token.FileSet, log *logger.Logger) *RouteScanner {
 	return &RouteScanner{
-		FileSet:     fset,
-		Routes:      []RouteInfo{},
-		Verbose:     verbose,
-		echoVarNames: map[string]bool{
-			"e":      true,
-			"echo":   true,
-			"router": true,
-			"app":    true,
-			"server": true,
-		},
+		FileSet:          fset,
+		Routes:           []RouteInfo{},
+		Log:              log,
+		Middleware:       []string{},
+		echoVarNames:     make(map[string]bool),
+		groups:           make(map[string]routerGroup),
+		registrarMethods: make(map[string]RegistrarMethod),
+		constants:        make(map[string]map[string]string),
+		routeNames:       make(map[*ast.CallExpr]string),
+		deprecatedLines:  make(map[string]map[int]bool),
+		funcs:            make(map[string]*routerFunc),
+		calls:            make(map[*ast.CallExpr]routerCall),
 	}
 }
 
+// AddRegistrarMethod registers a custom route registration method, matched
+// on any receiver and as a plain function call, in addition to Echo's own
+// routing methods
+func (s *RouteScanner) AddRegistrarMethod(method RegistrarMethod) {
+	s.registrarMethods[method.Name] = method
+}
+
 // Scan scans all files for Echo route definitions
 func (s *RouteScanner) Scan(files []*ast.File) error {
-	if s.Verbose {
-		fmt.Println("Scanning for Echo route definitions...")
+	if s.ServeMux {
+		s.Log.Debugf("Scanning for ServeMux route definitions...")
+	} else {
+		s.Log.Debugf("Scanning for Echo route definitions...")
 	}
 
+	// Collect string constants first, since routes can reference constants
+	// declared in other files
+	s.collectConstants(files)
+
+	// First pass: identify Echo instance variables and the functions
+	// routers are passed to. Calls to router functions are only followed
+	// once every variable is known.
+	funcs := s.collectRouterFuncs(files)
 	for _, file := range files {
-		// First pass: identify Echo instance variables
-		s.identifyEchoInstances(file)
-		
-		// Second pass: find route definitions
-		s.findRouteDefinitions(file)
+		s.currentPackage = file.Name.Name
+		s.identifyEchoInstances(file, funcs)
+		s.collectRouteNames(file)
+		s.collectDeprecatedLines(file)
+	}
+	s.funcs = funcs
+
+	// Second pass: find route definitions. Router functions are scanned
+	// where they are called, and the ones never called on their own.
+	if s.ServeMux {
+		s.scanServeMux(files)
+	} else {
+		for _, file := range files {
+			s.currentPackage = file.Name.Name
+			s.findRouteDefinitions(file)
+		}
+		for _, fn := range s.funcOrder {
+			if !fn.called && s.funcs[funcKey(fn.decl)] == fn {
+				s.scanRouterFunc(fn, nil)
+			}
+		}
 	}
 
-	if s.Verbose {
-		fmt.Printf("Found %d routes\n", len(s.Routes))
+	// Routes are marked deprecated by the position of their registration
+	for i, route := range s.Routes {
+		if s.deprecatedLines[route.Position.Filename][route.Position.Line] {
+			s.Routes[i].Deprecated = true
+		}
 	}
 
+	s.Log.Debugf("Found %d routes", len(s.Routes))
+
 	return nil
 }
 
-// identifyEchoInstances finds variables that might be Echo instances
-func (s *RouteScanner) identifyEchoInstances(file *ast.File) {
+// identifyEchoInstances finds variables and parameters that hold Echo
+// instances or groups. Assignments are revisited until no new router is
+// found, so groups created from routers declared later in the file are
+// tracked too.
+func (s *RouteScanner) identifyEchoInstances(file *ast.File, funcs map[string]*routerFunc) {
+	echoPkg := echoPackageName(file)
+	if echoPkg == "" {
+		return
+	}
+
+	// Functions returning a router, such as func newRouter() *echo.Echo,
+	// mapped to whether they return a group
+	routerFuncs := make(map[string]bool)
+	for name, fn := range funcs {
+		if fn == nil || fn.decl.Recv != nil {
+			continue
+		}
+		if results := fn.decl.Type.Results; results != nil && isRouterType(results.List[0].Type, fn.echoPkg) {
+			routerFuncs[name] = isGroupType(results.List[0].Type)
+		}
+	}
+
 	ast.Inspect(file, func(n ast.Node) bool {
-		// Look for variable assignments
-		if assign, ok := n.(*ast.AssignStmt); ok {
-			for i, rhs := range assign.Rhs {
-				// Check if right side is a call to echo.New() or similar
-				if call, ok := rhs.(*ast.CallExpr); ok {
-					if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-						if ident, ok := sel.X.(*ast.Ident); ok {
-							if ident.Name == "echo" && sel.Sel.Name == "New" {
-								// This is a call to echo.New()
-								if i < len(assign.Lhs) {
-									if lhsIdent, ok := assign.Lhs[i].(*ast.Ident); ok {
-										if s.Verbose {
-											fmt.Printf("  Found Echo instance: %s\n", lhsIdent.Name)
-										}
-										s.echoVarNames[lhsIdent.Name] = true
-									}
-								}
-							}
-						}
+		var funcType *ast.FuncType
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			funcType = fn.Type
+		case *ast.FuncLit:
+			funcType = fn.Type
+		case *ast.ValueSpec:
+			// var g *echo.Group
+			if fn.Type != nil && isRouterType(fn.Type, echoPkg) {
+				for _, name := range fn.Names {
+					s.addEchoInstance(name.Name)
+					if isGroupType(fn.Type) {
+						s.groups[name.Name] = routerGroup{}
+					}
+				}
+			}
+			return true
+		default:
+			return true
+		}
+
+		// Parameters of type *echo.Echo or *echo.Group, as in registerRoutes(e *echo.Echo)
+		for _, param := range funcType.Params.List {
+			if isRouterType(param.Type, echoPkg) {
+				for _, name := range param.Names {
+					s.addEchoInstance(name.Name)
+					if isGroupType(param.Type) {
+						s.groups[name.Name] = routerGroup{}
 					}
 				}
 			}
 		}
 		return true
 	})
+
+	for found := true; found; {
+		found = false
+		ast.Inspect(file, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			for _, target := range routerAssignments(assign) {
+				if !s.isRouterCall(target.value, echoPkg, routerFuncs) || s.echoVarNames[target.name] {
+					continue
+				}
+				s.addEchoInstance(target.name)
+				if group, isRouter := s.receiverGroup(target.value); isRouter {
+					s.groups[target.name] = group
+				} else if call, ok := target.value.(*ast.CallExpr); ok {
+					if fun, ok := call.Fun.(*ast.Ident); ok && routerFuncs[fun.Name] {
+						s.groups[target.name] = routerGroup{}
+					}
+				}
+				found = true
+			}
+			return true
+		})
+	}
+}
+
+// addEchoInstance records a variable name as an Echo instance or group
+func (s *RouteScanner) addEchoInstance(name string) {
+	if name == "_" || s.echoVarNames[name] {
+		return
+	}
+	s.Log.Debugf("  Found Echo instance: %s", name)
+	s.echoVarNames[name] = true
+}
+
+// isRouterCall checks if an expression creates or returns a router: echo.New(),
+// a call to a function returning a router, or Group() on a known router
+func (s *RouteScanner) isRouterCall(expr ast.Expr, echoPkg string, routerFuncs map[string]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		_, exists := routerFuncs[fun.Name]
+		return exists
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			if ident.Name == echoPkg && fun.Sel.Name == "New" {
+				return true
+			}
+			return fun.Sel.Name == "Group" && s.echoVarNames[ident.Name]
+		}
+		// Groups of routers held in struct fields: s.api.Group("/v1")
+		return fun.Sel.Name == "Group" && s.echoVarNames[routerVarName(fun.X)]
+	}
+	return false
+}
+
+// routerTarget is a variable or struct field assigned a value, named as in
+// the source, e.g. api or s.api
+type routerTarget struct {
+	name  string
+	value ast.Expr
+}
+
+// routerAssignments returns the variables and struct fields an assignment
+// sets: its left-hand sides, and the fields of struct literals assigned to a
+// variable, so s := &server{api: e.Group("/api")} sets s.api
+func routerAssignments(assign *ast.AssignStmt) []routerTarget {
+	var targets []routerTarget
+	for i, rhs := range assign.Rhs {
+		if i >= len(assign.Lhs) {
+			break
+		}
+		name := routerVarName(assign.Lhs[i])
+		if name == "" {
+			continue
+		}
+		targets = append(targets, routerTarget{name: name, value: rhs})
+
+		if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			rhs = unary.X
+		}
+		lit, ok := rhs.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					targets = append(targets, routerTarget{name: name + "." + key.Name, value: kv.Value})
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// routerVarName returns the name a router held in a variable or struct
+// field is tracked under, e.g. api or s.api, or "" for other expressions
+func routerVarName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		if name := routerVarName(x.X); name != "" {
+			return name + "." + x.Sel.Name
+		}
+	}
+	return ""
+}
+
+// echoPackageName returns the name the Echo package is imported as in a file,
+// or an empty string if the file doesn't import it
+func echoPackageName(file *ast.File) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || !strings.HasPrefix(path, "github.com/labstack/echo") {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "echo"
+	}
+	return ""
+}
+
+// isRouterType checks if a type expression is *echo.Echo or *echo.Group
+func isRouterType(expr ast.Expr, echoPkg string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == echoPkg && (sel.Sel.Name == "Echo" || sel.Sel.Name == "Group")
+}
+
+// isGroupType checks if a router type expression is *echo.Group
+func isGroupType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Group"
+}
+
+// receiverGroup returns the group routes registered on a router expression
+// belong to: a known variable or struct field, a chained Group() call such as
+// e.Group("/api", auth), or a call to a function returning a router. The
+// second result reports whether the expression is a known router; the group
+// of an Echo instance has no prefix and no middleware.
+func (s *RouteScanner) receiverGroup(expr ast.Expr) (routerGroup, bool) {
+	switch x := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		// A variable, or a struct field such as s.api
+		name := routerVarName(x)
+		if name == "" || !s.echoVarNames[name] {
+			return routerGroup{}, false
+		}
+		return s.groups[name], true
+	case *ast.CallExpr:
+		if fn := s.calledRouterFunc(x); fn != nil {
+			result := s.callRouterFunc(fn, x)
+			return result.group, result.isRouter
+		}
+		sel, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Group" {
+			return routerGroup{}, false
+		}
+		parent, ok := s.receiverGroup(sel.X)
+		if !ok {
+			return routerGroup{}, false
+		}
+
+		// Groups inherit the prefix and middleware of their parent
+		group := routerGroup{
+			prefix:     parent.prefix,
+			middleware: append([]string{}, parent.middleware...),
+			dynamic:    parent.dynamic,
+		}
+		if len(x.Args) > 0 {
+			prefix, ok := s.routePath(x.Args[0])
+			if !ok {
+				prefix = "/<" + types.ExprString(x.Args[0]) + ">"
+				group.dynamic = true
+			}
+			group.prefix += prefix
+		}
+		if len(x.Args) > 1 {
+			group.middleware = append(group.middleware, s.middlewareNames(x.Args[1:])...)
+		}
+		return group, true
+	}
+	return routerGroup{}, false
+}
+
+// fullPath returns the path of a route registered on the group. As in Echo,
+// a route registered with an empty path on an Echo instance matches "/".
+func (g routerGroup) fullPath(path string) string {
+	if g.prefix+path == "" {
+		return "/"
+	}
+	return g.prefix + path
+}
+
+// routePath returns the path of a route or group prefix argument, reporting
+// whether it is a constant. The empty string is a valid group route path.
+func (s *RouteScanner) routePath(expr ast.Expr) (string, bool) {
+	if path := s.extractStringLiteral(expr); path != "" {
+		return path, true
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	return "", ok && lit.Kind == token.STRING
+}
+
+// middlewareNames returns the names of middleware expressions, such as
+// authMiddleware or middleware.Logger() (reported as middleware.Logger)
+func (s *RouteScanner) middlewareNames(exprs []ast.Expr) []string {
+	names := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		if call, ok := expr.(*ast.CallExpr); ok {
+			expr = call.Fun
+		}
+		names = append(names, s.extractHandlerInfo(expr))
+	}
+	return names
+}
+
+// addMiddleware records middleware registered with Use or Pre on a router.
+// Group middleware applies to the routes registered on the group afterwards;
+// middleware registered on an Echo instance applies to every route.
+func (s *RouteScanner) addMiddleware(call *ast.CallExpr, receiver ast.Expr) {
+	// Middleware added to a chained group doesn't reach any route
+	if _, ok := receiver.(*ast.CallExpr); ok {
+		return
+	}
+
+	names := s.middlewareNames(call.Args)
+	if ident, ok := receiver.(*ast.Ident); ok {
+		if group, isGroup := s.groups[ident.Name]; isGroup {
+			group.middleware = append(append([]string{}, group.middleware...), names...)
+			s.groups[ident.Name] = group
+			s.Log.Debugf("  Found group middleware on %s: %s", ident.Name, strings.Join(names, ", "))
+			return
+		}
+	}
+
+	s.Middleware = append(s.Middleware, names...)
+	s.Log.Debugf("  Found global middleware: %s", strings.Join(names, ", "))
+}
+
+// updateGroups recomputes the prefix and middleware of groups created in an
+// assignment, such as g := e.Group("/api", auth) or g := newAPIGroup(e)
+func (s *RouteScanner) updateGroups(assign *ast.AssignStmt) {
+	for _, target := range routerAssignments(assign) {
+		if _, isCall := target.value.(*ast.CallExpr); !isCall {
+			continue
+		}
+		group, isRouter := s.receiverGroup(target.value)
+		if _, isGroup := s.groups[target.name]; isRouter && isGroup {
+			s.groups[target.name] = group
+		}
+	}
+}
+
+// collectRouterFuncs finds the functions and methods taking or returning an
+// Echo instance or group, by key
+func (s *RouteScanner) collectRouterFuncs(files []*ast.File) map[string]*routerFunc {
+	funcs := make(map[string]*routerFunc)
+	for _, file := range files {
+		echoPkg := echoPackageName(file)
+		if echoPkg == "" {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || !takesOrReturnsRouter(fn.Type, echoPkg) {
+				continue
+			}
+			s.addRouterFunc(funcs, &routerFunc{decl: fn, pkg: file.Name.Name, echoPkg: echoPkg})
+		}
+
+		// Function literals assigned to a variable, called by its name
+		ast.Inspect(file, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != len(assign.Rhs) {
+				return true
+			}
+			for i, rhs := range assign.Rhs {
+				name, isIdent := assign.Lhs[i].(*ast.Ident)
+				lit, isLit := rhs.(*ast.FuncLit)
+				if !isIdent || !isLit || !takesOrReturnsRouter(lit.Type, echoPkg) {
+					continue
+				}
+				decl := &ast.FuncDecl{Name: name, Type: lit.Type, Body: lit.Body}
+				s.addRouterFunc(funcs, &routerFunc{decl: decl, lit: lit, pkg: file.Name.Name, echoPkg: echoPkg})
+			}
+			return true
+		})
+	}
+	return funcs
+}
+
+// addRouterFunc adds a router function under its key, unless another one
+// already has it, since calls to functions sharing a name can't be told apart
+func (s *RouteScanner) addRouterFunc(funcs map[string]*routerFunc, fn *routerFunc) {
+	key := funcKey(fn.decl)
+	if _, exists := funcs[key]; exists {
+		funcs[key] = nil
+		return
+	}
+	funcs[key] = fn
+	s.funcOrder = append(s.funcOrder, fn)
+}
+
+// isRouterLit checks if a function literal is a router function, scanned
+// where it is called rather than where it is declared
+func (s *RouteScanner) isRouterLit(lit *ast.FuncLit) bool {
+	for _, fn := range s.funcOrder {
+		if fn.lit == lit {
+			return s.funcs[funcKey(fn.decl)] == fn
+		}
+	}
+	return false
+}
+
+// takesOrReturnsRouter checks if a function has a parameter or a first
+// result of type *echo.Echo or *echo.Group
+func takesOrReturnsRouter(funcType *ast.FuncType, echoPkg string) bool {
+	if funcType.Results != nil && len(funcType.Results.List) > 0 && isRouterType(funcType.Results.List[0].Type, echoPkg) {
+		return true
+	}
+	for _, param := range funcType.Params.List {
+		if isRouterType(param.Type, echoPkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// funcKey returns the key of a function in the router functions: its name,
+// prefixed with a dot for methods
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv != nil {
+		return "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// calledRouterFunc returns the router function a call invokes, or nil:
+// registerRoutes(e), routes.Register(e) or h.Register(api)
+func (s *RouteScanner) calledRouterFunc(call *ast.CallExpr) *routerFunc {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return s.funcs[fun.Name]
+	case *ast.SelectorExpr:
+		if fn := s.funcs["."+fun.Sel.Name]; fn != nil {
+			return fn
+		}
+		// Functions of another package, but not methods of a router
+		if x, ok := fun.X.(*ast.Ident); ok && !s.echoVarNames[x.Name] {
+			return s.funcs[fun.Sel.Name]
+		}
+	}
+	return nil
+}
+
+// callRouterFunc scans the routes of a router function called with the
+// routers passed to it, and returns the router it returns. A call is
+// scanned once, even when its result is looked up several times.
+func (s *RouteScanner) callRouterFunc(fn *routerFunc, call *ast.CallExpr) routerCall {
+	if result, exists := s.calls[call]; exists {
+		return result
+	}
+	result := s.scanRouterFunc(fn, call.Args)
+	s.calls[call] = result
+	return result
+}
+
+// scanRouterFunc scans the body of a router function whose group parameters
+// are bound to the groups passed as args, or to groups with no prefix when
+// args is nil. It returns the router the function returns, if any.
+func (s *RouteScanner) scanRouterFunc(fn *routerFunc, args []ast.Expr) routerCall {
+	// Recursive calls register no new routes
+	if fn.active {
+		return routerCall{}
+	}
+	fn.active = true
+	fn.called = fn.called || args != nil
+
+	// The function's variables are only visible while scanning its body
+	groups := make(map[string]routerGroup, len(s.groups))
+	for name, group := range s.groups {
+		groups[name] = group
+	}
+	i := 0
+	for _, param := range fn.decl.Type.Params.List {
+		if len(param.Names) == 0 {
+			i++
+			continue
+		}
+		for _, name := range param.Names {
+			if isRouterType(param.Type, fn.echoPkg) && isGroupType(param.Type) {
+				group := routerGroup{}
+				if i < len(args) {
+					group, _ = s.receiverGroup(args[i])
+				}
+				groups[name.Name] = group
+			}
+			i++
+		}
+	}
+
+	prevGroups, prevCalls, prevReturned, prevPackage := s.groups, s.calls, s.returned, s.currentPackage
+	s.groups, s.calls, s.returned, s.currentPackage = groups, make(map[*ast.CallExpr]routerCall), nil, fn.pkg
+	s.inspectRoutes(fn.decl.Body)
+	result := routerCall{}
+	if s.returned != nil {
+		result = *s.returned
+	}
+	s.groups, s.calls, s.returned, s.currentPackage = prevGroups, prevCalls, prevReturned, prevPackage
+
+	fn.active = false
+	return result
 }
 
-// findRouteDefinitions finds Echo route definitions
+// findRouteDefinitions finds Echo route definitions. Router functions are
+// skipped, since they are scanned where they are called.
 func (s *RouteScanner) findRouteDefinitions(file *ast.File) {
-	ast.Inspect(file, func(n ast.Node) bool {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if routerFn := s.funcs[funcKey(fn)]; routerFn != nil && routerFn.decl == fn {
+				continue
+			}
+		}
+		s.calls, s.returned = make(map[*ast.CallExpr]routerCall), nil
+		s.inspectRoutes(decl)
+	}
+}
+
+// inspectRoutes finds the Echo route definitions in a node
+func (s *RouteScanner) inspectRoutes(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.FuncLit); ok && s.isRouterLit(lit) {
+			return false
+		}
+
+		// Groups inherit the prefix and middleware of their parent as of
+		// their creation
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			s.updateGroups(assign)
+			s.findErrorHandler(assign)
+		}
+
+		// The first router returned by the router function being scanned
+		if ret, ok := n.(*ast.ReturnStmt); ok && s.returned == nil && len(ret.Results) > 0 {
+			if group, isRouter := s.receiverGroup(ret.Results[0]); isRouter {
+				s.returned = &routerCall{group: group, isRouter: true}
+			}
+		}
+
 		// Look for method calls
 		if expr, ok := n.(*ast.CallExpr); ok {
+			// Calls to router functions register their routes
+			if fn := s.calledRouterFunc(expr); fn != nil {
+				s.callRouterFunc(fn, expr)
+			}
+
+			// Check for custom registration helper functions
+			if ident, ok := expr.Fun.(*ast.Ident); ok {
+				if registrar, exists := s.registrarMethods[ident.Name]; exists {
+					s.addRegistrarRoute(expr, registrar)
+					return true
+				}
+			}
+
 			if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
-				if ident, ok := sel.X.(*ast.Ident); ok {
-					// Check if this is a call on an Echo instance
-					if s.echoVarNames[ident.Name] {
-						// Check if this is a route definition method
-						method := s.getHTTPMethod(sel.Sel.Name)
-						if method != "" && len(expr.Args) >= 2 {
-							// This is a route definition
-							path := s.extractStringLiteral(expr.Args[0])
-							handlerInfo := s.extractHandlerInfo(expr.Args[1])
-							
-							if path != "" {
-								route := RouteInfo{
-									Method:      method,
-									Path:        path,
-									HandlerName: handlerInfo,
-									HandlerNode: expr.Args[1],
-									Position:    s.FileSet.Position(expr.Pos()),
-								}
-								s.Routes = append(s.Routes, route)
-								
-								if s.Verbose {
-									fmt.Printf("  Found route: %s %s -> %s\n", method, path, handlerInfo)
-								}
-							}
-						}
-						
-						// Check for group definitions
-						if sel.Sel.Name == "Group" && len(expr.Args) >= 1 {
-							prefix := s.extractStringLiteral(expr.Args[0])
-							if prefix != "" {
-								// Track the group variable for subsequent route definitions
-								if assign, ok := n.(*ast.AssignStmt); ok {
-									for i, rhs := range assign.Rhs {
-										if rhs == expr && i < len(assign.Lhs) {
-											if lhsIdent, ok := assign.Lhs[i].(*ast.Ident); ok {
-												if s.Verbose {
-													fmt.Printf("  Found Echo group: %s with prefix %s\n", lhsIdent.Name, prefix)
-												}
-												s.echoVarNames[lhsIdent.Name] = true
-												// TODO: Track the prefix for this group
-											}
-										}
-									}
-								}
-							}
-						}
+				// Check for custom registrar methods on any receiver
+				if registrar, exists := s.registrarMethods[sel.Sel.Name]; exists {
+					s.addRegistrarRoute(expr, registrar)
+					return true
+				}
+
+				// Check if this is a call on an Echo instance or group, possibly
+				// a chained one such as e.Group("/api").GET(...)
+				group, isRouter := s.receiverGroup(sel.X)
+				if !isRouter {
+					return true
+				}
+
+				// Middleware registration
+				if sel.Sel.Name == "Use" || sel.Sel.Name == "Pre" {
+					s.addMiddleware(expr, sel.X)
+					return true
+				}
+
+				// Catch-all for unmatched routes: e.RouteNotFound("/*", handler)
+				if sel.Sel.Name == "RouteNotFound" {
+					s.addNotFoundRoute(expr, group)
+					return true
+				}
+
+				// Files served from a directory: e.Static("/assets", "public")
+				if (sel.Sel.Name == "Static" || sel.Sel.Name == "StaticFS") && len(expr.Args) >= 2 {
+					s.addStaticRoute(expr, sel.Sel.Name, group)
+					return true
+				}
+
+				// Route with any method: e.Add("PROPFIND", "/files", handler)
+				if sel.Sel.Name == "Add" && len(expr.Args) >= 3 {
+					method := strings.ToUpper(s.extractStringLiteral(expr.Args[0]))
+					if method != "" {
+						s.addRoute(expr, method, expr.Args[1:], group)
+					}
+					return true
+				}
+
+				// Route with several methods: e.Match([]string{"GET", "POST"}, "/x", handler)
+				if sel.Sel.Name == "Match" && len(expr.Args) >= 3 {
+					s.addMatchRoutes(expr, group)
+					return true
+				}
+
+				// Check if this is a route definition method
+				method := s.getHTTPMethod(sel.Sel.Name)
+				if method != "" && len(expr.Args) >= 2 {
+					s.addRoute(expr, method, expr.Args, group)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// addRoute records a route registered on a group with a call whose
+// arguments, from args on, are the path, the handler and route-level middleware
+func (s *RouteScanner) addRoute(call *ast.CallExpr, method string, args []ast.Expr, group routerGroup) {
+	path, ok := s.routePath(args[0])
+	if !ok {
+		s.Log.Debugf("  Skipping %s route at %s: path is not a string constant", method, s.FileSet.Position(call.Pos()))
+		return
+	}
+
+	// Route-level middleware follows the handler
+	middleware := append([]string{}, group.middleware...)
+	middleware = append(middleware, s.middlewareNames(args[2:])...)
+
+	route := RouteInfo{
+		Method:      method,
+		Path:        group.fullPath(path),
+		HandlerName: s.extractHandlerInfo(args[1]),
+		HandlerNode: args[1],
+		Position:    s.FileSet.Position(call.Pos()),
+		Middleware:  middleware,
+		Name:        s.routeNames[call],
+		Dynamic:     group.dynamic,
+	}
+	s.Routes = append(s.Routes, route)
+
+	s.Log.Debugf("  Found route: %s %s -> %s", method, route.Path, route.HandlerName)
+}
+
+// addMatchRoutes records a route per method registered with Match, whose
+// first argument is a slice literal of method names
+func (s *RouteScanner) addMatchRoutes(call *ast.CallExpr, group routerGroup) {
+	methods, ok := call.Args[0].(*ast.CompositeLit)
+	if !ok {
+		s.Log.Debugf("  Skipping Match routes at %s: methods are not a slice literal", s.FileSet.Position(call.Pos()))
+		return
+	}
+
+	for _, elt := range methods.Elts {
+		method := strings.ToUpper(s.extractStringLiteral(elt))
+		if method == "" {
+			s.Log.Debugf("  Skipping Match route at %s: method is not a string constant", s.FileSet.Position(elt.Pos()))
+			continue
+		}
+		s.addRoute(call, method, call.Args[1:], group)
+	}
+}
+
+// collectRouteNames finds the names assigned to routes in a file, either
+// directly, e.GET("/users/:id", getUser).Name = "get-user", or through a
+// variable holding the route, route := e.GET(...) then route.Name = "get-user".
+// It records each registration call's route name in routeNames.
+func (s *RouteScanner) collectRouteNames(file *ast.File) {
+	// Calls whose result was last assigned to each variable, in source order
+	routeVars := make(map[string]*ast.CallExpr)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+
+		for i, lhs := range assign.Lhs {
+			switch l := lhs.(type) {
+			case *ast.Ident:
+				if call, ok := assign.Rhs[i].(*ast.CallExpr); ok {
+					routeVars[l.Name] = call
+				} else {
+					delete(routeVars, l.Name)
+				}
+			case *ast.SelectorExpr:
+				if l.Sel.Name != "Name" {
+					continue
+				}
+				name := s.extractStringLiteral(assign.Rhs[i])
+				if name == "" {
+					continue
+				}
+				switch x := l.X.(type) {
+				case *ast.CallExpr:
+					s.routeNames[x] = name
+				case *ast.Ident:
+					if call, exists := routeVars[x.Name]; exists {
+						s.routeNames[call] = name
 					}
 				}
 			}
@@ -151,10 +918,174 @@ func (s *RouteScanner) findRouteDefinitions(file *ast.File) {
 	})
 }
 
+// deprecatedMarker marks the route registered on the line below a comment
+// holding it, or on the same line for a trailing comment
+const deprecatedMarker = "@deprecated"
+
+// collectDeprecatedLines records the lines of a file that a comment with
+// the @deprecated marker applies to: the line below the comment group, as in
+//
+//	// @deprecated
+//	e.GET("/v1/users", listUsersV1)
+//
+// and the line of the comment itself, for e.GET(...) // @deprecated
+func (s *RouteScanner) collectDeprecatedLines(file *ast.File) {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !strings.Contains(comment.Text, deprecatedMarker) {
+				continue
+			}
+			fileName := s.FileSet.Position(file.Pos()).Filename
+			if s.deprecatedLines[fileName] == nil {
+				s.deprecatedLines[fileName] = make(map[int]bool)
+			}
+			s.deprecatedLines[fileName][s.FileSet.Position(comment.Pos()).Line] = true
+			s.deprecatedLines[fileName][s.FileSet.Position(group.End()).Line+1] = true
+			break
+		}
+	}
+}
+
+// addNotFoundRoute records a route registered with RouteNotFound, which
+// matches any method on paths no other route matches
+func (s *RouteScanner) addNotFoundRoute(call *ast.CallExpr, group routerGroup) {
+	if len(call.Args) < 2 {
+		return
+	}
+	path, ok := s.routePath(call.Args[0])
+	if !ok {
+		return
+	}
+
+	middleware := append([]string{}, group.middleware...)
+	middleware = append(middleware, s.middlewareNames(call.Args[2:])...)
+	route := RouteInfo{
+		Method:      "ANY",
+		Path:        group.fullPath(path),
+		HandlerName: s.extractHandlerInfo(call.Args[1]),
+		HandlerNode: call.Args[1],
+		Position:    s.FileSet.Position(call.Pos()),
+		Middleware:  middleware,
+		NotFound:    true,
+		Dynamic:     group.dynamic,
+	}
+	s.Routes = append(s.Routes, route)
+
+	s.Log.Debugf("  Found not found route: %s -> %s", route.Path, route.HandlerName)
+}
+
+// addStaticRoute records the route registered with Static or StaticFS,
+// which serves the files under a directory or file system on GET requests
+// to the prefix, e.g. e.Static("/assets", "public") serves GET /assets/*
+func (s *RouteScanner) addStaticRoute(call *ast.CallExpr, method string, group routerGroup) {
+	prefix, ok := s.routePath(call.Args[0])
+	if !ok {
+		s.Log.Debugf("  Skipping %s route at %s: prefix is not a string constant", method, s.FileSet.Position(call.Pos()))
+		return
+	}
+
+	// As in Echo, the prefix is followed by a wildcard matching file paths
+	path := prefix + "/*"
+	if strings.HasSuffix(prefix, "/") {
+		path = prefix + "*"
+	}
+
+	dir := s.extractStringLiteral(call.Args[1])
+	if dir == "" {
+		dir = types.ExprString(call.Args[1])
+	}
+
+	route := RouteInfo{
+		Method:      "GET",
+		Path:        group.fullPath(path),
+		HandlerName: method,
+		Position:    s.FileSet.Position(call.Pos()),
+		Middleware:  append([]string{}, group.middleware...),
+		StaticDir:   dir,
+		Dynamic:     group.dynamic,
+	}
+	s.Routes = append(s.Routes, route)
+
+	s.Log.Debugf("  Found static route: %s -> %s", route.Path, dir)
+}
+
+// findErrorHandler records the function assigned to the HTTPErrorHandler of
+// an Echo instance. If it is assigned more than once, the last assignment wins.
+func (s *RouteScanner) findErrorHandler(assign *ast.AssignStmt) {
+	for i, lhs := range assign.Lhs {
+		sel, ok := lhs.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "HTTPErrorHandler" || i >= len(assign.Rhs) {
+			continue
+		}
+		if _, isRouter := s.receiverGroup(sel.X); !isRouter {
+			continue
+		}
+
+		s.ErrorHandler = &ErrorHandlerInfo{
+			HandlerName: s.extractHandlerInfo(assign.Rhs[i]),
+			HandlerNode: assign.Rhs[i],
+			Position:    s.FileSet.Position(assign.Pos()),
+		}
+		s.Log.Debugf("  Found HTTP error handler: %s", s.ErrorHandler.HandlerName)
+	}
+}
+
+// addRegistrarRoute records a route registered through a custom registrar
+// method or helper function. Middleware passed after the handler is
+// recorded, flattening slice literals spread with ..., and a method or path
+// that isn't a constant makes the route dynamic.
+func (s *RouteScanner) addRegistrarRoute(call *ast.CallExpr, registrar RegistrarMethod) {
+	if registrar.MethodArg >= len(call.Args) || registrar.PathArg >= len(call.Args) || registrar.HandlerArg >= len(call.Args) {
+		return
+	}
+
+	route := RouteInfo{
+		Method:      strings.ToUpper(s.extractStringLiteral(call.Args[registrar.MethodArg])),
+		Path:        s.extractStringLiteral(call.Args[registrar.PathArg]),
+		HandlerName: s.extractHandlerInfo(call.Args[registrar.HandlerArg]),
+		HandlerNode: call.Args[registrar.HandlerArg],
+		Position:    s.FileSet.Position(call.Pos()),
+		Middleware:  s.registrarMiddleware(call, registrar.HandlerArg),
+	}
+	if route.Method == "" {
+		route.Method = "ANY"
+		route.Dynamic = true
+	}
+	if route.Path == "" {
+		route.Path = "/<" + types.ExprString(call.Args[registrar.PathArg]) + ">"
+		route.Dynamic = true
+	}
+	s.Routes = append(s.Routes, route)
+
+	dynamic := ""
+	if route.Dynamic {
+		dynamic = " (dynamic)"
+	}
+	s.Log.Debugf("  Found registrar route: %s %s -> %s%s", route.Method, route.Path, route.HandlerName, dynamic)
+}
+
+// registrarMiddleware returns the names of the middleware passed to a
+// registrar after the handler. A slice literal spread as the variadic
+// argument, e.g. []echo.MiddlewareFunc{auth, logger}..., is flattened; other
+// spread slices can't be listed statically and are left out.
+func (s *RouteScanner) registrarMiddleware(call *ast.CallExpr, handlerArg int) []string {
+	args := call.Args[handlerArg+1:]
+	if call.Ellipsis.IsValid() && len(args) > 0 {
+		spread := args[len(args)-1]
+		args = args[:len(args)-1]
+		if lit, ok := spread.(*ast.CompositeLit); ok {
+			args = append(append([]ast.Expr{}, args...), lit.Elts...)
+		} else {
+			s.Log.Debugf("  Skipping middleware %s... at %s: not a slice literal", types.ExprString(spread), s.FileSet.Position(spread.Pos()))
+		}
+	}
+	return s.middlewareNames(args)
+}
+
 // getHTTPMethod returns the HTTP method for an Echo method name
 func (s *RouteScanner) getHTTPMethod(methodName string) string {
 	switch methodName {
-	case "GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD":
+	case "GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD", "CONNECT", "TRACE":
 		return methodName
 	case "Any":
 		return "ANY"
@@ -163,17 +1094,90 @@ func (s *RouteScanner) getHTTPMethod(methodName string) string {
 	}
 }
 
-// extractStringLiteral extracts a string literal from an AST expression
+// extractStringLiteral extracts a string from an AST expression: a string
+// literal, a string constant of the scanned code (UsersPath, routes.UsersPath),
+// a net/http method constant (http.MethodGet), or a concatenation of those.
+// Paths computed at runtime can't be resolved and yield "".
 func (s *RouteScanner) extractStringLiteral(expr ast.Expr) string {
-	if lit, ok := expr.(*ast.BasicLit); ok {
-		if lit.Kind == token.STRING {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
 			// Remove quotes
-			return strings.Trim(lit.Value, "\"'`")
+			return strings.Trim(e.Value, "\"'`")
+		}
+	case *ast.Ident:
+		return s.constants[s.currentPackage][e.Name]
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if pkg.Name == "http" && strings.HasPrefix(e.Sel.Name, "Method") {
+			return strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method"))
+		}
+		return s.constants[pkg.Name][e.Sel.Name]
+	case *ast.ParenExpr:
+		return s.extractStringLiteral(e.X)
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			left, right := s.extractStringLiteral(e.X), s.extractStringLiteral(e.Y)
+			if left != "" && right != "" {
+				return left + right
+			}
 		}
 	}
 	return ""
 }
 
+// collectConstants records the string constants declared at the top level of
+// each package. Constants defined in terms of other constants are resolved
+// once every declaration has been seen.
+func (s *RouteScanner) collectConstants(files []*ast.File) {
+	type pendingConstant struct {
+		pkg, name string
+		value     ast.Expr
+	}
+	pending := []pendingConstant{}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok || len(valueSpec.Values) != len(valueSpec.Names) {
+					continue
+				}
+				for i, name := range valueSpec.Names {
+					pending = append(pending, pendingConstant{file.Name.Name, name.Name, valueSpec.Values[i]})
+				}
+			}
+		}
+	}
+
+	// Resolve constants until no more can be resolved
+	for resolved := true; resolved; {
+		resolved = false
+		remaining := pending[:0]
+		for _, c := range pending {
+			s.currentPackage = c.pkg
+			if value := s.extractStringLiteral(c.value); value != "" {
+				if s.constants[c.pkg] == nil {
+					s.constants[c.pkg] = make(map[string]string)
+				}
+				s.constants[c.pkg][c.name] = value
+				resolved = true
+				continue
+			}
+			remaining = append(remaining, c)
+		}
+		pending = remaining
+	}
+	s.currentPackage = ""
+}
+
 // extractHandlerInfo extracts information about a handler function
 func (s *RouteScanner) extractHandlerInfo(expr ast.Expr) string {
 	switch v := expr.(type) {
@@ -196,3 +1200,13 @@ func (s *RouteScanner) extractHandlerInfo(expr ast.Expr) string {
 func (s *RouteScanner) GetRoutes() []RouteInfo {
 	return s.Routes
 }
+
+// GetErrorHandler returns the HTTP error handler, or nil if none is assigned
+func (s *RouteScanner) GetErrorHandler() *ErrorHandlerInfo {
+	return s.ErrorHandler
+}
+
+// GetMiddleware returns the global middleware, in registration order
+func (s *RouteScanner) GetMiddleware() []string {
+	return s.Middleware
+}
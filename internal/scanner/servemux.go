@@ -0,0 +1,248 @@
+package scanner
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// ParseServeMuxPattern parses a net/http ServeMux pattern of the form
+// [METHOD ][HOST]/[PATH], as in "GET /users/{id}", into a method and a
+// route path in Echo's syntax. Patterns without a method match any method,
+// and the host is dropped. Wildcards are converted to parameters, so
+// {id} and {rest...} become :id and :rest. A pattern ending in a slash
+// matches every path below it, unless it ends in {$}, so /static/ becomes
+// /static/* and /posts/{$} becomes /posts/.
+func ParseServeMuxPattern(pattern string) (method, path string, ok bool) {
+	method = "ANY"
+	rest := strings.TrimSpace(pattern)
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		method = strings.ToUpper(rest[:i])
+		rest = strings.TrimLeft(rest[i:], " \t")
+	}
+
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		return "", "", false
+	}
+	rest = rest[slash:]
+
+	var b strings.Builder
+	exact := false
+	for _, segment := range strings.Split(rest[1:], "/") {
+		b.WriteString("/")
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			b.WriteString(segment)
+			continue
+		}
+		name := strings.TrimSuffix(segment[1:len(segment)-1], "...")
+		if name == "$" {
+			exact = true
+			continue
+		}
+		b.WriteString(":" + name)
+	}
+	path = b.String()
+
+	if strings.HasSuffix(path, "/") && !exact {
+		path += "*"
+	}
+	return method, path, true
+}
+
+// netHTTPPackage is the name net/http is referred to by in the scanned code
+const netHTTPPackage = "http"
+
+// scanServeMux finds the routes registered on net/http ServeMux instances,
+// as in mux.HandleFunc("GET /users/{id}", getUser), and on the default mux
+// with http.HandleFunc and http.Handle
+func (s *RouteScanner) scanServeMux(files []*ast.File) {
+	muxNames := map[string]bool{"DefaultServeMux": true}
+	for _, file := range files {
+		s.collectServeMuxNames(file, muxNames)
+	}
+
+	for _, file := range files {
+		s.currentPackage = file.Name.Name
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (sel.Sel.Name != "HandleFunc" && sel.Sel.Name != "Handle") {
+				return true
+			}
+			if !isServeMux(sel.X, muxNames) {
+				return true
+			}
+			s.addServeMuxRoute(call)
+			return true
+		})
+	}
+}
+
+// collectServeMuxNames records the variables, parameters and struct fields
+// holding a ServeMux: mux := http.NewServeMux(), func routes(mux *http.ServeMux),
+// or a field declared as mux *http.ServeMux
+func (s *RouteScanner) collectServeMuxNames(file *ast.File, muxNames map[string]bool) {
+	addNames := func(names []*ast.Ident) {
+		for _, name := range names {
+			if name.Name != "_" && !muxNames[name.Name] {
+				s.Log.Debugf("  Found ServeMux instance: %s", name.Name)
+				muxNames[name.Name] = true
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Field:
+			if isServeMuxType(node.Type) {
+				addNames(node.Names)
+			}
+		case *ast.ValueSpec:
+			if node.Type != nil && isServeMuxType(node.Type) {
+				addNames(node.Names)
+			}
+			for i, value := range node.Values {
+				if i < len(node.Names) && isNewServeMux(value) {
+					addNames(node.Names[i : i+1])
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				if !isNewServeMux(rhs) {
+					continue
+				}
+				switch lhs := node.Lhs[i].(type) {
+				case *ast.Ident:
+					addNames([]*ast.Ident{lhs})
+				case *ast.SelectorExpr:
+					addNames([]*ast.Ident{lhs.Sel})
+				}
+			}
+		case *ast.KeyValueExpr:
+			// &server{mux: http.NewServeMux()}
+			if key, ok := node.Key.(*ast.Ident); ok && isNewServeMux(node.Value) {
+				addNames([]*ast.Ident{key})
+			}
+		}
+		return true
+	})
+}
+
+// isServeMuxType checks if a type expression is *http.ServeMux
+func isServeMuxType(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "ServeMux" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == netHTTPPackage
+}
+
+// isNewServeMux checks if an expression creates a ServeMux, with
+// http.NewServeMux() or &http.ServeMux{}
+func isNewServeMux(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return isHTTPSelector(e.Fun, "NewServeMux")
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok {
+			return isHTTPSelector(lit.Type, "ServeMux")
+		}
+	}
+	return false
+}
+
+// isServeMux checks if the receiver of a HandleFunc or Handle call is the
+// net/http package, for the default mux, or a known ServeMux, referred to
+// by a variable or a field, as in s.mux.HandleFunc(...)
+func isServeMux(expr ast.Expr, muxNames map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name == netHTTPPackage || muxNames[e.Name]
+	case *ast.SelectorExpr:
+		return muxNames[e.Sel.Name]
+	}
+	return false
+}
+
+// isHTTPSelector checks if an expression is http.<name>
+func isHTTPSelector(expr ast.Expr, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == netHTTPPackage
+}
+
+// addServeMuxRoute records the route registered with a ServeMux's
+// HandleFunc or Handle. Handlers converted with http.HandlerFunc(f) are
+// named after f, and file servers, as in
+// http.StripPrefix("/static/", http.FileServer(http.Dir("public"))), are
+// documented as static routes.
+func (s *RouteScanner) addServeMuxRoute(call *ast.CallExpr) {
+	pattern := s.extractStringLiteral(call.Args[0])
+	method, path, ok := ParseServeMuxPattern(pattern)
+	if !ok {
+		s.Log.Debugf("  Skipping ServeMux route at %s: pattern is not a constant pattern", s.FileSet.Position(call.Pos()))
+		return
+	}
+
+	handler := call.Args[1]
+	if conversion, ok := handler.(*ast.CallExpr); ok && isHTTPSelector(conversion.Fun, "HandlerFunc") && len(conversion.Args) == 1 {
+		handler = conversion.Args[0]
+	}
+
+	route := RouteInfo{
+		Method:      method,
+		Path:        path,
+		HandlerName: s.extractHandlerInfo(handler),
+		HandlerNode: handler,
+		Position:    s.FileSet.Position(call.Pos()),
+		Middleware:  []string{},
+		Name:        s.routeNames[call],
+	}
+	if dir, ok := fileServerDir(handler); ok && strings.HasSuffix(path, "*") {
+		route.Method = "GET"
+		route.HandlerName = "FileServer"
+		route.HandlerNode = nil
+		route.StaticDir = s.extractStringLiteral(dir)
+		if route.StaticDir == "" {
+			route.StaticDir = types.ExprString(dir)
+		}
+	}
+	s.Routes = append(s.Routes, route)
+
+	s.Log.Debugf("  Found route: %s %s -> %s", route.Method, route.Path, route.HandlerName)
+}
+
+// fileServerDir returns the directory served by http.FileServer(http.Dir(dir))
+// or the file system served by http.FileServer(fsys), possibly wrapped in
+// http.StripPrefix
+func fileServerDir(expr ast.Expr) (ast.Expr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil, false
+	}
+	if isHTTPSelector(call.Fun, "StripPrefix") && len(call.Args) == 2 {
+		return fileServerDir(call.Args[1])
+	}
+	if !isHTTPSelector(call.Fun, "FileServer") {
+		return nil, false
+	}
+	if dir, ok := call.Args[0].(*ast.CallExpr); ok && isHTTPSelector(dir.Fun, "Dir") && len(dir.Args) == 1 {
+		return dir.Args[0], true
+	}
+	return call.Args[0], true
+}
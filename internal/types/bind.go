@@ -0,0 +1,97 @@
+package types
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// bindTagSources are the struct tag keys Echo's binder reads, in the order
+// it binds them: path parameters, query parameters and headers
+var bindTagSources = []string{"param", "query", "header"}
+
+// bindInputTypes maps binding tag keys to the request input types they bind
+var bindInputTypes = map[string]string{
+	"param":  "Path",
+	"query":  "Query",
+	"header": "Header",
+}
+
+// bindTags returns the names a struct field is bound from by Echo's binder,
+// by tag key, or nil if the field has no binding tag
+func bindTags(field *ast.Field) map[string]string {
+	var tags map[string]string
+	for _, key := range bindTagSources {
+		name := strings.Split(fieldTag(field, key), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[key] = name
+	}
+	return tags
+}
+
+// BoundParam is a struct field c.Bind fills from the path, query string or
+// headers rather than the body
+type BoundParam struct {
+	Type     string // Path, Query or Header
+	Name     string // Parameter name from the binding tag
+	Required bool   // Whether the validate tag requires the field
+	Field    *FieldDefinition
+}
+
+// SplitBinding splits a struct bound with c.Bind into the fields Echo's
+// binder fills from the path, query string and headers, and a struct of the
+// JSON-tagged fields bound from the body, nil if there are none. Types
+// without binding tags are returned as the body.
+func SplitBinding(typeDef *TypeDefinition) ([]BoundParam, *TypeDefinition) {
+	structDef := typeDef
+	if structDef != nil && structDef.Kind == KindPointer {
+		structDef = structDef.ElementType
+	}
+	if structDef == nil || structDef.Kind != KindStruct {
+		return nil, typeDef
+	}
+
+	params := []BoundParam{}
+	for _, field := range structDef.Fields {
+		for _, key := range bindTagSources {
+			if name, ok := field.Bind[key]; ok {
+				params = append(params, BoundParam{
+					Type:     bindInputTypes[key],
+					Name:     name,
+					Required: key == "param" || hasValidationRule(field.Validate, "required"),
+					Field:    field,
+				})
+			}
+		}
+	}
+	if len(params) == 0 {
+		return nil, typeDef
+	}
+
+	// Echo only decodes the body into the fields the JSON decoder names
+	body := *structDef
+	body.Fields = []*FieldDefinition{}
+	for _, field := range structDef.Fields {
+		if field.JSONTagged {
+			body.Fields = append(body.Fields, field)
+		}
+	}
+	if len(body.Fields) == 0 {
+		return params, nil
+	}
+	return params, &body
+}
+
+// hasValidationRule reports whether a validate tag has a rule
+func hasValidationRule(validate, rule string) bool {
+	for _, r := range strings.Split(validate, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
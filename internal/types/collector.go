@@ -1,30 +1,29 @@
 package types
 
 import (
-	"fmt"
 	"go/ast"
 	"path/filepath"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // TypeCollector scans the codebase to collect type definitions
 type TypeCollector struct {
 	Registry *TypeRegistry
-	Verbose  bool
+	Log      *logger.Logger
 }
 
 // NewTypeCollector creates a new TypeCollector
-func NewTypeCollector(registry *TypeRegistry, verbose bool) *TypeCollector {
+func NewTypeCollector(registry *TypeRegistry, log *logger.Logger) *TypeCollector {
 	return &TypeCollector{
 		Registry: registry,
-		Verbose:  verbose,
+		Log:      log,
 	}
 }
 
 // CollectTypes collects type definitions from all packages in the codebase
 func (c *TypeCollector) CollectTypes(files []*ast.File, packagePath string) error {
-	if c.Verbose {
-		fmt.Printf("Collecting types from package: %s\n", packagePath)
-	}
+	c.Log.Debugf("Collecting types from package: %s", packagePath)
 
 	// Set the current package in the registry
 	c.Registry.SetCurrentPackage(packagePath)
@@ -39,6 +38,11 @@ func (c *TypeCollector) CollectTypes(files []*ast.File, packagePath string) erro
 		c.collectTypeDeclarations(file)
 	}
 
+	// Third pass: collect functions and the methods of the declared types
+	for _, file := range files {
+		c.collectFunctions(file)
+	}
+
 	return nil
 }
 
@@ -81,6 +85,78 @@ func (c *TypeCollector) collectTypeDeclarations(file *ast.File) {
 
 			// Process the type declaration
 			c.processTypeDeclaration(typeSpec)
+
+			// A single declaration is documented by the comment of the group
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+			if schema, err := schemaOverride(doc); err != nil {
+				c.Log.Warnf("invalid schema comment on type %s: %v", typeSpec.Name.Name, err)
+			} else if schema != nil {
+				if typeDef := c.Registry.Packages[c.Registry.CurrentPackage].Types[typeSpec.Name.Name]; typeDef != nil {
+					typeDef.Schema = schema
+				}
+			}
+		}
+	}
+}
+
+// collectFunctions records the result types of the functions declared in a
+// file, and the methods declared on the types of the package
+func (c *TypeCollector) collectFunctions(file *ast.File) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		// The first result is the value; a trailing error is ignored
+		var resultExpr ast.Expr
+		if results := funcDecl.Type.Results; results != nil && len(results.List) > 0 {
+			resultExpr = results.List[0].Type
+		}
+
+		// Plain functions are recorded on the package
+		if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+			if resultExpr != nil {
+				pkg := c.Registry.RegisterPackage(c.Registry.CurrentPackage)
+				pkg.Functions[funcDecl.Name.Name] = resultExpr
+			}
+			continue
+		}
+
+		// Find the receiver type name (T, *T, T[P] or *T[P])
+		recvType := funcDecl.Recv.List[0].Type
+		if star, ok := recvType.(*ast.StarExpr); ok {
+			recvType = star.X
+		}
+		switch t := recvType.(type) {
+		case *ast.IndexExpr:
+			recvType = t.X
+		case *ast.IndexListExpr:
+			recvType = t.X
+		}
+		ident, ok := recvType.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		typeDef := c.Registry.LookupType(ident.Name)
+		if typeDef == nil || typeDef.Kind == KindInterface {
+			continue
+		}
+
+		if typeDef.Methods == nil {
+			typeDef.Methods = make(map[string]string)
+			typeDef.methodResults = make(map[string]ast.Expr)
+		}
+		typeDef.Methods[funcDecl.Name.Name] = methodSignature(funcDecl.Type)
+		if funcDecl.Name.Name == "MarshalJSON" && typeDef.Methods["MarshalJSON"] == marshalJSONSignature {
+			typeDef.HasCustomMarshaler = true
+		}
+		if resultExpr != nil {
+			typeDef.methodResults[funcDecl.Name.Name] = resultExpr
 		}
 	}
 }
@@ -99,6 +175,8 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 			Fields:     []*FieldDefinition{},
 			Package:    c.Registry.CurrentPackage,
 			IsResolved: false,
+			TypeParams: typeParamNames(typeSpec),
+			XMLName:    xmlRootName(structType),
 		}
 
 		// Register the type (even though it's not fully resolved yet)
@@ -111,14 +189,25 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 				for _, name := range field.Names {
 					// Process JSON tags
 					jsonName, omitempty := c.Registry.extractJSONTag(field)
+					xmlName, xmlAttr, xmlOmitempty := extractXMLTag(field)
 
 					// Create a field definition with a placeholder type
 					fieldDef := &FieldDefinition{
-						Name:      name.Name,
-						Type:      nil, // Will be resolved later
-						JSONName:  jsonName,
-						Omitempty: omitempty,
-						IsPointer: isPointerType(field.Type),
+						Name:         name.Name,
+						Type:         nil, // Will be resolved later
+						JSONName:     jsonName,
+						Omitempty:    omitempty,
+						IsPointer:    isPointerType(field.Type),
+						Deprecated:   isDeprecatedField(field),
+						Description:  fieldDescription(field),
+						Validate:     validateTag(field),
+						Example:      fieldTag(field, "example"),
+						XMLName:      xmlName,
+						XMLAttr:      xmlAttr,
+						XMLOmitempty: xmlOmitempty,
+						JSONTagged:   c.Registry.jsonTag(field) != "",
+						Bind:         bindTags(field),
+						typeExpr:     field.Type,
 					}
 
 					typeDef.Fields = append(typeDef.Fields, fieldDef)
@@ -126,14 +215,23 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 			}
 		}
 
-		if c.Verbose {
-			fmt.Printf("Collected struct type: %s with %d fields\n", typeName, len(typeDef.Fields))
-		}
+		c.Log.Debugf("Collected struct type: %s with %d fields", typeName, len(typeDef.Fields))
+		return
+	}
+
+	// Check if it's an interface type
+	if interfaceType, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
+		typeDef := newInterfaceType(typeName, c.Registry.CurrentPackage, interfaceType)
+
+		// Register the type
+		c.Registry.RegisterType(typeDef)
+
+		c.Log.Debugf("Collected interface type: %s with %d methods", typeName, len(typeDef.Methods))
 		return
 	}
 
 	// Check if it's an array type
-	_, isArray := typeSpec.Type.(*ast.ArrayType)
+	arrayType, isArray := typeSpec.Type.(*ast.ArrayType)
 	if isArray {
 		// Create a new type definition
 		typeDef := &TypeDefinition{
@@ -142,19 +240,18 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 			ElementType: nil, // Will be resolved later
 			Package:     c.Registry.CurrentPackage,
 			IsResolved:  false,
+			typeExpr:    arrayType,
 		}
 
 		// Register the type
 		c.Registry.RegisterType(typeDef)
 
-		if c.Verbose {
-			fmt.Printf("Collected array type: %s\n", typeName)
-		}
+		c.Log.Debugf("Collected array type: %s", typeName)
 		return
 	}
 
 	// Check if it's a map type
-	_, isMap := typeSpec.Type.(*ast.MapType)
+	mapType, isMap := typeSpec.Type.(*ast.MapType)
 	if isMap {
 		// Create a new type definition
 		typeDef := &TypeDefinition{
@@ -164,14 +261,13 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 			ValueType:  nil, // Will be resolved later
 			Package:    c.Registry.CurrentPackage,
 			IsResolved: false,
+			typeExpr:   mapType,
 		}
 
 		// Register the type
 		c.Registry.RegisterType(typeDef)
 
-		if c.Verbose {
-			fmt.Printf("Collected map type: %s\n", typeName)
-		}
+		c.Log.Debugf("Collected map type: %s", typeName)
 		return
 	}
 
@@ -186,16 +282,12 @@ func (c *TypeCollector) processTypeDeclaration(typeSpec *ast.TypeSpec) {
 	// Register the type
 	c.Registry.RegisterType(typeDef)
 
-	if c.Verbose {
-		fmt.Printf("Collected basic type: %s\n", typeName)
-	}
+	c.Log.Debugf("Collected basic type: %s", typeName)
 }
 
 // ResolveTypes resolves all collected types
 func (c *TypeCollector) ResolveTypes() error {
-	if c.Verbose {
-		fmt.Println("Resolving types...")
-	}
+	c.Log.Debugf("Resolving types...")
 
 	// Iterate through all packages
 	for pkgPath, pkgInfo := range c.Registry.Packages {
@@ -208,6 +300,9 @@ func (c *TypeCollector) ResolveTypes() error {
 		}
 	}
 
+	// Find the implementations of interfaces now that all method sets are known
+	c.Registry.ResolveImplementations()
+
 	return nil
 }
 
@@ -219,53 +314,72 @@ func (c *TypeCollector) resolveType(typeDef *TypeDefinition) {
 
 	switch typeDef.Kind {
 	case KindStruct:
-		// Resolve field types
+		// Resolve field types from their AST nodes now that all types in
+		// the package are registered. Type parameters of an uninstantiated
+		// generic type are left as any.
 		for _, field := range typeDef.Fields {
 			// Skip already resolved fields
 			if field.Type != nil && field.Type.IsResolved {
 				continue
 			}
-
-			// TODO: This is a placeholder. In a real implementation,
-			// we would need to look up the AST node for the field type
-			// and resolve it using the Registry.ResolveType method.
-			// For now, we'll just set a basic type.
-			field.Type = &TypeDefinition{
-				Name:       "string", // Placeholder
-				Kind:       KindBasic,
-				BasicType:  "string",
-				Package:    typeDef.Package,
-				IsResolved: true,
+			if field.typeExpr != nil {
+				field.Type = c.Registry.resolveFieldType(typeDef, field)
+			}
+			if field.Type == nil {
+				field.Type = unresolvedFieldType(field.typeExpr, typeDef.Package)
 			}
 		}
 
+	case KindInterface:
+		// Merge the methods of embedded interfaces
+		c.resolveEmbeddedInterfaces(typeDef, make(map[*TypeDefinition]bool))
+
 	case KindArray:
-		// TODO: Resolve element type
-		typeDef.ElementType = &TypeDefinition{
-			Name:       "string", // Placeholder
-			Kind:       KindBasic,
-			BasicType:  "string",
-			Package:    typeDef.Package,
-			IsResolved: true,
+		// Resolve the element type from the declared array type
+		if arrayType, ok := typeDef.typeExpr.(*ast.ArrayType); ok {
+			typeDef.ElementType = c.Registry.ResolveType(arrayType.Elt)
+			if typeDef.ElementType == nil {
+				typeDef.ElementType = unresolvedFieldType(arrayType.Elt, typeDef.Package)
+			}
 		}
 
 	case KindMap:
-		// TODO: Resolve key and value types
-		typeDef.KeyType = &TypeDefinition{
-			Name:       "string", // Placeholder
-			Kind:       KindBasic,
-			BasicType:  "string",
-			Package:    typeDef.Package,
-			IsResolved: true,
-		}
-		typeDef.ValueType = &TypeDefinition{
-			Name:       "string", // Placeholder
-			Kind:       KindBasic,
-			BasicType:  "string",
-			Package:    typeDef.Package,
-			IsResolved: true,
+		// Resolve the key and value types from the declared map type
+		if mapType, ok := typeDef.typeExpr.(*ast.MapType); ok {
+			typeDef.KeyType = c.Registry.ResolveType(mapType.Key)
+			if typeDef.KeyType == nil {
+				typeDef.KeyType = unresolvedFieldType(mapType.Key, typeDef.Package)
+			}
+			typeDef.ValueType = c.Registry.ResolveType(mapType.Value)
+			if typeDef.ValueType == nil {
+				typeDef.ValueType = unresolvedFieldType(mapType.Value, typeDef.Package)
+			}
 		}
 	}
 
 	typeDef.IsResolved = true
 }
+
+// unresolvedFieldType returns a placeholder basic type for a field whose type
+// is not defined in the analyzed code, keeping the qualified name of external
+// types (e.g. time.Time) so the schema generator can map well-known types
+func unresolvedFieldType(expr ast.Expr, packagePath string) *TypeDefinition {
+	typeName := "string" // Placeholder
+	if star, ok := expr.(*ast.StarExpr); ok {
+		// Pointer fields are flagged on the field definition
+		expr = star.X
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if x, ok := sel.X.(*ast.Ident); ok {
+			typeName = x.Name + "." + sel.Sel.Name
+		}
+	}
+
+	return &TypeDefinition{
+		Name:       typeName,
+		Kind:       KindBasic,
+		BasicType:  typeName,
+		Package:    packagePath,
+		IsResolved: true,
+	}
+}
@@ -0,0 +1,247 @@
+package types
+
+import (
+	"go/ast"
+	"sync"
+)
+
+// ResponseMatch describes the response written by a call a ResponseDetector matched
+type ResponseMatch struct {
+	Type        string   // JSON, XML, String, HTML, File, Blob, Stream, NoContent or Redirect
+	Status      ast.Expr // Status code argument, nil if the response is always 200
+	Payload     ast.Expr // Value written in the body, nil if there is none
+	Encoded     bool     // Whether Payload holds bytes that are already encoded, as in c.JSONBlob
+	ContentType ast.Expr // Content type argument (Blob, Stream) or file name (File), if any
+	Template    ast.Expr // Name of the template rendered with Payload as its model (Render), if any
+}
+
+// RequestMatch describes the request input read by a call a RequestDetector matched
+type RequestMatch struct {
+	Type     string   // Path, Query, Form, File, Cookie, Header or Body
+	Name     ast.Expr // Name of the input, or the variable a Body is bound to
+	Default  ast.Expr // Value used when the input is missing, if any
+	DataType string   // Data type of the input, "string" if empty
+	Required bool     // Whether the input is required
+}
+
+// ResponseDetector extracts the response written by a call to a response
+// helper, such as c.JSON(http.StatusOK, user) or respondJSON(c, 200, user)
+type ResponseDetector interface {
+	DetectResponse(call *ast.CallExpr) (ResponseMatch, bool)
+}
+
+// ResponseDetectorFunc adapts a function to a ResponseDetector
+type ResponseDetectorFunc func(call *ast.CallExpr) (ResponseMatch, bool)
+
+// DetectResponse calls f(call)
+func (f ResponseDetectorFunc) DetectResponse(call *ast.CallExpr) (ResponseMatch, bool) {
+	return f(call)
+}
+
+// RequestDetector extracts the request input read by a call to a request
+// helper, such as c.Param("id") or pathInt(c, "id")
+type RequestDetector interface {
+	DetectRequest(call *ast.CallExpr) (RequestMatch, bool)
+}
+
+// RequestDetectorFunc adapts a function to a RequestDetector
+type RequestDetectorFunc func(call *ast.CallExpr) (RequestMatch, bool)
+
+// DetectRequest calls f(call)
+func (f RequestDetectorFunc) DetectRequest(call *ast.CallExpr) (RequestMatch, bool) {
+	return f(call)
+}
+
+// Detectors is a registry of response and request detectors, by the name of
+// the function or method whose calls they match. It is safe for concurrent use.
+type Detectors struct {
+	mu        sync.RWMutex
+	responses map[string][]ResponseDetector
+	requests  map[string][]RequestDetector
+}
+
+// NewDetectors creates an empty Detectors registry
+func NewDetectors() *Detectors {
+	return &Detectors{
+		responses: make(map[string][]ResponseDetector),
+		requests:  make(map[string][]RequestDetector),
+	}
+}
+
+// RegisterResponse registers a detector for calls to the function or method
+// named name. Detectors registered later for the same name are tried first.
+func (d *Detectors) RegisterResponse(name string, detector ResponseDetector) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.responses[name] = append(d.responses[name], detector)
+}
+
+// RegisterRequest registers a detector for calls to the function or method
+// named name. Detectors registered later for the same name are tried first.
+func (d *Detectors) RegisterRequest(name string, detector RequestDetector) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests[name] = append(d.requests[name], detector)
+}
+
+// Include registers the detectors of another registry, after the ones
+// already registered
+func (d *Detectors) Include(other *Detectors) {
+	if other == nil {
+		return
+	}
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	for name, detectors := range other.responses {
+		for _, detector := range detectors {
+			d.RegisterResponse(name, detector)
+		}
+	}
+	for name, detectors := range other.requests {
+		for _, detector := range detectors {
+			d.RegisterRequest(name, detector)
+		}
+	}
+}
+
+// DetectResponse returns the response written by a call, if a detector
+// registered for the called name matches it
+func (d *Detectors) DetectResponse(call *ast.CallExpr) (ResponseMatch, bool) {
+	d.mu.RLock()
+	detectors := d.responses[CallName(call)]
+	d.mu.RUnlock()
+	for i := len(detectors) - 1; i >= 0; i-- {
+		if match, ok := detectors[i].DetectResponse(call); ok {
+			return match, true
+		}
+	}
+	return ResponseMatch{}, false
+}
+
+// DetectRequest returns the request input read by a call, if a detector
+// registered for the called name matches it
+func (d *Detectors) DetectRequest(call *ast.CallExpr) (RequestMatch, bool) {
+	d.mu.RLock()
+	detectors := d.requests[CallName(call)]
+	d.mu.RUnlock()
+	for i := len(detectors) - 1; i >= 0; i-- {
+		if match, ok := detectors[i].DetectRequest(call); ok {
+			return match, true
+		}
+	}
+	return RequestMatch{}, false
+}
+
+// CallName returns the name of the function or method a call invokes:
+// JSON for c.JSON(...), respondJSON for respondJSON(...)
+func CallName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// contextNames lists the common names of the Echo context parameter
+var contextNames = map[string]bool{
+	"c": true, "ctx": true, "context": true, "ec": true,
+}
+
+// IsContextCall reports whether a call is a method call on the Echo
+// context parameter, e.g. c.JSON(...)
+func IsContextCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && contextNames[ident.Name]
+}
+
+// echoResponseMethods maps the echo.Context methods writing a response to
+// the response type and the positions of their arguments, -1 if absent
+var echoResponseMethods = map[string]struct {
+	Type        string
+	Status      int
+	Payload     int
+	ContentType int
+	Encoded     bool
+}{
+	// c.String(http.StatusOK, "Hello")
+	"String": {Type: "String", Status: 0, Payload: 1, ContentType: -1},
+	// c.JSON(http.StatusOK, user), c.JSONPretty(http.StatusOK, user, "  ")
+	"JSON":       {Type: "JSON", Status: 0, Payload: 1, ContentType: -1},
+	"JSONPretty": {Type: "JSON", Status: 0, Payload: 1, ContentType: -1},
+	// c.JSONBlob(http.StatusOK, data) writes bytes that are already encoded
+	"JSONBlob": {Type: "JSON", Status: 0, Payload: 1, ContentType: -1, Encoded: true},
+	// c.XML(http.StatusOK, data), c.XMLPretty(http.StatusOK, data, "  ")
+	"XML":       {Type: "XML", Status: 0, Payload: 1, ContentType: -1},
+	"XMLPretty": {Type: "XML", Status: 0, Payload: 1, ContentType: -1},
+	// c.HTML(http.StatusOK, "<html>...</html>")
+	"HTML": {Type: "HTML", Status: 0, Payload: 1, ContentType: -1},
+	// c.File("path/to/file")
+	"File": {Type: "File", Status: -1, Payload: -1, ContentType: 0},
+	// c.Blob(http.StatusOK, "application/octet-stream", data)
+	"Blob": {Type: "Blob", Status: 0, Payload: 2, ContentType: 1},
+	// c.Stream(http.StatusOK, "application/octet-stream", reader)
+	"Stream": {Type: "Stream", Status: 0, Payload: 2, ContentType: 1},
+	// c.NoContent(http.StatusNoContent)
+	"NoContent": {Type: "NoContent", Status: 0, Payload: -1, ContentType: -1},
+	// c.Redirect(http.StatusFound, "/new-url")
+	"Redirect": {Type: "Redirect", Status: 0, Payload: -1, ContentType: -1},
+}
+
+// EchoDetectors creates a Detectors registry with the detectors of the
+// echo.Context methods writing a response
+func EchoDetectors() *Detectors {
+	d := NewDetectors()
+	RegisterEchoResponseDetectors(d)
+	return d
+}
+
+// RegisterEchoResponseDetectors registers the detectors of the echo.Context
+// methods writing a response
+func RegisterEchoResponseDetectors(d *Detectors) {
+	for name, method := range echoResponseMethods {
+		method := method
+		d.RegisterResponse(name, ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+			if !IsContextCall(call) {
+				return ResponseMatch{}, false
+			}
+			match := ResponseMatch{
+				Type:        method.Type,
+				Status:      argAt(call, method.Status),
+				Payload:     argAt(call, method.Payload),
+				ContentType: argAt(call, method.ContentType),
+				Encoded:     method.Encoded,
+			}
+			return match, true
+		}))
+	}
+
+	// c.Render(http.StatusOK, "user.html", user) renders a template with
+	// the registered echo.Renderer, passing the payload as its model
+	d.RegisterResponse("Render", ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+		if !IsContextCall(call) {
+			return ResponseMatch{}, false
+		}
+		match := ResponseMatch{
+			Type:     "HTML",
+			Status:   argAt(call, 0),
+			Payload:  argAt(call, 2),
+			Template: argAt(call, 1),
+		}
+		return match, true
+	}))
+}
+
+// argAt returns the argument of a call at an index, or nil if the index is
+// negative or past the last argument
+func argAt(call *ast.CallExpr, i int) ast.Expr {
+	if i < 0 || i >= len(call.Args) {
+		return nil
+	}
+	return call.Args[i]
+}
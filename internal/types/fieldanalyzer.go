@@ -4,28 +4,27 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // StructFieldAnalyzer analyzes struct fields to extract detailed type information
 type StructFieldAnalyzer struct {
 	Registry *TypeRegistry
-	Verbose  bool
+	Log      *logger.Logger
 }
 
 // NewStructFieldAnalyzer creates a new StructFieldAnalyzer
-func NewStructFieldAnalyzer(registry *TypeRegistry, verbose bool) *StructFieldAnalyzer {
+func NewStructFieldAnalyzer(registry *TypeRegistry, log *logger.Logger) *StructFieldAnalyzer {
 	return &StructFieldAnalyzer{
 		Registry: registry,
-		Verbose:  verbose,
+		Log:      log,
 	}
 }
 
 // AnalyzeStructFields analyzes all struct fields in the registry
 func (a *StructFieldAnalyzer) AnalyzeStructFields() error {
-	if a.Verbose {
-		fmt.Println("Analyzing struct fields...")
-	}
+	a.Log.Debugf("Analyzing struct fields...")
 
 	// Iterate through all packages
 	for pkgPath, pkgInfo := range a.Registry.Packages {
@@ -45,40 +44,34 @@ func (a *StructFieldAnalyzer) AnalyzeStructFields() error {
 
 // analyzeStructType analyzes a struct type and its fields
 func (a *StructFieldAnalyzer) analyzeStructType(typeDef *TypeDefinition) {
-	if a.Verbose {
-		fmt.Printf("Analyzing struct type: %s.%s\n", typeDef.Package, typeDef.Name)
-	}
+	a.Log.Debugf("Analyzing struct type: %s.%s", typeDef.Package, typeDef.Name)
 
-	// Skip already fully resolved types
-	if typeDef.IsResolved {
+	// Skip types this pass already analyzed, marking the type first so
+	// recursive types end the recursion
+	if typeDef.NestedAnalyzed {
 		return
 	}
+	typeDef.NestedAnalyzed = true
 
 	// Analyze each field
 	for _, field := range typeDef.Fields {
 		a.analyzeField(field, typeDef)
 	}
-
-	// Mark the type as resolved
-	typeDef.IsResolved = true
 }
 
 // analyzeField analyzes a struct field
 func (a *StructFieldAnalyzer) analyzeField(field *FieldDefinition, parentType *TypeDefinition) {
-	if a.Verbose {
-		fmt.Printf("  Analyzing field: %s\n", field.Name)
-	}
+	a.Log.Debugf("  Analyzing field: %s", field.Name)
 
-	// Skip already resolved fields
-	if field.Type != nil && field.Type.IsResolved {
-		return
+	// A field left without a type by the other passes is resolved from its
+	// AST node, or gets a placeholder type if it can't be
+	if field.Type == nil {
+		field.Type = a.Registry.resolveFieldType(parentType, field)
+	}
+	if field.Type == nil && field.typeExpr != nil {
+		field.Type = unresolvedFieldType(field.typeExpr, parentType.Package)
 	}
-
-	// If the field type is nil, try to resolve it
 	if field.Type == nil {
-		// This would require access to the AST node for the field
-		// In a real implementation, we would need to store the AST node with the field
-		// For now, we'll just set a placeholder type
 		field.Type = &TypeDefinition{
 			Name:       "unknown",
 			Kind:       KindBasic,
@@ -167,21 +160,14 @@ func (a *StructFieldAnalyzer) EnhanceTypeWithComments(file *ast.File) {
 					for _, fieldDef := range typeDef.Fields {
 						if fieldDef.Name == fieldName {
 							// Add comment to field if available
-							if field.Doc != nil {
-								// Extract comment text
-								comment := field.Doc.Text()
-								// Clean up comment (remove // or /* */ markers)
-								comment = strings.TrimSpace(comment)
-								comment = strings.TrimPrefix(comment, "//")
-								comment = strings.TrimPrefix(comment, "/*")
-								comment = strings.TrimSuffix(comment, "*/")
-								comment = strings.TrimSpace(comment)
-
-								// Store comment in field type (we'll need to add a Description field)
-								// For now, just log it
-								if a.Verbose {
-									fmt.Printf("  Field %s comment: %s\n", fieldName, comment)
-								}
+							if description := fieldDescription(field); description != "" {
+								fieldDef.Description = description
+								a.Log.Debugf("  Field %s comment: %s", fieldName, description)
+							}
+
+							// Flag deprecated fields from doc comments or tags
+							if isDeprecatedField(field) {
+								fieldDef.Deprecated = true
 							}
 							break
 						}
@@ -238,35 +224,12 @@ func (a *StructFieldAnalyzer) ExtractJSONTags(file *ast.File) {
 						continue
 					}
 
-					// Extract JSON tag
-					tagValue := field.Tag.Value
-					// Remove the backticks
-					tagValue = strings.Trim(tagValue, "`")
-
-					// Extract the json tag
-					jsonTag := ""
-					for _, tag := range strings.Split(tagValue, " ") {
-						if strings.HasPrefix(tag, "json:") {
-							jsonTag = strings.Trim(strings.TrimPrefix(tag, "json:"), "\"")
-							break
-						}
-					}
-
-					if jsonTag == "" {
+					// Extract the JSON tag
+					jsonName, omitempty, found := parseJSONTag(a.Registry.jsonTag(field))
+					if !found {
 						continue
 					}
 
-					// Parse the JSON tag
-					parts := strings.Split(jsonTag, ",")
-					jsonName := parts[0]
-					omitempty := false
-					for _, part := range parts[1:] {
-						if part == "omitempty" {
-							omitempty = true
-							break
-						}
-					}
-
 					// Find the field in the type definition
 					for _, fieldDef := range typeDef.Fields {
 						if fieldDef.Name == fieldName {
@@ -274,9 +237,7 @@ func (a *StructFieldAnalyzer) ExtractJSONTags(file *ast.File) {
 							fieldDef.JSONName = jsonName
 							fieldDef.Omitempty = omitempty
 
-							if a.Verbose {
-								fmt.Printf("  Field %s JSON tag: %s (omitempty: %v)\n", fieldName, jsonName, omitempty)
-							}
+							a.Log.Debugf("  Field %s JSON tag: %s (omitempty: %v)", fieldName, jsonName, omitempty)
 							break
 						}
 					}
@@ -288,9 +249,7 @@ func (a *StructFieldAnalyzer) ExtractJSONTags(file *ast.File) {
 
 // AnalyzeNestedStructs analyzes nested struct types
 func (a *StructFieldAnalyzer) AnalyzeNestedStructs() {
-	if a.Verbose {
-		fmt.Println("Analyzing nested struct ..")
-	}
+	a.Log.Debugf("Analyzing nested struct ..")
 
 	// Iterate through all packages
 	for pkgPath, pkgInfo := range a.Registry.Packages {
@@ -315,9 +274,7 @@ func (a *StructFieldAnalyzer) analyzeNestedStructs(typeDef *TypeDefinition, visi
 	}
 	visited[typeKey] = true
 
-	if a.Verbose {
-		fmt.Printf("Analyzing nested structs in: %s\n", typeKey)
-	}
+	a.Log.Debugf("Analyzing nested structs in: %s", typeKey)
 
 	// Analyze each field
 	for _, field := range typeDef.Fields {
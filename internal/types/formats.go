@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FormatRule infers the format of a struct field's schema from the field's
+// name, for fields of a JSON type without a format of their own
+type FormatRule struct {
+	Pattern *regexp.Regexp // Matched against the Go name of the field
+	Type    JSONSchemaType // JSON type of the fields the rule applies to
+	Format  JSONSchemaFormat
+}
+
+// JSONSchemaFormatUnixTime is the format of integer timestamps, in seconds
+// since the Unix epoch
+const JSONSchemaFormatUnixTime JSONSchemaFormat = "unix-time"
+
+// DefaultFormatRules are the format inference rules of a new SchemaGenerator:
+// strings named Email, URL, UUID or ending in them, e.g. ContactEmail or
+// AvatarURL, and timestamps named after an event, e.g. CreatedAt, whether
+// strings or integers
+var DefaultFormatRules = []FormatRule{
+	{Pattern: regexp.MustCompile(`(^|[a-z0-9])(Email|EMail)$|^email$`), Type: JSONSchemaTypeString, Format: JSONSchemaFormatEmail},
+	{Pattern: regexp.MustCompile(`(^|[a-z0-9])(URL|Url|URI|Uri)$|^(url|uri)$`), Type: JSONSchemaTypeString, Format: JSONSchemaFormatURI},
+	{Pattern: regexp.MustCompile(`(^|[a-z0-9])(UUID|Uuid)$|^uuid$`), Type: JSONSchemaTypeString, Format: JSONSchemaFormatUUID},
+	{Pattern: regexp.MustCompile(`[a-z0-9]At$`), Type: JSONSchemaTypeString, Format: JSONSchemaFormatDateTime},
+	{Pattern: regexp.MustCompile(`[a-z0-9]At$`), Type: JSONSchemaTypeInteger, Format: JSONSchemaFormatUnixTime},
+}
+
+// ParseFormatRule parses a format inference rule of the form
+// pattern=format[:type], where pattern is a regular expression matched
+// against field names and type the JSON type of the fields it applies to,
+// string if omitted (e.g. Phone$=phone or Expiry$=unix-time:integer)
+func ParseFormatRule(spec string) (FormatRule, error) {
+	i := strings.LastIndex(spec, "=")
+	if i <= 0 || i == len(spec)-1 {
+		return FormatRule{}, fmt.Errorf("invalid format rule %q, expected pattern=format[:type]", spec)
+	}
+
+	pattern, err := regexp.Compile(spec[:i])
+	if err != nil {
+		return FormatRule{}, fmt.Errorf("invalid pattern in format rule %q: %v", spec, err)
+	}
+
+	format, schemaType := spec[i+1:], string(JSONSchemaTypeString)
+	if j := strings.Index(format, ":"); j >= 0 {
+		format, schemaType = format[:j], format[j+1:]
+	}
+	switch JSONSchemaType(schemaType) {
+	case JSONSchemaTypeString, JSONSchemaTypeInteger, JSONSchemaTypeNumber:
+	default:
+		return FormatRule{}, fmt.Errorf("invalid schema type %q in format rule %q", schemaType, spec)
+	}
+	if format == "" {
+		return FormatRule{}, fmt.Errorf("invalid format rule %q, expected pattern=format[:type]", spec)
+	}
+
+	return FormatRule{Pattern: pattern, Type: JSONSchemaType(schemaType), Format: JSONSchemaFormat(format)}, nil
+}
+
+// AddFormatRule adds a format inference rule, taking precedence over the
+// rules already added
+func (g *SchemaGenerator) AddFormatRule(rule FormatRule) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.FormatRules = append([]FormatRule{rule}, g.FormatRules...)
+}
+
+// inferFormat returns the format of the first rule matching a field whose
+// schema has a JSON type, or "" if none does
+func (g *SchemaGenerator) inferFormat(field *FieldDefinition, schemaType JSONSchemaType) JSONSchemaFormat {
+	for _, rule := range g.FormatRules {
+		if rule.Type == schemaType && rule.Pattern.MatchString(field.Name) {
+			return rule.Format
+		}
+	}
+	return ""
+}
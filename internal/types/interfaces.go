@@ -0,0 +1,146 @@
+package types
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// newInterfaceType creates an interface type definition from an interface type expression
+func newInterfaceType(name, packagePath string, iface *ast.InterfaceType) *TypeDefinition {
+	typeDef := &TypeDefinition{
+		Name:       name,
+		Kind:       KindInterface,
+		Package:    packagePath,
+		Methods:    make(map[string]string),
+		IsResolved: true,
+	}
+
+	if iface.Methods == nil {
+		return typeDef
+	}
+
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			// Embedded interfaces are merged once all types are collected
+			typeDef.embedded = append(typeDef.embedded, field.Type)
+			typeDef.IsResolved = false
+			continue
+		}
+		for _, name := range field.Names {
+			typeDef.Methods[name.Name] = methodSignature(funcType)
+			if results := funcType.Results; results != nil && len(results.List) > 0 {
+				if typeDef.methodResults == nil {
+					typeDef.methodResults = make(map[string]ast.Expr)
+				}
+				typeDef.methodResults[name.Name] = results.List[0].Type
+			}
+		}
+	}
+
+	return typeDef
+}
+
+// resolveEmbeddedInterfaces merges the methods of embedded interfaces into an interface
+func (c *TypeCollector) resolveEmbeddedInterfaces(typeDef *TypeDefinition, visited map[*TypeDefinition]bool) {
+	if visited[typeDef] {
+		return
+	}
+	visited[typeDef] = true
+
+	for _, expr := range typeDef.embedded {
+		embedded := c.Registry.ResolveType(expr)
+		if embedded == nil || embedded.Kind != KindInterface {
+			continue
+		}
+		c.resolveEmbeddedInterfaces(embedded, visited)
+		for name, signature := range embedded.Methods {
+			typeDef.Methods[name] = signature
+		}
+		for name, resultExpr := range embedded.methodResults {
+			if typeDef.methodResults == nil {
+				typeDef.methodResults = make(map[string]ast.Expr)
+			}
+			typeDef.methodResults[name] = resultExpr
+		}
+	}
+	typeDef.embedded = nil
+}
+
+// ResolveImplementations finds the concrete types in the analyzed packages that
+// implement each named interface, by comparing method sets
+func (r *TypeRegistry) ResolveImplementations() {
+	// Collect all concrete types with methods
+	concrete := []*TypeDefinition{}
+	for _, pkgInfo := range r.Packages {
+		for _, typeDef := range pkgInfo.Types {
+			if typeDef.Kind != KindInterface && len(typeDef.Methods) > 0 {
+				concrete = append(concrete, typeDef)
+			}
+		}
+	}
+
+	for _, pkgInfo := range r.Packages {
+		for _, iface := range pkgInfo.Types {
+			// Every type implements the empty interface, so don't list them
+			if iface.Kind != KindInterface || len(iface.Methods) == 0 {
+				continue
+			}
+
+			iface.Implementations = nil
+			for _, typeDef := range concrete {
+				if implements(typeDef, iface) {
+					iface.Implementations = append(iface.Implementations, typeDef)
+				}
+			}
+
+			// Keep the implementations in a stable order
+			sort.Slice(iface.Implementations, func(i, j int) bool {
+				a, b := iface.Implementations[i], iface.Implementations[j]
+				return a.Package+"."+a.Name < b.Package+"."+b.Name
+			})
+
+			r.Log.Debugf("Interface %s.%s has %d implementations", iface.Package, iface.Name, len(iface.Implementations))
+		}
+	}
+}
+
+// implements checks if a type's method set contains all methods of an interface.
+// Methods with pointer receivers are included, since values stored in
+// interfaces are usually pointers.
+func implements(typeDef, iface *TypeDefinition) bool {
+	for name, signature := range iface.Methods {
+		if typeDef.Methods[name] != signature {
+			return false
+		}
+	}
+	return true
+}
+
+// methodSignature returns a method signature without parameter names, so
+// signatures from interfaces and method declarations can be compared
+func methodSignature(funcType *ast.FuncType) string {
+	return "(" + fieldTypes(funcType.Params) + ") (" + fieldTypes(funcType.Results) + ")"
+}
+
+// fieldTypes returns the comma-separated types of a parameter or result list
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+
+	typeNames := []string{}
+	for _, field := range fields.List {
+		typeName := types.ExprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return strings.Join(typeNames, ", ")
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// compositeLitType resolves the type of a composite literal. Maps from
+// strings to interface{} whose keys are all constant, such as
+// map[string]interface{}{"id": 1, "name": "John"}, and slices of them get
+// the keys as LiteralFields, typed after their values.
+func (t *VariableTracker) compositeLitType(lit *ast.CompositeLit) *TypeDefinition {
+	typeDef := t.Registry.ResolveTypeIn(t.Package, lit.Type)
+	if typeDef == nil {
+		return nil
+	}
+
+	switch {
+	case isLiteralMap(typeDef):
+		return t.mapLiteralType(typeDef, []*ast.CompositeLit{lit})
+	case typeDef.Kind == KindArray && isLiteralMap(typeDef.ElementType):
+		// Elements are map literals, usually with their type elided:
+		// []map[string]interface{}{{"id": 1}, {"id": 2}}
+		elements := []*ast.CompositeLit{}
+		for _, elt := range lit.Elts {
+			elemLit, ok := elt.(*ast.CompositeLit)
+			if !ok {
+				return typeDef
+			}
+			elements = append(elements, elemLit)
+		}
+		elemType := t.mapLiteralType(typeDef.ElementType, elements)
+		if elemType == typeDef.ElementType {
+			return typeDef
+		}
+		arrayType := *typeDef
+		arrayType.ElementType = elemType
+		return &arrayType
+	}
+	return typeDef
+}
+
+// isLiteralMap reports whether a type is a map from strings to the empty
+// interface, whose literals are documented by their keys
+func isLiteralMap(typeDef *TypeDefinition) bool {
+	return typeDef != nil && typeDef.Kind == KindMap &&
+		typeDef.KeyType != nil && typeDef.KeyType.Name == "string" &&
+		isEmptyInterface(typeDef.ValueType)
+}
+
+// isEmptyInterface reports whether a type is interface{} or any
+func isEmptyInterface(typeDef *TypeDefinition) bool {
+	return typeDef != nil && typeDef.Kind == KindInterface &&
+		len(typeDef.Methods) == 0 && len(typeDef.Implementations) == 0
+}
+
+// mapLiteralType returns a copy of a map type with the keys of its literals
+// as LiteralFields, merged across literals with the first value's type
+// winning. The map type is returned as is if a key isn't a string literal.
+func (t *VariableTracker) mapLiteralType(mapType *TypeDefinition, lits []*ast.CompositeLit) *TypeDefinition {
+	fields := []*FieldDefinition{}
+	seen := make(map[string]bool)
+	for _, lit := range lits {
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return mapType
+			}
+			key, ok := kv.Key.(*ast.BasicLit)
+			if !ok || key.Kind != token.STRING {
+				return mapType
+			}
+			name, err := strconv.Unquote(key.Value)
+			if err != nil {
+				return mapType
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			// Values of unknown type accept anything, like the map's values
+			valueType := t.literalValueType(kv.Value)
+			if valueType == nil || valueType.Kind == KindUnknown {
+				valueType = mapType.ValueType
+			}
+			fields = append(fields, &FieldDefinition{
+				Name:     name,
+				Type:     valueType,
+				JSONName: name,
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return mapType
+	}
+
+	literalType := *mapType
+	literalType.LiteralFields = fields
+	return &literalType
+}
+
+// literalValueType resolves the type of a value in a map literal, including
+// the predeclared true and false
+func (t *VariableTracker) literalValueType(expr ast.Expr) *TypeDefinition {
+	if ident, ok := expr.(*ast.Ident); ok && (ident.Name == "true" || ident.Name == "false") && t.variable(ident) == nil {
+		return &TypeDefinition{
+			Name:       "bool",
+			Kind:       KindBasic,
+			BasicType:  "bool",
+			IsResolved: true,
+		}
+	}
+	return t.resolveExpressionType(expr)
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/json"
+	"go/ast"
+	"strings"
+)
+
+// marshalJSONSignature is the signature of the MarshalJSON method of
+// json.Marshaler, as returned by methodSignature
+const marshalJSONSignature = "() ([]byte, error)"
+
+// CustomMarshalerDescription notes that a schema was generated from the
+// fields of a type whose JSON encoding is written by its own method
+const CustomMarshalerDescription = "serialized by a custom MarshalJSON method; this schema is derived from its fields and is best-effort"
+
+// schemaOverride parses the schema set by a "schema:" line in the doc
+// comment of a type declaration, e.g.
+//
+//	// schema: {"type": "string", "format": "date"}
+//
+// It returns nil if the comment has no such line.
+func schemaOverride(doc *ast.CommentGroup) (*JSONSchema, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "schema:") {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(line, "schema:"))
+		schema := &JSONSchema{}
+		if err := json.Unmarshal([]byte(value), schema); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, nil
+}
+
+// noteCustomMarshaler adds CustomMarshalerDescription to the description of
+// the schema generated for a type with a custom MarshalJSON method
+func noteCustomMarshaler(typeDef *TypeDefinition, schema *JSONSchema) {
+	if schema == nil || !typeDef.HasCustomMarshaler {
+		return
+	}
+	if schema.Description == "" {
+		schema.Description = CustomMarshalerDescription
+	} else {
+		schema.Description += " (" + CustomMarshalerDescription + ")"
+	}
+}
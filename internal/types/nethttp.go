@@ -0,0 +1,266 @@
+package types
+
+import "go/ast"
+
+// NetHTTPDetectors creates a Detectors registry with the detectors of the
+// net/http calls writing a response from a handler with the signature
+// func(w http.ResponseWriter, r *http.Request). Writes through the
+// ResponseWriter take their status from the w.WriteHeader call before them,
+// which is only known from the statements around the call, so the functions
+// of the files are scanned for them up front.
+func NetHTTPDetectors(files []*ast.File) *Detectors {
+	d := NewDetectors()
+	RegisterNetHTTPResponseDetectors(d, CollectResponseWrites(files))
+	return d
+}
+
+// RegisterNetHTTPResponseDetectors registers the detectors of the net/http
+// calls writing a response: the writes collected by CollectResponseWrites,
+// and http.Error, http.Redirect and http.ServeFile
+func RegisterNetHTTPResponseDetectors(d *Detectors, writes map[*ast.CallExpr]ResponseMatch) {
+	written := ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+		match, ok := writes[call]
+		return match, ok
+	})
+	for _, name := range []string{"Encode", "Write", "WriteHeader", "Fprint", "Fprintf", "Fprintln", "WriteString"} {
+		d.RegisterResponse(name, written)
+	}
+
+	// http.Error(w, "not found", http.StatusNotFound)
+	d.RegisterResponse("Error", ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+		if !isHTTPCall(call) || len(call.Args) != 3 {
+			return ResponseMatch{}, false
+		}
+		return ResponseMatch{Type: "String", Status: call.Args[2], Payload: call.Args[1]}, true
+	}))
+
+	// http.Redirect(w, r, "/new-url", http.StatusFound)
+	d.RegisterResponse("Redirect", ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+		if !isHTTPCall(call) || len(call.Args) != 4 {
+			return ResponseMatch{}, false
+		}
+		return ResponseMatch{Type: "Redirect", Status: call.Args[3]}, true
+	}))
+
+	// http.ServeFile(w, r, "path/to/file")
+	d.RegisterResponse("ServeFile", ResponseDetectorFunc(func(call *ast.CallExpr) (ResponseMatch, bool) {
+		if !isHTTPCall(call) || len(call.Args) != 3 {
+			return ResponseMatch{}, false
+		}
+		return ResponseMatch{Type: "File", ContentType: call.Args[2]}, true
+	}))
+}
+
+// isHTTPCall checks if a call is a function call on the net/http package
+func isHTTPCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "http"
+}
+
+// CollectResponseWrites finds the responses written through the
+// http.ResponseWriter parameter of the functions declared in files:
+//   - json.NewEncoder(w).Encode(v) writes a JSON response
+//   - w.Write(b) writes bytes, documented as a blob
+//   - fmt.Fprintf(w, ...) and io.WriteString(w, s) write text
+//   - w.WriteHeader(code) not followed by a write in its block sends no body
+//
+// A write has the status of the last w.WriteHeader call before it in its
+// block or the blocks enclosing it, and 200 if there is none.
+func CollectResponseWrites(files []*ast.File) map[*ast.CallExpr]ResponseMatch {
+	writes := make(map[*ast.CallExpr]ResponseMatch)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			var funcType *ast.FuncType
+			var body *ast.BlockStmt
+			switch fn := n.(type) {
+			case *ast.FuncDecl:
+				funcType, body = fn.Type, fn.Body
+			case *ast.FuncLit:
+				funcType, body = fn.Type, fn.Body
+			default:
+				return true
+			}
+			writers := responseWriterParams(funcType)
+			if len(writers) > 0 && body != nil {
+				c := &responseWriteCollector{writers: writers, encoders: make(map[string]bool), writes: writes}
+				c.collectBlock(body.List, nil)
+			}
+			return true
+		})
+	}
+	return writes
+}
+
+// responseWriterParams returns the names of the parameters of type
+// http.ResponseWriter
+func responseWriterParams(funcType *ast.FuncType) map[string]bool {
+	writers := make(map[string]bool)
+	if funcType.Params == nil {
+		return writers
+	}
+	for _, param := range funcType.Params.List {
+		sel, ok := param.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "ResponseWriter" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "http" {
+			continue
+		}
+		for _, name := range param.Names {
+			writers[name.Name] = true
+		}
+	}
+	return writers
+}
+
+// responseWriteCollector collects the responses written through the
+// ResponseWriter parameters of a function
+type responseWriteCollector struct {
+	writers  map[string]bool // Names of the ResponseWriter parameters
+	encoders map[string]bool // Variables holding a json.Encoder writing to one
+	writes   map[*ast.CallExpr]ResponseMatch
+}
+
+// collectBlock collects the writes of a list of statements, with the status
+// set by the enclosing blocks, nil if none is
+func (c *responseWriteCollector) collectBlock(stmts []ast.Stmt, status ast.Expr) {
+	for i, stmt := range stmts {
+		if expr, ok := stmt.(*ast.ExprStmt); ok {
+			if call, ok := expr.X.(*ast.CallExpr); ok && c.isWriterCall(call, "WriteHeader") && len(call.Args) == 1 {
+				status = call.Args[0]
+				if !c.writesBody(stmts[i+1:]) {
+					c.writes[call] = ResponseMatch{Type: "NoContent", Status: status}
+				}
+				continue
+			}
+		}
+
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.BlockStmt:
+				c.collectBlock(node.List, status)
+				return false
+			case *ast.CaseClause:
+				c.collectBlock(node.Body, status)
+				return false
+			case *ast.CommClause:
+				c.collectBlock(node.Body, status)
+				return false
+			case *ast.AssignStmt:
+				// enc := json.NewEncoder(w)
+				if len(node.Lhs) != len(node.Rhs) {
+					return true
+				}
+				for j, rhs := range node.Rhs {
+					if ident, ok := node.Lhs[j].(*ast.Ident); ok && c.isNewEncoder(rhs) {
+						c.encoders[ident.Name] = true
+					}
+				}
+			case *ast.CallExpr:
+				if payload := c.encodedValue(node); payload != nil {
+					c.writes[node] = ResponseMatch{Type: "JSON", Status: status, Payload: payload}
+				} else if c.isWriterCall(node, "Write") && len(node.Args) == 1 {
+					c.writes[node] = ResponseMatch{Type: "Blob", Status: status, Payload: node.Args[0], Encoded: true}
+				} else if c.writesText(node) {
+					c.writes[node] = ResponseMatch{Type: "String", Status: status, Payload: node.Args[len(node.Args)-1]}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// isWriterCall checks if a call is the method name called on a ResponseWriter
+func (c *responseWriteCollector) isWriterCall(call *ast.CallExpr, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && c.writers[ident.Name]
+}
+
+// isNewEncoder checks if an expression is json.NewEncoder(w) on a ResponseWriter
+func (c *responseWriteCollector) isNewEncoder(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "NewEncoder" {
+		return false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "json" {
+		return false
+	}
+	writer, ok := call.Args[0].(*ast.Ident)
+	return ok && c.writers[writer.Name]
+}
+
+// encodedValue returns the value encoded by json.NewEncoder(w).Encode(v) or
+// enc.Encode(v), or nil if the call doesn't encode to a ResponseWriter
+func (c *responseWriteCollector) encodedValue(call *ast.CallExpr) ast.Expr {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Encode" || len(call.Args) != 1 {
+		return nil
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok && c.encoders[ident.Name] {
+		return call.Args[0]
+	}
+	if c.isNewEncoder(sel.X) {
+		return call.Args[0]
+	}
+	return nil
+}
+
+// writesText checks if a call writes text to a ResponseWriter with
+// fmt.Fprint, fmt.Fprintf, fmt.Fprintln or io.WriteString
+func (c *responseWriteCollector) writesText(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) < 2 {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	switch {
+	case pkg.Name == "fmt" && (sel.Sel.Name == "Fprint" || sel.Sel.Name == "Fprintf" || sel.Sel.Name == "Fprintln"):
+	case pkg.Name == "io" && sel.Sel.Name == "WriteString":
+	default:
+		return false
+	}
+	writer, ok := call.Args[0].(*ast.Ident)
+	return ok && c.writers[writer.Name]
+}
+
+// writesBody checks if statements write a response body: through an
+// encoder or a method of the ResponseWriter, or by passing the
+// ResponseWriter to a function, as in fmt.Fprintf(w, ...)
+func (c *responseWriteCollector) writesBody(stmts []ast.Stmt) bool {
+	found := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || found {
+				return !found
+			}
+			if c.encodedValue(call) != nil || c.isWriterCall(call, "Write") || c.isWriterCall(call, "WriteString") {
+				found = true
+			}
+			for _, arg := range call.Args {
+				if ident, ok := arg.(*ast.Ident); ok && c.writers[ident.Name] {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // TypeKind represents the kind of a type
@@ -16,6 +21,8 @@ const (
 	KindMap
 	KindBasic
 	KindPointer
+	KindInterface
+	KindUnknown // Type that can't be statically determined
 )
 
 // TypeDefinition represents a Go type definition
@@ -28,16 +35,78 @@ type TypeDefinition struct {
 	ValueType   *TypeDefinition    // For maps
 	Package     string             // Package path
 	BasicType   string             // For basic types (string, int, etc.)
-	IsResolved  bool               // Whether the type has been fully resolved
+	IsResolved  bool               // Whether the field, element, key and value types were resolved by the TypeCollector
+	TypeParams  []string           // Type parameter names for generic types
+	XMLName     string             // Root element name from an XMLName field's xml tag
+	Service     string             // Service the type belongs to, when several repositories are merged
+
+	// HasCustomMarshaler reports whether the type has a MarshalJSON method,
+	// so its JSON encoding may not follow its fields
+	HasCustomMarshaler bool
+
+	// Schema overrides the generated schema, if set with a "schema:" line
+	// in the doc comment of the type declaration
+	Schema *JSONSchema
+
+	// Methods maps method names to signatures, for interfaces and for
+	// concrete types with methods declared in the analyzed code
+	Methods map[string]string
+
+	// Implementations lists the concrete types implementing an interface
+	Implementations []*TypeDefinition
+
+	// LiteralFields lists the keys of a map[string]interface{} composite
+	// literal with constant keys, typed after their values, so the map is
+	// documented as an object with these properties
+	LiteralFields []*FieldDefinition
+
+	// embedded holds embedded interface expressions until they are merged
+	embedded []ast.Expr
+
+	// methodResults maps method names to their first result type
+	// expression, for the methods of interfaces and of concrete types
+	methodResults map[string]ast.Expr
+
+	// typeExpr is the underlying array or map type expression of a declared
+	// type, kept so its element types can be resolved once all types are collected
+	typeExpr ast.Expr
+
+	// Each resolution pass marks the types it has visited with its own
+	// flag, so a type visited by one pass isn't skipped by the others:
+	// FieldsResolved by the PackageResolver, which fills in the fields
+	// referring to types registered late, and NestedAnalyzed by the
+	// StructFieldAnalyzer
+	FieldsResolved bool
+	NestedAnalyzed bool
 }
 
 // FieldDefinition represents a field in a struct
 type FieldDefinition struct {
-	Name      string
-	Type      *TypeDefinition
-	JSONName  string
-	Omitempty bool
-	IsPointer bool
+	Name        string
+	Type        *TypeDefinition
+	JSONName    string
+	Omitempty   bool
+	IsPointer   bool
+	Deprecated  bool
+	Description string // Field doc comment
+	Validate    string // Validation rules from the validate tag
+	Example     string // Example value from the example tag, as written
+	XMLName     string // Element or attribute name from the xml tag; "-" if skipped
+	XMLAttr     bool   // Whether the xml tag encodes the field as an attribute
+
+	// XMLOmitempty reports whether the xml tag has the omitempty option
+	XMLOmitempty bool
+
+	// JSONTagged reports whether the field has a JSON tag
+	JSONTagged bool
+
+	// Bind maps the sources Echo's binder fills the field from (query,
+	// param or header) to the name given by the field's binding tag
+	Bind map[string]string
+
+	// typeExpr is the field's type expression, kept so the type can be
+	// resolved once all types in the package have been collected
+	typeExpr ast.Expr
 }
 
 // PackageInfo represents information about a package
@@ -47,6 +116,9 @@ type PackageInfo struct {
 
 	// Map of import alias to package path
 	Imports map[string]string
+
+	// Map of function name to its first result type expression
+	Functions map[string]ast.Expr
 }
 
 // TypeRegistry is a central repository for storing and retrieving type information
@@ -60,17 +132,29 @@ type TypeRegistry struct {
 	// FileSet for position information
 	FileSet *token.FileSet
 
-	// Verbose mode
-	Verbose bool
+	Log *logger.Logger
+
+	// JSONTagKeys are the struct tag keys naming a field in JSON, consulted
+	// in order, e.g. json then a tag of an alternate JSON library. The first
+	// key with a value on a field wins. If empty, only json is consulted.
+	JSONTagKeys []string
+
+	// typeArgs maps type parameter names to their arguments while the
+	// fields of a generic type are being resolved
+	typeArgs map[string]*TypeDefinition
+
+	// mu serializes lookups and resolution, which switch the current package
+	// and cache generic instantiations, so handlers can be analyzed concurrently
+	mu sync.Mutex
 }
 
 // NewTypeRegistry creates a new TypeRegistry
-func NewTypeRegistry(fset *token.FileSet, verbose bool) *TypeRegistry {
+func NewTypeRegistry(fset *token.FileSet, log *logger.Logger) *TypeRegistry {
 	return &TypeRegistry{
-		Packages:      make(map[string]*PackageInfo),
+		Packages:       make(map[string]*PackageInfo),
 		CurrentPackage: "",
-		FileSet:       fset,
-		Verbose:       verbose,
+		FileSet:        fset,
+		Log:            log,
 	}
 }
 
@@ -78,12 +162,11 @@ func NewTypeRegistry(fset *token.FileSet, verbose bool) *TypeRegistry {
 func (r *TypeRegistry) RegisterPackage(packagePath string) *PackageInfo {
 	if _, exists := r.Packages[packagePath]; !exists {
 		r.Packages[packagePath] = &PackageInfo{
-			Types:   make(map[string]*TypeDefinition),
-			Imports: make(map[string]string),
-		}
-		if r.Verbose {
-			fmt.Printf("Registered package: %s\n", packagePath)
+			Types:     make(map[string]*TypeDefinition),
+			Imports:   make(map[string]string),
+			Functions: make(map[string]ast.Expr),
 		}
+		r.Log.Debugf("Registered package: %s", packagePath)
 	}
 	return r.Packages[packagePath]
 }
@@ -98,22 +181,39 @@ func (r *TypeRegistry) SetCurrentPackage(packagePath string) {
 func (r *TypeRegistry) RegisterImport(alias, packagePath string) {
 	pkg := r.RegisterPackage(r.CurrentPackage)
 	pkg.Imports[alias] = packagePath
-	if r.Verbose {
-		fmt.Printf("Registered import: %s -> %s in package %s\n", alias, packagePath, r.CurrentPackage)
-	}
+	r.Log.Debugf("Registered import: %s -> %s in package %s", alias, packagePath, r.CurrentPackage)
 }
 
 // RegisterType registers a type with the current package
 func (r *TypeRegistry) RegisterType(typeDef *TypeDefinition) {
 	pkg := r.RegisterPackage(r.CurrentPackage)
 	pkg.Types[typeDef.Name] = typeDef
-	if r.Verbose {
-		fmt.Printf("Registered type: %s in package %s\n", typeDef.Name, r.CurrentPackage)
-	}
+	r.Log.Debugf("Registered type: %s in package %s", typeDef.Name, r.CurrentPackage)
 }
 
 // LookupType looks up a type by name in the current package
 func (r *TypeRegistry) LookupType(name string) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lookupType(name)
+}
+
+// LookupTypeIn looks up a type by name in a package, or in the current
+// package if packagePath is empty, without changing the current package
+func (r *TypeRegistry) LookupTypeIn(packagePath, name string) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var typeDef *TypeDefinition
+	r.inPackage(packagePath, func() {
+		typeDef = r.lookupType(name)
+	})
+	return typeDef
+}
+
+// lookupType looks up a type by name in the current package, with the lock held
+func (r *TypeRegistry) lookupType(name string) *TypeDefinition {
 	// Check if it's a qualified name (pkg.Type)
 	if strings.Contains(name, ".") {
 		parts := strings.SplitN(name, ".", 2)
@@ -142,14 +242,135 @@ func (r *TypeRegistry) LookupType(name string) *TypeDefinition {
 	return nil
 }
 
-// ResolveType resolves a type expression to a TypeDefinition
+// LookupFunctionReturnType returns the result type of a function declared in
+// the current package, or of a qualified function (pkg.Func) from an imported one
+func (r *TypeRegistry) LookupFunctionReturnType(name string) *TypeDefinition {
+	return r.LookupFunctionReturnTypeIn("", name)
+}
+
+// LookupFunctionReturnTypeIn returns the result type of a function declared
+// in a package, or of a qualified function from one of its imports. The
+// current package is used if packagePath is empty.
+func (r *TypeRegistry) LookupFunctionReturnTypeIn(packagePath, name string) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if packagePath == "" {
+		packagePath = r.CurrentPackage
+	}
+	if strings.Contains(name, ".") {
+		parts := strings.SplitN(name, ".", 2)
+		importPath, exists := r.RegisterPackage(packagePath).Imports[parts[0]]
+		if !exists {
+			return nil
+		}
+		packagePath, name = importPath, parts[1]
+	}
+
+	pkg, exists := r.Packages[packagePath]
+	if !exists {
+		return nil
+	}
+	resultExpr, exists := pkg.Functions[name]
+	if !exists {
+		return nil
+	}
+
+	// Resolve the result in the package that declares the function
+	var returnType *TypeDefinition
+	r.withTypeArgs(packagePath, nil, func() {
+		returnType = r.resolveType(resultExpr)
+	})
+	return returnType
+}
+
+// LookupMethodReturnType returns the result type of a method declared on a
+// type, looking through pointers to the receiver type
+func (r *TypeRegistry) LookupMethodReturnType(recv *TypeDefinition, method string) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for recv != nil && recv.Kind == KindPointer {
+		recv = recv.ElementType
+	}
+	if recv == nil {
+		return nil
+	}
+
+	resultExpr, exists := recv.methodResults[method]
+	if !exists {
+		return nil
+	}
+
+	// Resolve the result in the package that declares the receiver type
+	var returnType *TypeDefinition
+	r.withTypeArgs(recv.Package, nil, func() {
+		returnType = r.resolveType(resultExpr)
+	})
+	return returnType
+}
+
+// ResolveType resolves a type expression to a TypeDefinition. Like the other
+// lookups, it is safe for concurrent use.
 func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.resolveType(expr)
+}
+
+// ResolveTypeIn resolves a type expression in a package, or in the current
+// package if packagePath is empty, without changing the current package.
+// Handlers declared in different packages can so be analyzed concurrently.
+func (r *TypeRegistry) ResolveTypeIn(packagePath string, expr ast.Expr) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var typeDef *TypeDefinition
+	r.inPackage(packagePath, func() {
+		typeDef = r.resolveType(expr)
+	})
+	return typeDef
+}
+
+// inPackage runs fn with the current package switched to packagePath, if
+// it isn't empty, with the lock held
+func (r *TypeRegistry) inPackage(packagePath string, fn func()) {
+	if packagePath == "" || packagePath == r.CurrentPackage {
+		fn()
+		return
+	}
+	prevPackage := r.CurrentPackage
+	r.SetCurrentPackage(packagePath)
+	defer func() {
+		r.CurrentPackage = prevPackage
+	}()
+	fn()
+}
+
+// resolveType resolves a type expression to a TypeDefinition, with the lock held
+func (r *TypeRegistry) resolveType(expr ast.Expr) *TypeDefinition {
 	if expr == nil {
 		return nil
 	}
 
 	switch t := expr.(type) {
 	case *ast.Ident:
+		// Type parameter of the generic type being resolved
+		if typeArg, exists := r.typeArgs[t.Name]; exists {
+			return typeArg
+		}
+
+		// The predeclared any is the empty interface
+		if t.Name == "any" && r.lookupType(t.Name) == nil {
+			return anyType(r.CurrentPackage)
+		}
+
+		// The predeclared error is an interface any error value satisfies
+		if t.Name == "error" && r.lookupType(t.Name) == nil {
+			return errorType()
+		}
+
 		// Basic type or type defined in the current package
 		if isBasicType(t.Name) {
 			return &TypeDefinition{
@@ -160,18 +381,26 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 				IsResolved: true,
 			}
 		}
-		return r.LookupType(t.Name)
+		return r.lookupType(t.Name)
 
 	case *ast.SelectorExpr:
 		// Type from another package (pkg.Type)
 		if x, ok := t.X.(*ast.Ident); ok {
 			qualifiedName := x.Name + "." + t.Sel.Name
-			return r.LookupType(qualifiedName)
+			return r.lookupType(qualifiedName)
 		}
 
+	case *ast.IndexExpr:
+		// Generic instantiation with one type argument (Type[Arg])
+		return r.instantiate(t.X, []ast.Expr{t.Index})
+
+	case *ast.IndexListExpr:
+		// Generic instantiation with several type arguments (Type[A, B])
+		return r.instantiate(t.X, t.Indices)
+
 	case *ast.ArrayType:
 		// Array type ([]Type)
-		elemType := r.ResolveType(t.Elt)
+		elemType := r.resolveType(t.Elt)
 		if elemType != nil {
 			return &TypeDefinition{
 				Name:        fmt.Sprintf("[]%s", elemType.Name),
@@ -184,8 +413,8 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 
 	case *ast.MapType:
 		// Map type (map[KeyType]ValueType)
-		keyType := r.ResolveType(t.Key)
-		valueType := r.ResolveType(t.Value)
+		keyType := r.resolveType(t.Key)
+		valueType := r.resolveType(t.Value)
 		if keyType != nil && valueType != nil {
 			return &TypeDefinition{
 				Name:       fmt.Sprintf("map[%s]%s", keyType.Name, valueType.Name),
@@ -199,7 +428,7 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 
 	case *ast.StarExpr:
 		// Pointer type (*Type)
-		elemType := r.ResolveType(t.X)
+		elemType := r.resolveType(t.X)
 		if elemType != nil {
 			return &TypeDefinition{
 				Name:        fmt.Sprintf("*%s", elemType.Name),
@@ -210,6 +439,14 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 			}
 		}
 
+	case *ast.InterfaceType:
+		// Anonymous interface type (e.g. interface{})
+		typeDef := newInterfaceType("interface{}", r.CurrentPackage, t)
+		if len(typeDef.Methods) > 0 || len(typeDef.embedded) > 0 {
+			typeDef.Name = "interface"
+		}
+		return typeDef
+
 	case *ast.StructType:
 		// Anonymous struct type
 		structDef := &TypeDefinition{
@@ -218,12 +455,13 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 			Fields:     []*FieldDefinition{},
 			Package:    r.CurrentPackage,
 			IsResolved: true,
+			XMLName:    xmlRootName(t),
 		}
 
 		// Process struct fields
 		if t.Fields != nil {
 			for _, field := range t.Fields.List {
-				fieldType := r.ResolveType(field.Type)
+				fieldType := r.resolveType(field.Type)
 				if fieldType == nil {
 					structDef.IsResolved = false
 					continue
@@ -233,13 +471,23 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 				for _, name := range field.Names {
 					// Process JSON tags
 					jsonName, omitempty := r.extractJSONTag(field)
+					xmlName, xmlAttr, xmlOmitempty := extractXMLTag(field)
 
 					fieldDef := &FieldDefinition{
-						Name:      name.Name,
-						Type:      fieldType,
-						JSONName:  jsonName,
-						Omitempty: omitempty,
-						IsPointer: isPointerType(field.Type),
+						Name:         name.Name,
+						Type:         fieldType,
+						JSONName:     jsonName,
+						Omitempty:    omitempty,
+						IsPointer:    isPointerType(field.Type),
+						Deprecated:   isDeprecatedField(field),
+						Description:  fieldDescription(field),
+						Validate:     validateTag(field),
+						Example:      fieldTag(field, "example"),
+						XMLName:      xmlName,
+						XMLAttr:      xmlAttr,
+						XMLOmitempty: xmlOmitempty,
+						JSONTagged:   r.jsonTag(field) != "",
+						Bind:         bindTags(field),
 					}
 
 					structDef.Fields = append(structDef.Fields, fieldDef)
@@ -253,32 +501,219 @@ func (r *TypeRegistry) ResolveType(expr ast.Expr) *TypeDefinition {
 	return nil
 }
 
-// extractJSONTag extracts the JSON tag from a struct field
+// instantiate resolves a generic type instantiation by substituting the type
+// arguments into the fields of the generic type
+func (r *TypeRegistry) instantiate(genericExpr ast.Expr, argExprs []ast.Expr) *TypeDefinition {
+	generic := r.resolveType(genericExpr)
+	if generic == nil || len(generic.TypeParams) == 0 {
+		return generic
+	}
+
+	// Resolve the type arguments in the current scope
+	args := make([]*TypeDefinition, len(argExprs))
+	argNames := make([]string, len(argExprs))
+	for i, argExpr := range argExprs {
+		args[i] = r.resolveType(argExpr)
+		if args[i] == nil {
+			args[i] = anyType(r.CurrentPackage)
+		}
+		argNames[i] = args[i].Name
+	}
+
+	// Reuse an existing instantiation
+	name := fmt.Sprintf("%s[%s]", generic.Name, strings.Join(argNames, ", "))
+	pkg := r.RegisterPackage(generic.Package)
+	if typeDef, exists := pkg.Types[name]; exists {
+		return typeDef
+	}
+
+	typeDef := &TypeDefinition{
+		Name:       name,
+		Kind:       generic.Kind,
+		Package:    generic.Package,
+		IsResolved: true,
+	}
+	if generic.Kind != KindStruct {
+		typeDef.ElementType = generic.ElementType
+		typeDef.KeyType = generic.KeyType
+		typeDef.ValueType = generic.ValueType
+		typeDef.BasicType = generic.BasicType
+		return typeDef
+	}
+
+	// Register the instantiation before resolving fields so recursive types terminate
+	pkg.Types[name] = typeDef
+
+	// Resolve the fields in the generic type's package with the type parameters bound
+	typeArgs := make(map[string]*TypeDefinition)
+	for i, param := range generic.TypeParams {
+		if i < len(args) {
+			typeArgs[param] = args[i]
+		}
+	}
+	r.withTypeArgs(generic.Package, typeArgs, func() {
+		for _, field := range generic.Fields {
+			instField := *field
+			if field.typeExpr != nil {
+				instField.Type = r.resolveType(field.typeExpr)
+				if instField.Type == nil {
+					instField.Type = unresolvedFieldType(field.typeExpr, generic.Package)
+				}
+			}
+			typeDef.Fields = append(typeDef.Fields, &instField)
+		}
+	})
+
+	return typeDef
+}
+
+// WrapEnvelope returns the envelope type with its data field typed as the
+// payload, registered as Envelope[Payload] in the envelope's package
+func (r *TypeRegistry) WrapEnvelope(envelope *TypeDefinition, dataField string, payload *TypeDefinition) *TypeDefinition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Reuse an existing wrapping of the same payload
+	name := fmt.Sprintf("%s[%s]", envelope.Name, payload.Name)
+	pkg := r.RegisterPackage(envelope.Package)
+	if typeDef, exists := pkg.Types[name]; exists {
+		return typeDef
+	}
+
+	typeDef := *envelope
+	typeDef.Name = name
+	typeDef.Fields = make([]*FieldDefinition, len(envelope.Fields))
+	for i, field := range envelope.Fields {
+		wrappedField := *field
+		if field.Name == dataField {
+			wrappedField.Type = payload
+		}
+		typeDef.Fields[i] = &wrappedField
+	}
+	pkg.Types[name] = &typeDef
+
+	return &typeDef
+}
+
+// withTypeArgs runs fn in the given package with type parameters bound to
+// typeArgs, with the lock held
+func (r *TypeRegistry) withTypeArgs(packagePath string, typeArgs map[string]*TypeDefinition, fn func()) {
+	prevPackage, prevArgs := r.CurrentPackage, r.typeArgs
+	r.SetCurrentPackage(packagePath)
+	r.typeArgs = typeArgs
+	defer func() {
+		r.CurrentPackage, r.typeArgs = prevPackage, prevArgs
+	}()
+	fn()
+}
+
+// resolveFieldType resolves the type of a struct field from its AST node,
+// with the type parameters of a generic parent standing for any. It returns
+// nil if the field has no AST node or its type isn't registered.
+func (r *TypeRegistry) resolveFieldType(parent *TypeDefinition, field *FieldDefinition) *TypeDefinition {
+	if field.typeExpr == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	typeArgs := make(map[string]*TypeDefinition)
+	for _, param := range parent.TypeParams {
+		typeArgs[param] = anyType(parent.Package)
+	}
+
+	var fieldType *TypeDefinition
+	r.withTypeArgs(parent.Package, typeArgs, func() {
+		fieldType = r.resolveType(field.typeExpr)
+	})
+	return fieldType
+}
+
+// anyType returns the empty interface type, also used for unbound type parameters
+func anyType(packagePath string) *TypeDefinition {
+	return &TypeDefinition{
+		Name:       "any",
+		Kind:       KindInterface,
+		Package:    packagePath,
+		IsResolved: true,
+	}
+}
+
+// errorType returns the predeclared error interface. Its implementations
+// aren't resolved, since errors of any package can be returned, so like the
+// empty interface its schema accepts any value.
+func errorType() *TypeDefinition {
+	return &TypeDefinition{
+		Name:          "error",
+		Kind:          KindInterface,
+		Methods:       map[string]string{"Error": "() (string)"},
+		methodResults: map[string]ast.Expr{"Error": ast.NewIdent("string")},
+		IsResolved:    true,
+	}
+}
+
+// UnknownType returns the placeholder for a value whose type can't be
+// statically determined, such as a value read from an interface-typed store
+func UnknownType() *TypeDefinition {
+	return &TypeDefinition{
+		Name:       "unknown",
+		Kind:       KindUnknown,
+		IsResolved: true,
+	}
+}
+
+// typeParamNames returns the names of the type parameters in a type declaration
+func typeParamNames(typeSpec *ast.TypeSpec) []string {
+	if typeSpec.TypeParams == nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, field := range typeSpec.TypeParams.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// extractJSONTag extracts the JSON name of a struct field from the first
+// of the JSON tag keys set on it, and whether it has omitempty
 func (r *TypeRegistry) extractJSONTag(field *ast.Field) (string, bool) {
-	if field.Tag == nil {
-		return "", false
+	jsonName, omitempty, _ := parseJSONTag(r.jsonTag(field))
+
+	// If the JSON name is "-", the field is not exported to JSON
+	if jsonName == "-" {
+		return "", true
 	}
 
-	tagValue := field.Tag.Value
-	// Remove the backticks
-	tagValue = strings.Trim(tagValue, "`")
+	return jsonName, omitempty
+}
 
-	// Extract the json tag
-	jsonTag := ""
-	for _, tag := range strings.Split(tagValue, " ") {
-		if strings.HasPrefix(tag, "json:") {
-			jsonTag = strings.Trim(strings.TrimPrefix(tag, "json:"), "\"")
-			break
+// jsonTag returns the value of the first JSON tag key set in the tag of a
+// struct field, or "" if none is
+func (r *TypeRegistry) jsonTag(field *ast.Field) string {
+	keys := r.JSONTagKeys
+	if len(keys) == 0 {
+		keys = []string{"json"}
+	}
+	for _, key := range keys {
+		if value := fieldTag(field, key); value != "" {
+			return value
 		}
 	}
+	return ""
+}
 
+// parseJSONTag splits a JSON tag value into the field name and whether it
+// has omitempty. The last result is false if the tag is empty.
+func parseJSONTag(jsonTag string) (string, bool, bool) {
 	if jsonTag == "" {
-		return "", false
+		return "", false, false
 	}
 
-	// Check for omitempty
 	parts := strings.Split(jsonTag, ",")
-	jsonName := parts[0]
 	omitempty := false
 	for _, part := range parts[1:] {
 		if part == "omitempty" {
@@ -286,13 +721,99 @@ func (r *TypeRegistry) extractJSONTag(field *ast.Field) (string, bool) {
 			break
 		}
 	}
+	return parts[0], omitempty, true
+}
 
-	// If the JSON name is "-", the field is not exported to JSON
-	if jsonName == "-" {
-		return "", true
+// extractXMLTag extracts the element name from the xml tag of a struct field,
+// and whether the field is encoded as an attribute and has omitempty. Nested
+// element paths such as "items>item" are reduced to their outermost element.
+func extractXMLTag(field *ast.Field) (string, bool, bool) {
+	xmlTag := fieldTag(field, "xml")
+	if xmlTag == "" {
+		return "", false, false
 	}
 
-	return jsonName, omitempty
+	parts := strings.Split(xmlTag, ",")
+	xmlName := parts[0]
+	if i := strings.Index(xmlName, ">"); i >= 0 {
+		xmlName = xmlName[:i]
+	}
+	attr, omitempty := false, false
+	for _, part := range parts[1:] {
+		switch part {
+		case "attr":
+			attr = true
+		case "omitempty":
+			omitempty = true
+		}
+	}
+
+	return xmlName, attr, omitempty
+}
+
+// xmlRootName returns the element name of a struct from the xml tag of its
+// XMLName field, e.g. XMLName xml.Name `xml:"user"`
+func xmlRootName(structType *ast.StructType) string {
+	if structType.Fields == nil {
+		return ""
+	}
+	for _, field := range structType.Fields.List {
+		for _, name := range field.Names {
+			if name.Name == "XMLName" {
+				xmlName, _, _ := extractXMLTag(field)
+				return xmlName
+			}
+		}
+	}
+	return ""
+}
+
+// fieldDescription returns the description of a struct field: its
+// description tag, or else its doc comment or line comment, as a single line
+func fieldDescription(field *ast.Field) string {
+	if description := fieldTag(field, "description"); description != "" {
+		return description
+	}
+
+	cg := field.Doc
+	if cg == nil {
+		cg = field.Comment
+	}
+	if cg == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(cg.Text()), " ")
+}
+
+// fieldTag returns the value of a key in the tag of a struct field,
+// following reflect.StructTag, so quoted values may contain spaces
+func fieldTag(field *ast.Field, key string) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get(key)
+}
+
+// isDeprecatedField checks if a struct field is marked as deprecated, either
+// through a "Deprecated:" doc comment or a `deprecated:"true"` struct tag
+func isDeprecatedField(field *ast.Field) bool {
+	for _, cg := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if cg == nil {
+			continue
+		}
+		for _, line := range strings.Split(cg.Text(), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "Deprecated:") {
+				return true
+			}
+		}
+	}
+
+	value := fieldTag(field, "deprecated")
+	return value == "true" || value == "1"
 }
 
 // isBasicType checks if a type name is a basic Go type
@@ -317,7 +838,6 @@ func isBasicType(name string) bool {
 		"string":     true,
 		"byte":       true,
 		"rune":       true,
-		"error":      true,
 	}
 	return basicTypes[name]
 }
@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // PackageResolver handles cross-package type resolution
@@ -15,24 +17,22 @@ type PackageResolver struct {
 	Registry       *TypeRegistry
 	RootPath       string
 	ParsedPackages map[string]bool
-	Verbose        bool
+	Log            *logger.Logger
 }
 
 // NewPackageResolver creates a new PackageResolver
-func NewPackageResolver(registry *TypeRegistry, rootPath string, verbose bool) *PackageResolver {
+func NewPackageResolver(registry *TypeRegistry, rootPath string, log *logger.Logger) *PackageResolver {
 	return &PackageResolver{
 		Registry:       registry,
 		RootPath:       rootPath,
 		ParsedPackages: make(map[string]bool),
-		Verbose:        verbose,
+		Log:            log,
 	}
 }
 
 // ResolvePackages resolves types across packages
 func (r *PackageResolver) ResolvePackages() error {
-	if r.Verbose {
-		fmt.Println("Resolving types across packages...")
-	}
+	r.Log.Debugf("Resolving types across packages...")
 
 	// First, build a dependency graph of packages
 	dependencies := r.buildPackageDependencies()
@@ -54,10 +54,10 @@ func (r *PackageResolver) buildPackageDependencies() map[string][]string {
 	for pkgPath, pkgInfo := range r.Registry.Packages {
 		deps := []string{}
 
-		// Add dependencies from imports
+		// Add dependencies from imports of analyzed packages, which are
+		// registered under their import paths
 		for _, importPath := range pkgInfo.Imports {
-			// Skip standard library imports
-			if !strings.Contains(importPath, ".") {
+			if _, exists := r.Registry.Packages[importPath]; !exists {
 				continue
 			}
 			deps = append(deps, importPath)
@@ -90,9 +90,7 @@ func (r *PackageResolver) resolvePackageDependencies(pkgPath string, dependencie
 
 // resolvePackageTypes resolves types in a package
 func (r *PackageResolver) resolvePackageTypes(pkgPath string) {
-	if r.Verbose {
-		fmt.Printf("Resolving types in package: %s\n", pkgPath)
-	}
+	r.Log.Debugf("Resolving types in package: %s", pkgPath)
 
 	// Set the current package
 	r.Registry.SetCurrentPackage(pkgPath)
@@ -109,19 +107,24 @@ func (r *PackageResolver) resolvePackageTypes(pkgPath string) {
 	}
 }
 
-// resolveType resolves a type definition
+// resolveType resolves the fields of a type definition, and of the types it
+// refers to. Types are marked when visited, so recursive types end the
+// recursion, independently of the other resolution passes.
 func (r *PackageResolver) resolveType(typeDef *TypeDefinition) {
-	// Skip already resolved types
-	if typeDef.IsResolved {
+	// Skip types this pass already visited
+	if typeDef.FieldsResolved {
 		return
 	}
+	typeDef.FieldsResolved = true
 
-	if r.Verbose {
-		fmt.Printf("  Resolving type: %s\n", typeDef.Name)
-	}
+	r.Log.Debugf("  Resolving type: %s", typeDef.Name)
 
 	switch typeDef.Kind {
 	case KindStruct:
+		// Fields of a type declared in another file of the same package
+		// may have been collected before that type was registered
+		r.resolveMissingFieldTypes(typeDef)
+
 		// Resolve field types
 		for _, field := range typeDef.Fields {
 			if field.Type == nil {
@@ -151,8 +154,16 @@ func (r *PackageResolver) resolveType(typeDef *TypeDefinition) {
 			r.resolveType(typeDef.ElementType)
 		}
 	}
+}
 
-	typeDef.IsResolved = true
+// resolveMissingFieldTypes resolves the struct fields left without a type
+// from their AST nodes, now that all types in the package are registered
+func (r *PackageResolver) resolveMissingFieldTypes(typeDef *TypeDefinition) {
+	for _, field := range typeDef.Fields {
+		if field.Type == nil {
+			field.Type = r.Registry.resolveFieldType(typeDef, field)
+		}
+	}
 }
 
 // ScanPackage scans a package for types
@@ -165,9 +176,7 @@ func (r *PackageResolver) ScanPackage(packagePath string) error {
 	// Mark as parsed
 	r.ParsedPackages[packagePath] = true
 
-	if r.Verbose {
-		fmt.Printf("Scanning package: %s\n", packagePath)
-	}
+	r.Log.Debugf("Scanning package: %s", packagePath)
 
 	// Convert package path to directory path
 	dirPath := filepath.Join(r.RootPath, packagePath)
@@ -207,6 +216,15 @@ func (r *PackageResolver) ScanPackage(packagePath string) error {
 		}
 	}
 
+	// Resolve fields referring to types declared in a later file
+	if pkgInfo, exists := r.Registry.Packages[packagePath]; exists {
+		for _, typeDef := range pkgInfo.Types {
+			if typeDef.Kind == KindStruct {
+				r.resolveMissingFieldTypes(typeDef)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -275,6 +293,8 @@ func (r *PackageResolver) processTypeDeclaration(typeSpec *ast.TypeSpec, package
 			Fields:     []*FieldDefinition{},
 			Package:    packagePath,
 			IsResolved: false,
+			TypeParams: typeParamNames(typeSpec),
+			XMLName:    xmlRootName(structType),
 		}
 
 		// Register the type
@@ -287,14 +307,25 @@ func (r *PackageResolver) processTypeDeclaration(typeSpec *ast.TypeSpec, package
 				for _, name := range field.Names {
 					// Process JSON tags
 					jsonName, omitempty := r.Registry.extractJSONTag(field)
+					xmlName, xmlAttr, xmlOmitempty := extractXMLTag(field)
 
 					// Create a field definition
 					fieldDef := &FieldDefinition{
-						Name:      name.Name,
-						Type:      r.Registry.ResolveType(field.Type),
-						JSONName:  jsonName,
-						Omitempty: omitempty,
-						IsPointer: isPointerType(field.Type),
+						Name:         name.Name,
+						Type:         r.Registry.ResolveType(field.Type),
+						JSONName:     jsonName,
+						Omitempty:    omitempty,
+						IsPointer:    isPointerType(field.Type),
+						Deprecated:   isDeprecatedField(field),
+						Description:  fieldDescription(field),
+						Validate:     validateTag(field),
+						Example:      fieldTag(field, "example"),
+						XMLName:      xmlName,
+						XMLAttr:      xmlAttr,
+						XMLOmitempty: xmlOmitempty,
+						JSONTagged:   r.Registry.jsonTag(field) != "",
+						Bind:         bindTags(field),
+						typeExpr:     field.Type,
 					}
 
 					typeDef.Fields = append(typeDef.Fields, fieldDef)
@@ -302,9 +333,7 @@ func (r *PackageResolver) processTypeDeclaration(typeSpec *ast.TypeSpec, package
 			}
 		}
 
-		if r.Verbose {
-			fmt.Printf("  Collected struct type: %s with %d fields\n", typeName, len(typeDef.Fields))
-		}
+		r.Log.Debugf("  Collected struct type: %s with %d fields", typeName, len(typeDef.Fields))
 		return
 	}
 
@@ -314,9 +343,7 @@ func (r *PackageResolver) processTypeDeclaration(typeSpec *ast.TypeSpec, package
 
 // ResolveImportedTypes resolves types imported from other packages
 func (r *PackageResolver) ResolveImportedTypes() error {
-	if r.Verbose {
-		fmt.Println("Resolving imported types...")
-	}
+	r.Log.Debugf("Resolving imported types...")
 
 	// Iterate through all packages
 	for pkgPath, pkgInfo := range r.Registry.Packages {
@@ -325,7 +352,7 @@ func (r *PackageResolver) ResolveImportedTypes() error {
 
 		// Resolve imported types in this package
 		for typeName, typeDef := range pkgInfo.Types {
-			if !typeDef.IsResolved {
+			if !typeDef.FieldsResolved {
 				r.resolveImportedType(typeDef, pkgPath, typeName)
 			}
 		}
@@ -336,14 +363,13 @@ func (r *PackageResolver) ResolveImportedTypes() error {
 
 // resolveImportedType resolves a type that might be imported from another package
 func (r *PackageResolver) resolveImportedType(typeDef *TypeDefinition, pkgPath, typeName string) {
-	if r.Verbose {
-		fmt.Printf("  Resolving imported type: %s.%s\n", pkgPath, typeName)
-	}
+	r.Log.Debugf("  Resolving imported type: %s.%s", pkgPath, typeName)
 
-	// Skip already resolved types
-	if typeDef.IsResolved {
+	// Skip types this pass already visited
+	if typeDef.FieldsResolved {
 		return
 	}
+	typeDef.FieldsResolved = true
 
 	// Get package info
 	pkgInfo, exists := r.Registry.Packages[pkgPath]
@@ -362,7 +388,7 @@ func (r *PackageResolver) resolveImportedType(typeDef *TypeDefinition, pkgPath,
 				if fieldType != nil {
 					field.Type = fieldType
 				}
-			} else if !field.Type.IsResolved {
+			} else if !field.Type.FieldsResolved {
 				// Recursively resolve the field type
 				r.resolveImportedType(field.Type, field.Type.Package, field.Type.Name)
 			}
@@ -370,27 +396,25 @@ func (r *PackageResolver) resolveImportedType(typeDef *TypeDefinition, pkgPath,
 
 	case KindArray:
 		// Resolve element type
-		if typeDef.ElementType != nil && !typeDef.ElementType.IsResolved {
+		if typeDef.ElementType != nil && !typeDef.ElementType.FieldsResolved {
 			r.resolveImportedType(typeDef.ElementType, typeDef.ElementType.Package, typeDef.ElementType.Name)
 		}
 
 	case KindMap:
 		// Resolve key and value types
-		if typeDef.KeyType != nil && !typeDef.KeyType.IsResolved {
+		if typeDef.KeyType != nil && !typeDef.KeyType.FieldsResolved {
 			r.resolveImportedType(typeDef.KeyType, typeDef.KeyType.Package, typeDef.KeyType.Name)
 		}
-		if typeDef.ValueType != nil && !typeDef.ValueType.IsResolved {
+		if typeDef.ValueType != nil && !typeDef.ValueType.FieldsResolved {
 			r.resolveImportedType(typeDef.ValueType, typeDef.ValueType.Package, typeDef.ValueType.Name)
 		}
 
 	case KindPointer:
 		// Resolve element type
-		if typeDef.ElementType != nil && !typeDef.ElementType.IsResolved {
+		if typeDef.ElementType != nil && !typeDef.ElementType.FieldsResolved {
 			r.resolveImportedType(typeDef.ElementType, typeDef.ElementType.Package, typeDef.ElementType.Name)
 		}
 	}
-
-	typeDef.IsResolved = true
 }
 
 // findImportedType finds a type imported from another package
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sync"
+	"testing"
+)
+
+// resolutionSources are two packages whose field types refer to types of
+// the same package and of the other one, collected after the referring one
+var resolutionSources = map[string]string{
+	"example.com/app/api": `package api
+
+import "example.com/app/models"
+
+type Order struct {
+	ID              int
+	Items           []OrderItem
+	ShippingAddress models.Address
+	Owner           *models.User
+}
+
+type OrderItem struct {
+	ProductID int
+	Product   models.Product
+}
+`,
+	"example.com/app/models": `package models
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type User struct {
+	Name    string
+	Address Address
+}
+
+type Product struct {
+	Name string
+}
+`,
+}
+
+// resolvePasses collects the types of resolutionSources and runs the
+// resolution passes in the given order, returning the registry
+func resolvePasses(t *testing.T, order []string) *TypeRegistry {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	registry := NewTypeRegistry(fset, nil)
+	collector := NewTypeCollector(registry, nil)
+	for _, pkgPath := range []string{"example.com/app/api", "example.com/app/models"} {
+		file, err := parser.ParseFile(fset, pkgPath+"/types.go", resolutionSources[pkgPath], parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := collector.CollectTypes([]*ast.File{file}, pkgPath); err != nil {
+			t.Fatalf("CollectTypes(%s) = %v", pkgPath, err)
+		}
+	}
+
+	fieldAnalyzer := NewStructFieldAnalyzer(registry, nil)
+	passes := map[string]func() error{
+		"collector": collector.ResolveTypes,
+		"resolver":  NewPackageResolver(registry, "", nil).ResolvePackages,
+		"fields": func() error {
+			err := fieldAnalyzer.AnalyzeStructFields()
+			fieldAnalyzer.AnalyzeNestedStructs()
+			return err
+		},
+	}
+	for _, pass := range order {
+		if err := passes[pass](); err != nil {
+			t.Fatalf("%s pass = %v", pass, err)
+		}
+	}
+	return registry
+}
+
+// assertConcrete fails the test unless a field of a struct has a struct type
+// with fields, looking through pointers and slices
+func assertConcrete(t *testing.T, passes []string, parent *TypeDefinition, name, want string) {
+	t.Helper()
+
+	var fieldType *TypeDefinition
+	for _, field := range parent.Fields {
+		if field.Name == name {
+			fieldType = field.Type
+		}
+	}
+	for fieldType != nil && (fieldType.Kind == KindPointer || fieldType.Kind == KindArray) {
+		fieldType = fieldType.ElementType
+	}
+	if fieldType == nil || fieldType.Name != want || fieldType.Kind != KindStruct || len(fieldType.Fields) == 0 {
+		t.Errorf("passes %v: %s.%s = %+v, want the %s struct", passes, parent.Name, name, fieldType, want)
+	}
+}
+
+func TestFieldTypesAreConcreteInAnyPassOrder(t *testing.T) {
+	orders := [][]string{
+		{"collector", "resolver", "fields"},
+		{"collector", "fields", "resolver"},
+		{"resolver", "collector", "fields"},
+		{"resolver", "fields", "collector"},
+		{"fields", "collector", "resolver"},
+		{"fields", "resolver", "collector"},
+	}
+	for _, passes := range orders {
+		registry := resolvePasses(t, passes)
+
+		order := registry.Packages["example.com/app/api"].Types["Order"]
+		if order == nil {
+			t.Fatalf("Order isn't registered")
+		}
+		assertConcrete(t, passes, order, "Items", "OrderItem")
+		assertConcrete(t, passes, order, "ShippingAddress", "Address")
+		assertConcrete(t, passes, order, "Owner", "User")
+
+		item := registry.Packages["example.com/app/api"].Types["OrderItem"]
+		assertConcrete(t, passes, item, "Product", "Product")
+
+		user := registry.Packages["example.com/app/models"].Types["User"]
+		assertConcrete(t, passes, user, "Address", "Address")
+	}
+}
+
+func TestResolveFieldTypeConcurrently(t *testing.T) {
+	registry := resolvePasses(t, []string{"collector", "resolver", "fields"})
+	order := registry.Packages["example.com/app/api"].Types["Order"]
+
+	// Handlers of different packages are analyzed concurrently, resolving
+	// types in their own package while fields are resolved in another
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if user := registry.ResolveTypeIn("example.com/app/models", ast.NewIdent("User")); user == nil || user.Name != "User" {
+				t.Errorf("ResolveTypeIn(models, User) = %+v, want User", user)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, field := range order.Fields {
+				if field.Name == "Items" {
+					if items := registry.resolveFieldType(order, field); items == nil || items.Kind != KindArray {
+						t.Errorf("resolveFieldType(Order.Items) = %+v, want []OrderItem", items)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -4,40 +4,82 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
-// ResponseInfo represents information about a JSON response
+// ResponseInfo represents information about a JSON or XML response, or
+// the model of a rendered template
 type ResponseInfo struct {
 	StatusCode int
 	Type       *TypeDefinition
 	Position   string
+	Template   string // Name of the template the value is rendered with, if any
 }
 
-// ResponseAnalyzer analyzes Echo response methods to extract JSON response formats
+// ResponseAnalyzer analyzes Echo response methods to extract JSON and XML response formats
 type ResponseAnalyzer struct {
 	Registry        *TypeRegistry
 	VariableTracker *VariableTracker
 	Responses       []*ResponseInfo
-	Verbose         bool
+	Log             *logger.Logger
+
+	// StatusConstants maps names declared with a status code value to the code
+	StatusConstants map[string]int
+
+	// Envelope is the response wrapper type whose data field is documented
+	// with the payload of each response, if set
+	Envelope *Envelope
+
+	// Detectors match the calls writing a response, the echo.Context
+	// methods unless replaced
+	Detectors *Detectors
+}
+
+// Envelope describes a response wrapper type, such as
+// struct { Data interface{}; Meta Meta }, applied to every response
+type Envelope struct {
+	TypeName  string // Type name, optionally qualified by its package name
+	DataField string // Go name of the field holding the payload
+}
+
+// ParseEnvelope parses a response envelope of the form Type.Field
+// (e.g. Envelope.Data or api.Envelope.Data)
+func ParseEnvelope(spec string) (*Envelope, error) {
+	i := strings.LastIndex(spec, ".")
+	if i <= 0 || i == len(spec)-1 {
+		return nil, fmt.Errorf("invalid envelope %q, expected Type.Field", spec)
+	}
+	return &Envelope{TypeName: spec[:i], DataField: spec[i+1:]}, nil
+}
+
+// Matches reports whether a type is the envelope type
+func (e *Envelope) Matches(typeDef *TypeDefinition) bool {
+	if typeDef == nil || typeDef.Kind != KindStruct {
+		return false
+	}
+	return typeDef.Name == e.TypeName || path.Base(typeDef.Package)+"."+typeDef.Name == e.TypeName
 }
 
 // NewResponseAnalyzer creates a new ResponseAnalyzer
-func NewResponseAnalyzer(registry *TypeRegistry, variableTracker *VariableTracker, verbose bool) *ResponseAnalyzer {
+func NewResponseAnalyzer(registry *TypeRegistry, variableTracker *VariableTracker, log *logger.Logger) *ResponseAnalyzer {
 	return &ResponseAnalyzer{
 		Registry:        registry,
 		VariableTracker: variableTracker,
 		Responses:       []*ResponseInfo{},
-		Verbose:         verbose,
+		Log:             log,
+		Detectors:       EchoDetectors(),
 	}
 }
 
-// AnalyzeHandler analyzes a handler function for JSON responses
+// AnalyzeHandler analyzes a handler function for JSON and XML responses
 func (a *ResponseAnalyzer) AnalyzeHandler(funcDecl *ast.FuncDecl) error {
-	if a.Verbose {
-		fmt.Printf("Analyzing handler function: %s for JSON responses\n", funcDecl.Name.Name)
-	}
+	a.Log.Debugf("Analyzing handler function: %s for JSON and XML responses", funcDecl.Name.Name)
 
 	// Clear previous responses
 	a.Responses = []*ResponseInfo{}
@@ -45,14 +87,9 @@ func (a *ResponseAnalyzer) AnalyzeHandler(funcDecl *ast.FuncDecl) error {
 	// Analyze the function body
 	if funcDecl.Body != nil {
 		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-			// Look for method calls
+			// Look for calls writing a response
 			if expr, ok := n.(*ast.CallExpr); ok {
-				if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
-					if ident, ok := sel.X.(*ast.Ident); ok {
-						// Check for Echo context methods
-						a.checkJSONResponseMethod(ident.Name, sel.Sel.Name, expr)
-					}
-				}
+				a.checkJSONResponse(expr)
 			}
 			return true
 		})
@@ -61,46 +98,41 @@ func (a *ResponseAnalyzer) AnalyzeHandler(funcDecl *ast.FuncDecl) error {
 	return nil
 }
 
-// checkJSONResponseMethod checks if a method call is a JSON response method
-func (a *ResponseAnalyzer) checkJSONResponseMethod(objName, methodName string, call *ast.CallExpr) {
-	// Common context parameter names
-	contextNames := map[string]bool{
-		"c": true, "ctx": true, "context": true, "ec": true,
-	}
-
-	if !contextNames[objName] {
+// checkJSONResponse checks if a call writes a JSON or XML response, e.g.
+// c.JSON(http.StatusOK, user) or a helper matched by a registered detector
+func (a *ResponseAnalyzer) checkJSONResponse(call *ast.CallExpr) {
+	// c.JSON(code, i) and c.JSONPretty(code, i, indent) encode the value i,
+	// as do c.XML and c.XMLPretty, while c.JSONBlob(code, b) writes bytes
+	// that are already encoded. c.Render passes the value to a template.
+	match, ok := a.Detectors.DetectResponse(call)
+	if !ok || match.Type != "JSON" && match.Type != "XML" && match.Template == nil {
 		return
 	}
+	isBlob := match.Encoded
+	methodName := CallName(call)
 
-	// Check for JSON response methods
-	isJSONResponse := false
-	switch methodName {
-	case "JSON", "JSONPretty", "JSONBlob":
-		isJSONResponse = true
+	// Extract status code and response variable
+	var statusCode int = http.StatusOK // Default
+	if match.Status != nil {
+		statusCode = a.extractStatusCode(match.Status)
 	}
+	responseVar := match.Payload
 
-	if !isJSONResponse {
+	if responseVar == nil {
 		return
 	}
 
-	// Extract status code and response variable
-	var statusCode int = http.StatusOK // Default
-	var responseVar ast.Expr
-
-	if len(call.Args) >= 2 {
-		// First argument is status code
-		statusCode = a.extractStatusCode(call.Args[0])
-		// Second argument is response data
-		responseVar = call.Args[1]
+	// Resolve the type of the response variable, documenting values that
+	// can't be resolved with a permissive schema rather than dropping them
+	var responseType *TypeDefinition
+	if isBlob {
+		responseType = a.resolveBlobType(responseVar)
+	} else {
+		responseType = a.resolveResponseType(responseVar)
 	}
-
-	// Resolve the type of the response variable
-	responseType := a.resolveResponseType(responseVar)
 	if responseType == nil {
-		if a.Verbose {
-			fmt.Printf("  Could not resolve type of response variable\n")
-		}
-		return
+		a.Log.Debugf("  Could not resolve type of response variable")
+		responseType = UnknownType()
 	}
 
 	// Create response info
@@ -109,52 +141,23 @@ func (a *ResponseAnalyzer) checkJSONResponseMethod(objName, methodName string, c
 		Type:       responseType,
 		Position:   a.Registry.FileSet.Position(call.Pos()).String(),
 	}
+	if match.Template != nil {
+		responseInfo.Template = types.ExprString(match.Template)
+		if lit, ok := match.Template.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			responseInfo.Template, _ = strconv.Unquote(lit.Value)
+		}
+	}
 
 	a.Responses = append(a.Responses, responseInfo)
 
-	if a.Verbose {
-		fmt.Printf("  Found JSON response: status %d, type %s\n", statusCode, responseType.Name)
-	}
+	a.Log.Debugf("  Found %s response: status %d, type %s", methodName, statusCode, responseType.Name)
 }
 
 // extractStatusCode extracts an HTTP status code from an AST expression
 func (a *ResponseAnalyzer) extractStatusCode(expr ast.Expr) int {
-	// Handle direct integer literals
-	if lit, ok := expr.(*ast.BasicLit); ok {
-		if lit.Kind == token.INT {
-			code, _ := strconv.Atoi(lit.Value)
-			return code
-		}
-	}
-
-	// Handle http.StatusXXX constants
-	if sel, ok := expr.(*ast.SelectorExpr); ok {
-		if ident, ok := sel.X.(*ast.Ident); ok {
-			if ident.Name == "http" {
-				switch sel.Sel.Name {
-				case "StatusOK":
-					return http.StatusOK
-				case "StatusCreated":
-					return http.StatusCreated
-				case "StatusAccepted":
-					return http.StatusAccepted
-				case "StatusNoContent":
-					return http.StatusNoContent
-				case "StatusBadRequest":
-					return http.StatusBadRequest
-				case "StatusUnauthorized":
-					return http.StatusUnauthorized
-				case "StatusForbidden":
-					return http.StatusForbidden
-				case "StatusNotFound":
-					return http.StatusNotFound
-				case "StatusInternalServerError":
-					return http.StatusInternalServerError
-				}
-			}
-		}
+	if code, ok := ResolveStatusCode(expr, a.StatusConstants); ok {
+		return code
 	}
-
 	return http.StatusOK // Default
 }
 
@@ -163,29 +166,27 @@ func (a *ResponseAnalyzer) resolveResponseType(expr ast.Expr) *TypeDefinition {
 	switch e := expr.(type) {
 	case *ast.Ident:
 		// Variable reference
-		return a.VariableTracker.GetVariableType(e.Name)
+		return a.VariableTracker.VariableTypeOf(e)
 
 	case *ast.SelectorExpr:
-		// Field access (e.g., user.Profile)
-		if x, ok := e.X.(*ast.Ident); ok {
-			varType := a.VariableTracker.GetVariableType(x.Name)
-			if varType != nil && varType.Kind == KindStruct {
-				// Find the field in the struct
-				for _, field := range varType.Fields {
-					if field.Name == e.Sel.Name {
-						return field.Type
-					}
-				}
-			}
-		}
+		// Field access (e.g., user.Profile, h.cache.users)
+		return fieldType(a.VariableTracker.resolveExpressionType(e.X), e.Sel.Name)
 
 	case *ast.CallExpr:
 		// Function call (e.g., getUser())
 		return a.VariableTracker.resolveFunctionCallType(e)
 
+	case *ast.IndexExpr:
+		// Map or slice element (e.g., cache[id], users[0])
+		return indexedType(a.VariableTracker.resolveExpressionType(e.X))
+
 	case *ast.CompositeLit:
 		// Composite literal (e.g., User{Name: "John"})
-		return a.Registry.ResolveType(e.Type)
+		typeDef := a.VariableTracker.compositeLitType(e)
+		if a.Envelope != nil && a.Envelope.Matches(typeDef) {
+			return a.resolveEnvelopeType(typeDef, e)
+		}
+		return typeDef
 
 	case *ast.UnaryExpr:
 		// Unary expression (e.g., &user)
@@ -197,6 +198,38 @@ func (a *ResponseAnalyzer) resolveResponseType(expr ast.Expr) *TypeDefinition {
 	return nil
 }
 
+// resolveEnvelopeType resolves the type of an envelope literal, with its data
+// field typed as the payload it is given (e.g., Envelope{Data: users})
+func (a *ResponseAnalyzer) resolveEnvelopeType(envelope *TypeDefinition, lit *ast.CompositeLit) *TypeDefinition {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != a.Envelope.DataField {
+			continue
+		}
+
+		payload := a.resolveResponseType(kv.Value)
+		if payload == nil {
+			a.Log.Debugf("  Could not resolve payload type of envelope %s", envelope.Name)
+			return envelope
+		}
+		return a.Registry.WrapEnvelope(envelope, a.Envelope.DataField, payload)
+	}
+
+	return envelope
+}
+
+// resolveBlobType resolves the type of the value encoded in a JSON blob,
+// tracing the byte slice back to the json.Marshal call that produced it
+func (a *ResponseAnalyzer) resolveBlobType(expr ast.Expr) *TypeDefinition {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return a.VariableTracker.MarshaledTypeOf(ident)
+	}
+	return nil
+}
+
 // GetResponses returns all analyzed responses
 func (a *ResponseAnalyzer) GetResponses() []*ResponseInfo {
 	return a.Responses
@@ -3,6 +3,11 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // JSONSchemaType represents a JSON Schema type
@@ -25,6 +30,9 @@ const (
 	JSONSchemaFormatDateTime JSONSchemaFormat = "date-time"
 	JSONSchemaFormatEmail    JSONSchemaFormat = "email"
 	JSONSchemaFormatURI      JSONSchemaFormat = "uri"
+	JSONSchemaFormatUUID     JSONSchemaFormat = "uuid"
+	JSONSchemaFormatInt64    JSONSchemaFormat = "int64"
+	JSONSchemaFormatBinary   JSONSchemaFormat = "binary"
 )
 
 // JSONSchemaProperty represents a property in a JSON Schema
@@ -37,6 +45,22 @@ type JSONSchemaProperty struct {
 	Required             []string                       `json:"required,omitempty"`
 	Ref                  string                         `json:"$ref,omitempty"`
 	AdditionalProperties *JSONSchemaProperty            `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema                  `json:"oneOf,omitempty"`
+	Deprecated           bool                           `json:"deprecated,omitempty"`
+	Nullable             bool                           `json:"nullable,omitempty"`
+	Example              interface{}                    `json:"example,omitempty"`
+	XML                  *XMLObject                     `json:"xml,omitempty"`
+
+	// Constraints from validate tags
+	Enum             []interface{} `json:"enum,omitempty"`
+	MinLength        *int          `json:"minLength,omitempty"`
+	MaxLength        *int          `json:"maxLength,omitempty"`
+	MinItems         *int          `json:"minItems,omitempty"`
+	MaxItems         *int          `json:"maxItems,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
 }
 
 // JSONSchema represents a JSON Schema
@@ -47,37 +71,145 @@ type JSONSchema struct {
 	Items                *JSONSchema                    `json:"items,omitempty"`
 	Properties           map[string]*JSONSchemaProperty `json:"properties,omitempty"`
 	Required             []string                       `json:"required,omitempty"`
+	Ref                  string                         `json:"$ref,omitempty"`
 	AdditionalProperties *JSONSchemaProperty            `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema                  `json:"oneOf,omitempty"`
+	Nullable             bool                           `json:"nullable,omitempty"`
+	XML                  *XMLObject                     `json:"xml,omitempty"`
 }
 
 // SchemaGenerator generates JSON Schema from Go type definitions
 type SchemaGenerator struct {
 	Registry *TypeRegistry
 	Schemas  map[string]*JSONSchema
-	Verbose  bool
+	Log      *logger.Logger
+
+	// OmitRequired drops the required arrays from all object schemas
+	OmitRequired bool
+
+	// Components holds the schemas referenced from other schemas by $ref,
+	// keyed by component name
+	Components map[string]*JSONSchema
+
+	// NullablePointers marks pointer fields as nullable, since a nil pointer
+	// is serialized as null
+	NullablePointers bool
+
+	// WellKnownTypes maps qualified names of types from outside the analyzed
+	// code to their schemas, seeded with DefaultWellKnownTypes
+	WellKnownTypes map[string]WellKnownType
+
+	// Draft is the JSON Schema draft schema documents conform to,
+	// SchemaDraft202012 or SchemaDraft07; if empty, SchemaDraft202012
+	Draft string
+
+	// ExampleArrayLength is the number of elements of arrays in generated
+	// examples; if 0, DefaultExampleArrayLength
+	ExampleArrayLength int
+
+	// FormatRules infer the format of struct fields from their names, in
+	// priority order, seeded with DefaultFormatRules; nil disables inference
+	FormatRules []FormatRule
+
+	// inProgress tracks types whose schema or example is being generated,
+	// so recursive types don't recurse forever
+	inProgress map[string]bool
+
+	// inExampleArray is set while the example of an array element is
+	// generated, with exampleIndex the index of the element, so elements
+	// get distinct values
+	inExampleArray bool
+	exampleIndex   int
+
+	// xml generates schemas of the XML encoding while set; they are cached
+	// in xmlSchemas, apart from the JSON schemas
+	xml        bool
+	xmlSchemas map[string]*JSONSchema
+
+	// defs collects named struct schemas while a schema document is being
+	// generated; nested structs are referenced from $defs while it is set
+	defs map[string]*JSONSchema
+
+	// mu guards Schemas, Components, inProgress and defs during generation
+	mu sync.Mutex
 }
 
 // NewSchemaGenerator creates a new SchemaGenerator
-func NewSchemaGenerator(registry *TypeRegistry, verbose bool) *SchemaGenerator {
+func NewSchemaGenerator(registry *TypeRegistry, log *logger.Logger) *SchemaGenerator {
+	wellKnownTypes := make(map[string]WellKnownType, len(DefaultWellKnownTypes))
+	for name, wellKnown := range DefaultWellKnownTypes {
+		wellKnownTypes[name] = wellKnown
+	}
+
 	return &SchemaGenerator{
-		Registry: registry,
-		Schemas:  make(map[string]*JSONSchema),
-		Verbose:  verbose,
+		Registry:       registry,
+		Schemas:        make(map[string]*JSONSchema),
+		Components:     make(map[string]*JSONSchema),
+		Log:            log,
+		WellKnownTypes: wellKnownTypes,
+		FormatRules:    append([]FormatRule{}, DefaultFormatRules...),
+		inProgress:     make(map[string]bool),
+		xmlSchemas:     make(map[string]*JSONSchema),
 	}
 }
 
-// GenerateSchema generates a JSON Schema for a type definition
+// GenerateSchema generates a JSON Schema for a type definition. It is safe
+// for concurrent use: generated schemas are cached and shared between calls.
 func (g *SchemaGenerator) GenerateSchema(typeDef *TypeDefinition) *JSONSchema {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.generateSchema(typeDef)
+}
+
+// generateSchema generates a JSON Schema for a type definition, with the lock held
+func (g *SchemaGenerator) generateSchema(typeDef *TypeDefinition) *JSONSchema {
 	if typeDef == nil {
 		return nil
 	}
 
 	// Check if we've already generated a schema for this type
-	schemaKey := fmt.Sprintf("%s.%s", typeDef.Package, typeDef.Name)
-	if schema, exists := g.Schemas[schemaKey]; exists {
+	schemas := g.Schemas
+	if g.xml {
+		schemas = g.xmlSchemas
+	}
+	schemaKey := schemaCacheKey(typeDef)
+	if schema, exists := schemas[schemaKey]; exists && schemaKey != "" {
 		return schema
 	}
 
+	// Types with a well-known JSON representation
+	if wellKnown, exists := g.lookupWellKnownType(typeDef); exists {
+		schema := *wellKnown.Schema
+		if schemaKey != "" {
+			schemas[schemaKey] = &schema
+		}
+		return &schema
+	}
+
+	// Schemas set in the doc comment of the type declaration
+	if typeDef.Schema != nil {
+		schema := *typeDef.Schema
+		if schemaKey != "" {
+			schemas[schemaKey] = &schema
+		}
+		return &schema
+	}
+
+	// Named structs are referenced from $defs in schema documents
+	if g.defs != nil && typeDef.Kind == KindStruct && typeDef.Name != "" {
+		return g.defRef(typeDef)
+	}
+
+	// Break cycles in recursive types with a plain object schema
+	if schemaKey != "" {
+		if g.inProgress[schemaKey] {
+			return &JSONSchema{Type: JSONSchemaTypeObject}
+		}
+		g.inProgress[schemaKey] = true
+		defer delete(g.inProgress, schemaKey)
+	}
+
 	// Create a new schema based on the type kind
 	var schema *JSONSchema
 	switch typeDef.Kind {
@@ -92,18 +224,70 @@ func (g *SchemaGenerator) GenerateSchema(typeDef *TypeDefinition) *JSONSchema {
 	case KindPointer:
 		// For pointers, generate schema for the element type
 		if typeDef.ElementType != nil {
-			schema = g.GenerateSchema(typeDef.ElementType)
+			schema = g.generateSchema(typeDef.ElementType)
 		}
+	case KindInterface:
+		schema = g.generateInterfaceSchema(typeDef)
+	case KindUnknown:
+		schema = UnknownSchema()
+	}
+
+	// The fields of types with a custom MarshalJSON method may not be what
+	// they encode to
+	if typeDef.Kind != KindPointer {
+		noteCustomMarshaler(typeDef, schema)
 	}
 
 	// Store the schema for future reference
-	if schema != nil {
-		g.Schemas[schemaKey] = schema
+	if schema != nil && schemaKey != "" {
+		schemas[schemaKey] = schema
 	}
 
 	return schema
 }
 
+// schemaCacheKey returns the key a type's schema is cached under. Unnamed
+// pointer, slice and map types are keyed by their structure, since their
+// names are built from unqualified element names and would collide across
+// packages (e.g. []User and []models.User). Anonymous structs and interfaces
+// aren't identified by their names, so their schemas aren't cached and the
+// key is empty.
+func schemaCacheKey(typeDef *TypeDefinition) string {
+	if typeDef == nil {
+		return ""
+	}
+
+	name := typeDef.Name
+	switch {
+	case typeDef.Kind == KindPointer && strings.HasPrefix(name, "*"):
+		if elem := schemaCacheKey(typeDef.ElementType); elem != "" {
+			return "*" + elem
+		}
+		return ""
+	case typeDef.Kind == KindArray && strings.HasPrefix(name, "["):
+		if elem := schemaCacheKey(typeDef.ElementType); elem != "" {
+			return name[:strings.Index(name, "]")+1] + elem
+		}
+		return ""
+	case typeDef.Kind == KindMap && len(typeDef.LiteralFields) > 0:
+		// Literal fields are specific to the literal the map was built from
+		return ""
+	case typeDef.Kind == KindMap && strings.HasPrefix(name, "map["):
+		key, value := schemaCacheKey(typeDef.KeyType), schemaCacheKey(typeDef.ValueType)
+		if key != "" && value != "" {
+			return "map[" + key + "]" + value
+		}
+		return ""
+	case typeDef.Kind == KindStruct && name == "anonymous",
+		typeDef.Kind == KindInterface && name == "interface":
+		return ""
+	}
+	if typeDef.Service != "" {
+		return fmt.Sprintf("%s:%s.%s", typeDef.Service, typeDef.Package, name)
+	}
+	return fmt.Sprintf("%s.%s", typeDef.Package, name)
+}
+
 // generateStructSchema generates a JSON Schema for a struct type
 func (g *SchemaGenerator) generateStructSchema(typeDef *TypeDefinition) *JSONSchema {
 	schema := &JSONSchema{
@@ -119,38 +303,79 @@ func (g *SchemaGenerator) generateStructSchema(typeDef *TypeDefinition) *JSONSch
 			continue
 		}
 
-		// Determine JSON field name
+		// Determine JSON field name, or the XML element name. XML elements
+		// are named after the Go field unless tagged otherwise.
 		jsonName := field.Name
-		if field.JSONName != "" {
+		omitempty := field.Omitempty
+		if g.xml {
+			if field.XMLName == "-" || field.Name == "XMLName" {
+				continue
+			}
+			if field.XMLName != "" {
+				jsonName = field.XMLName
+			}
+			omitempty = field.XMLOmitempty
+		} else if field.JSONName != "" {
 			jsonName = field.JSONName
 		}
 
 		// Generate schema for the field type
-		fieldSchema := g.GenerateSchema(field.Type)
+		fieldSchema := g.generateSchema(field.Type)
 		if fieldSchema == nil {
 			continue
 		}
 
+		// Describe the property with the field's doc comment, if it has one
+		description := fieldSchema.Description
+		if field.Description != "" {
+			description = field.Description
+		}
+
 		// Create property from field schema
 		property := &JSONSchemaProperty{
 			Type:                 fieldSchema.Type,
 			Format:               fieldSchema.Format,
-			Description:          fieldSchema.Description,
+			Description:          description,
 			Items:                fieldSchema.Items,
 			Properties:           fieldSchema.Properties,
 			Required:             fieldSchema.Required,
+			Ref:                  fieldSchema.Ref,
 			AdditionalProperties: fieldSchema.AdditionalProperties,
+			OneOf:                fieldSchema.OneOf,
+			Deprecated:           field.Deprecated,
+			Nullable:             g.NullablePointers && field.IsPointer,
+		}
+		if field.Example != "" {
+			property.Example = g.exampleValue(field.Example, field.Type)
+		}
+		if g.xml && field.XMLAttr {
+			property.XML = &XMLObject{Attribute: true}
+		}
+
+		// Add constraints from the validate tag
+		validatedRequired := applyValidationConstraints(property, field.Validate)
+
+		// Infer the format of plain values from the field name, e.g. email
+		// for ContactEmail, unless the type or validate tag sets one
+		if property.Format == "" && property.Ref == "" {
+			property.Format = g.inferFormat(field, property.Type)
 		}
 
 		// Add property to schema
 		schema.Properties[jsonName] = property
 
-		// Add to required fields if not omitempty
-		if !field.Omitempty {
+		// Add to required fields if not omitempty, or if validation requires
+		// them. Pointer fields are otherwise never required since they can be nil.
+		if (validatedRequired || (!omitempty && !field.IsPointer)) && !g.OmitRequired {
 			schema.Required = append(schema.Required, jsonName)
 		}
 	}
 
+	// Leave the required array out entirely when there is nothing in it
+	if len(schema.Required) == 0 {
+		schema.Required = nil
+	}
+
 	return schema
 }
 
@@ -160,9 +385,16 @@ func (g *SchemaGenerator) generateArraySchema(typeDef *TypeDefinition) *JSONSche
 		Type: JSONSchemaTypeArray,
 	}
 
-	// Generate schema for the element type
+	// Generate schema for the element type. Pointer elements can be nil,
+	// so the items are nullable; the cached element schema is shared, so
+	// nullability is set on a copy.
 	if typeDef.ElementType != nil {
-		elemSchema := g.GenerateSchema(typeDef.ElementType)
+		elemSchema := g.generateSchema(typeDef.ElementType)
+		if elemSchema != nil && g.NullablePointers && typeDef.ElementType.Kind == KindPointer {
+			nullable := *elemSchema
+			nullable.Nullable = true
+			elemSchema = &nullable
+		}
 		if elemSchema != nil {
 			schema.Items = elemSchema
 		}
@@ -177,9 +409,19 @@ func (g *SchemaGenerator) generateMapSchema(typeDef *TypeDefinition) *JSONSchema
 		Type: JSONSchemaTypeObject,
 	}
 
+	// Keys of the literal the map was built from are documented as properties
+	if len(typeDef.LiteralFields) > 0 {
+		literal := g.generateStructSchema(&TypeDefinition{
+			Name:   "anonymous",
+			Kind:   KindStruct,
+			Fields: typeDef.LiteralFields,
+		})
+		schema.Properties = literal.Properties
+	}
+
 	// Generate schema for the value type
 	if typeDef.ValueType != nil {
-		valueSchema := g.GenerateSchema(typeDef.ValueType)
+		valueSchema := g.generateSchema(typeDef.ValueType)
 		if valueSchema != nil {
 			schema.AdditionalProperties = &JSONSchemaProperty{
 				Type:                 valueSchema.Type,
@@ -188,14 +430,75 @@ func (g *SchemaGenerator) generateMapSchema(typeDef *TypeDefinition) *JSONSchema
 				Items:                valueSchema.Items,
 				Properties:           valueSchema.Properties,
 				Required:             valueSchema.Required,
+				Ref:                  valueSchema.Ref,
 				AdditionalProperties: valueSchema.AdditionalProperties,
+				OneOf:                valueSchema.OneOf,
+				Nullable:             g.NullablePointers && typeDef.ValueType.Kind == KindPointer,
+			}
+		}
+	}
+
+	return schema
+}
+
+// generateInterfaceSchema generates a JSON Schema for an interface type. Named
+// interfaces become a oneOf over their implementations; anything else accepts
+// any value.
+func (g *SchemaGenerator) generateInterfaceSchema(typeDef *TypeDefinition) *JSONSchema {
+	schema := &JSONSchema{}
+
+	for _, impl := range typeDef.Implementations {
+		if g.defs != nil {
+			schema.OneOf = append(schema.OneOf, g.defRef(impl))
+			continue
+		}
+
+		name := ComponentName(impl)
+		if g.xml {
+			name += "_XML"
+		}
+		if _, exists := g.Components[name]; !exists {
+			// Reserve the name first so recursive references terminate
+			g.Components[name] = &JSONSchema{}
+			if implSchema := g.generateSchema(impl); implSchema != nil {
+				*g.Components[name] = *implSchema
 			}
 		}
+		schema.OneOf = append(schema.OneOf, &JSONSchema{Ref: "#/components/schemas/" + name})
 	}
 
 	return schema
 }
 
+// UnknownTypeDescription describes values whose type can't be statically determined
+const UnknownTypeDescription = "type could not be statically determined"
+
+// UnknownSchema returns the permissive schema used for values whose type
+// can't be statically determined
+func UnknownSchema() *JSONSchema {
+	return &JSONSchema{Description: UnknownTypeDescription}
+}
+
+// ComponentName returns the name under which a type's schema is stored in
+// the components section, replacing characters OpenAPI doesn't allow. Types
+// of a service are qualified with its name, e.g. orders.Order.
+func ComponentName(typeDef *TypeDefinition) string {
+	name := typeDef.Name
+	if typeDef.Service != "" {
+		name = typeDef.Service + "." + name
+	}
+
+	var sb strings.Builder
+	for _, r := range name {
+		if r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '.' || r == '-' || r == '_' {
+			sb.WriteRune(r)
+		} else if r == '[' || r == ',' {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
 // generateBasicSchema generates a JSON Schema for a basic type
 func (g *SchemaGenerator) generateBasicSchema(typeDef *TypeDefinition) *JSONSchema {
 	schema := &JSONSchema{}
@@ -210,9 +513,6 @@ func (g *SchemaGenerator) generateBasicSchema(typeDef *TypeDefinition) *JSONSche
 		schema.Type = JSONSchemaTypeNumber
 	case "bool":
 		schema.Type = JSONSchemaTypeBoolean
-	case "time.Time":
-		schema.Type = JSONSchemaTypeString
-		schema.Format = JSONSchemaFormatDateTime
 	default:
 		// Default to string for unknown types
 		schema.Type = JSONSchemaTypeString
@@ -228,8 +528,16 @@ func (g *SchemaGenerator) GenerateSchemaString(typeDef *TypeDefinition) (string,
 		return "", fmt.Errorf("failed to generate schema for type %s", typeDef.Name)
 	}
 
-	// Convert schema to JSON
-	schemaBytes, err := json.MarshalIndent(schema, "", "  ")
+	return marshalJSONSchema(schema)
+}
+
+// marshalJSONSchema converts a schema to indented JSON
+func marshalJSONSchema(schema interface{}) (string, error) {
+	value, err := JSONSchemaValue(schema)
+	if err != nil {
+		return "", err
+	}
+	schemaBytes, err := json.MarshalIndent(value, "", "  ")
 	if err != nil {
 		return "", err
 	}
@@ -237,9 +545,88 @@ func (g *SchemaGenerator) GenerateSchemaString(typeDef *TypeDefinition) (string,
 	return string(schemaBytes), nil
 }
 
+// JSONSchemaValue converts a schema to generic JSON values, expressing
+// nullable properties and exclusive bounds the JSON Schema way
+func JSONSchemaValue(schema interface{}) (interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return jsonSchemaExclusiveBounds(jsonSchemaNullable(generic)), nil
+}
+
+// jsonSchemaExclusiveBounds rewrites the boolean exclusiveMinimum and
+// exclusiveMaximum of OpenAPI 3.0 schemas into the numeric form of JSON
+// Schema draft-06 and later, which replaces minimum and maximum
+func jsonSchemaExclusiveBounds(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range s {
+			s[key] = jsonSchemaExclusiveBounds(value)
+		}
+		for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+			if isExclusive, ok := s[exclusive].(bool); ok {
+				delete(s, exclusive)
+				if value, exists := s[bound]; exists && isExclusive {
+					s[exclusive] = value
+					delete(s, bound)
+				}
+			}
+		}
+		return s
+	case []interface{}:
+		for i, value := range s {
+			s[i] = jsonSchemaExclusiveBounds(value)
+		}
+		return s
+	}
+	return schema
+}
+
+// jsonSchemaNullable rewrites the OpenAPI "nullable" keyword in a generic
+// schema into JSON Schema form: a type array including "null", or a oneOf
+// with a null schema for $refs
+func jsonSchemaNullable(schema interface{}) interface{} {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range s {
+			s[key] = jsonSchemaNullable(value)
+		}
+		if nullable, _ := s["nullable"].(bool); nullable {
+			delete(s, "nullable")
+			if t, ok := s["type"].(string); ok {
+				s["type"] = []string{t, string(JSONSchemaTypeNull)}
+			} else if ref, ok := s["$ref"]; ok {
+				delete(s, "$ref")
+				s["oneOf"] = []interface{}{
+					map[string]interface{}{"$ref": ref},
+					map[string]interface{}{"type": JSONSchemaTypeNull},
+				}
+			}
+		}
+		return s
+	case []interface{}:
+		for i, value := range s {
+			s[i] = jsonSchemaNullable(value)
+		}
+		return s
+	}
+	return schema
+}
+
+// DefaultExampleArrayLength is the number of elements of arrays in
+// generated examples, unless SchemaGenerator.ExampleArrayLength is set
+const DefaultExampleArrayLength = 2
+
 // GenerateExampleJSON generates an example JSON string for a type definition
 func (g *SchemaGenerator) GenerateExampleJSON(typeDef *TypeDefinition) (string, error) {
+	g.mu.Lock()
 	example := g.generateExample(typeDef)
+	g.mu.Unlock()
 	if example == nil {
 		return "", fmt.Errorf("failed to generate example for type %s", typeDef.Name)
 	}
@@ -259,8 +646,21 @@ func (g *SchemaGenerator) generateExample(typeDef *TypeDefinition) interface{} {
 		return nil
 	}
 
+	// Types with a well-known JSON representation
+	if wellKnown, exists := g.lookupWellKnownType(typeDef); exists {
+		return wellKnown.Example
+	}
+
 	switch typeDef.Kind {
 	case KindStruct:
+		// Break cycles in recursive types with an empty object
+		exampleKey := "example:" + typeDef.Package + "." + typeDef.Name
+		if g.inProgress[exampleKey] {
+			return map[string]interface{}{}
+		}
+		g.inProgress[exampleKey] = true
+		defer delete(g.inProgress, exampleKey)
+
 		return g.generateStructExample(typeDef)
 	case KindArray:
 		return g.generateArrayExample(typeDef)
@@ -273,6 +673,14 @@ func (g *SchemaGenerator) generateExample(typeDef *TypeDefinition) interface{} {
 		if typeDef.ElementType != nil {
 			return g.generateExample(typeDef.ElementType)
 		}
+	case KindInterface:
+		// Use the first implementation as the example
+		if len(typeDef.Implementations) > 0 {
+			return g.generateExample(typeDef.Implementations[0])
+		}
+		return map[string]interface{}{}
+	case KindUnknown:
+		return map[string]interface{}{}
 	}
 
 	return nil
@@ -295,6 +703,12 @@ func (g *SchemaGenerator) generateStructExample(typeDef *TypeDefinition) interfa
 			jsonName = field.JSONName
 		}
 
+		// Use the value from the example tag, if the field has one
+		if field.Example != "" {
+			example[jsonName] = g.exampleValue(field.Example, field.Type)
+			continue
+		}
+
 		// Skip omitempty fields for simplicity
 		if field.Omitempty {
 			continue
@@ -310,17 +724,103 @@ func (g *SchemaGenerator) generateStructExample(typeDef *TypeDefinition) interfa
 	return example
 }
 
-// generateArrayExample generates an example for an array type
+// exampleValue converts the value of an example tag to the JSON type of a
+// field: numbers and booleans are parsed, arrays are split on commas (as in
+// swaggo) unless written as JSON, and objects are parsed as JSON. Values
+// that can't be converted are kept as strings.
+func (g *SchemaGenerator) exampleValue(raw string, typeDef *TypeDefinition) interface{} {
+	for typeDef.Kind == KindPointer && typeDef.ElementType != nil {
+		typeDef = typeDef.ElementType
+	}
+
+	schemaType := JSONSchemaTypeString
+	if wellKnown, exists := g.lookupWellKnownType(typeDef); exists {
+		schemaType = wellKnown.Schema.Type
+	} else {
+		switch typeDef.Kind {
+		case KindBasic:
+			schemaType = g.generateBasicSchema(typeDef).Type
+		case KindArray:
+			schemaType = JSONSchemaTypeArray
+		case KindStruct, KindMap:
+			schemaType = JSONSchemaTypeObject
+		case KindInterface, KindUnknown:
+			schemaType = ""
+		}
+	}
+
+	switch schemaType {
+	case JSONSchemaTypeInteger:
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value
+		}
+	case JSONSchemaTypeNumber:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case JSONSchemaTypeBoolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case JSONSchemaTypeArray:
+		var value []interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value
+		}
+		values := []interface{}{}
+		for _, item := range strings.Split(raw, ",") {
+			item = strings.TrimSpace(item)
+			if typeDef.ElementType != nil {
+				values = append(values, g.exampleValue(item, typeDef.ElementType))
+			} else {
+				values = append(values, item)
+			}
+		}
+		return values
+	case JSONSchemaTypeObject, "":
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value
+		}
+	}
+
+	return raw
+}
+
+// generateArrayExample generates an example for an array type, with
+// ExampleArrayLength elements whose values vary with their index, such as
+// incrementing ids. Arrays nested in an element get a single element, so
+// examples of nested arrays stay small.
 func (g *SchemaGenerator) generateArrayExample(typeDef *TypeDefinition) interface{} {
-	// Generate a single example element
-	if typeDef.ElementType != nil {
-		elemExample := g.generateExample(typeDef.ElementType)
-		if elemExample != nil {
-			return []interface{}{elemExample}
+	example := []interface{}{}
+	if typeDef.ElementType == nil {
+		return example
+	}
+
+	if g.inExampleArray {
+		if elemExample := g.generateExample(typeDef.ElementType); elemExample != nil {
+			example = append(example, elemExample)
 		}
+		return example
 	}
 
-	return []interface{}{}
+	length := g.ExampleArrayLength
+	if length <= 0 {
+		length = DefaultExampleArrayLength
+	}
+	g.inExampleArray = true
+	defer func() {
+		g.inExampleArray, g.exampleIndex = false, 0
+	}()
+	for i := 0; i < length; i++ {
+		g.exampleIndex = i
+		elemExample := g.generateExample(typeDef.ElementType)
+		if elemExample == nil {
+			break
+		}
+		example = append(example, elemExample)
+	}
+	return example
 }
 
 // generateMapExample generates an example for a map type
@@ -338,8 +838,24 @@ func (g *SchemaGenerator) generateMapExample(typeDef *TypeDefinition) interface{
 	return example
 }
 
-// generateBasicExample generates an example for a basic type
+// generateBasicExample generates an example for a basic type. In array
+// elements, values are numbered after the element: "string1", 1, 1.5 and
+// false for the first, "string2", 2, 2.5 and true for the second.
 func (g *SchemaGenerator) generateBasicExample(typeDef *TypeDefinition) interface{} {
+	if g.inExampleArray {
+		n := g.exampleIndex + 1
+		switch typeDef.BasicType {
+		case "string":
+			return fmt.Sprintf("string%d", n)
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return n
+		case "float32", "float64":
+			return float64(n) + 0.5
+		case "bool":
+			return n%2 == 0
+		}
+	}
+
 	// Generate example based on the basic type
 	switch typeDef.BasicType {
 	case "string":
@@ -350,8 +866,6 @@ func (g *SchemaGenerator) generateBasicExample(typeDef *TypeDefinition) interfac
 		return 0.0
 	case "bool":
 		return false
-	case "time.Time":
-		return "2025-04-23T01:27:02Z"
 	default:
 		return "unknown"
 	}
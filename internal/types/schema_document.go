@@ -0,0 +1,124 @@
+package types
+
+import "fmt"
+
+// JSONSchemaDialect is the JSON Schema version schema documents conform to
+// by default
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON Schema drafts schema documents can conform to
+const (
+	SchemaDraft202012 = "2020-12"
+	SchemaDraft07     = "draft-07"
+)
+
+// schemaDialects maps the supported JSON Schema drafts to their meta-schema URI
+var schemaDialects = map[string]string{
+	SchemaDraft202012: JSONSchemaDialect,
+	SchemaDraft07:     "http://json-schema.org/draft-07/schema#",
+}
+
+// SchemaDialect returns the meta-schema URI of a JSON Schema draft, the
+// value of $schema in schema documents conforming to it
+func SchemaDialect(draft string) (string, error) {
+	dialect, exists := schemaDialects[draft]
+	if !exists {
+		return "", fmt.Errorf("unsupported JSON Schema draft %q, expected %s or %s", draft, SchemaDraft07, SchemaDraft202012)
+	}
+	return dialect, nil
+}
+
+// SchemaDocument is a standalone JSON Schema: a root schema and the
+// definitions of the named structs it references through $ref, under $defs,
+// or definitions in draft-07
+type SchemaDocument struct {
+	Dialect string `json:"$schema,omitempty"`
+	*JSONSchema
+	Defs        map[string]*JSONSchema `json:"$defs,omitempty"`
+	Definitions map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+// GenerateSchemaDocument generates a schema document for a type definition.
+// Unlike GenerateSchema, each named struct nested in the type is defined once
+// in $defs and referenced wherever it appears.
+func (g *SchemaGenerator) GenerateSchemaDocument(typeDef *TypeDefinition) *SchemaDocument {
+	if typeDef == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Schemas cached by GenerateSchema inline nested structs, so generate
+	// the document with a cache of its own
+	schemas := g.Schemas
+	g.Schemas = make(map[string]*JSONSchema)
+	g.defs = make(map[string]*JSONSchema)
+	defer func() {
+		g.Schemas = schemas
+		g.defs = nil
+	}()
+
+	// The root itself is inlined rather than referenced
+	root := typeDef
+	for root.Kind == KindPointer && root.ElementType != nil {
+		root = root.ElementType
+	}
+	var schema *JSONSchema
+	if root.Kind == KindStruct {
+		schema = g.generateStructSchema(root)
+	} else {
+		schema = g.generateSchema(root)
+	}
+	if schema == nil {
+		return nil
+	}
+
+	doc := &SchemaDocument{Dialect: JSONSchemaDialect, JSONSchema: schema}
+	if g.Draft == SchemaDraft07 {
+		doc.Dialect = schemaDialects[SchemaDraft07]
+	}
+	if len(g.defs) > 0 {
+		if g.Draft == SchemaDraft07 {
+			doc.Definitions = g.defs
+		} else {
+			doc.Defs = g.defs
+		}
+	}
+	return doc
+}
+
+// GenerateSchemaDocumentString generates a schema document string for a type definition
+func (g *SchemaGenerator) GenerateSchemaDocumentString(typeDef *TypeDefinition) (string, error) {
+	doc := g.GenerateSchemaDocument(typeDef)
+	if doc == nil {
+		return "", fmt.Errorf("failed to generate schema for type %s", typeDef.Name)
+	}
+	return marshalJSONSchema(doc)
+}
+
+// defRef returns a $ref to the definition of a named type, generating the
+// definition the first time the type is referenced
+func (g *SchemaGenerator) defRef(typeDef *TypeDefinition) *JSONSchema {
+	name := ComponentName(typeDef)
+	if _, exists := g.defs[name]; !exists {
+		// Reserve the name first so recursive references terminate
+		g.defs[name] = &JSONSchema{}
+		var schema *JSONSchema
+		if typeDef.Schema != nil {
+			schema = typeDef.Schema
+		} else if typeDef.Kind == KindStruct {
+			schema = g.generateStructSchema(typeDef)
+			noteCustomMarshaler(typeDef, schema)
+		} else {
+			schema = g.generateSchema(typeDef)
+		}
+		if schema != nil {
+			*g.defs[name] = *schema
+		}
+	}
+	if g.Draft == SchemaDraft07 {
+		return &JSONSchema{Ref: "#/definitions/" + name}
+	}
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const optionalFieldsSource = `package main
+
+type Profile struct {
+	Bio string ` + "`json:\"bio\"`" + `
+}
+
+type User struct {
+	Avatar   *Profile ` + "`json:\"avatar,omitempty\"`" + `
+	Manager  *Profile ` + "`json:\"manager\"`" + `
+	Nickname string   ` + "`json:\"nickname,omitempty\"`" + `
+	Name     string   ` + "`json:\"name\"`" + `
+}
+`
+
+// collectedType collects and resolves the types of a source file and returns
+// the named one
+func collectedType(t *testing.T, source, name string) (*TypeRegistry, *TypeDefinition) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewTypeRegistry(fset, nil)
+	collector := NewTypeCollector(registry, nil)
+	if err := collector.CollectTypes([]*ast.File{file}, "main"); err != nil {
+		t.Fatalf("CollectTypes() = %v", err)
+	}
+	if err := collector.ResolveTypes(); err != nil {
+		t.Fatalf("ResolveTypes() = %v", err)
+	}
+	fieldAnalyzer := NewStructFieldAnalyzer(registry, nil)
+	if err := fieldAnalyzer.AnalyzeStructFields(); err != nil {
+		t.Fatalf("AnalyzeStructFields() = %v", err)
+	}
+	fieldAnalyzer.AnalyzeNestedStructs()
+
+	typeDef := registry.LookupTypeIn("main", name)
+	if typeDef == nil {
+		t.Fatalf("no type %s", name)
+	}
+	return registry, typeDef
+}
+
+func TestOptionalFields(t *testing.T) {
+	registry, user := collectedType(t, optionalFieldsSource, "User")
+
+	// Pointer fields are never required, since they can be nil; with
+	// NullablePointers they're also nullable, whether or not omitempty is set
+	for _, test := range []struct {
+		field    string
+		nullable bool
+		required bool
+	}{
+		{"avatar", true, false},    // pointer, omitempty
+		{"manager", true, false},   // pointer
+		{"nickname", false, false}, // omitempty
+		{"name", false, true},      // neither
+	} {
+		for _, nullablePointers := range []bool{false, true} {
+			g := NewSchemaGenerator(registry, nil)
+			g.NullablePointers = nullablePointers
+			schema := g.GenerateSchema(user)
+
+			property, exists := schema.Properties[test.field]
+			if !exists {
+				t.Fatalf("User schema has no property %s: %+v", test.field, schema.Properties)
+			}
+			if want := test.nullable && nullablePointers; property.Nullable != want {
+				t.Errorf("NullablePointers=%v: %s nullable = %v, want %v", nullablePointers, test.field, property.Nullable, want)
+			}
+			required := false
+			for _, name := range schema.Required {
+				required = required || name == test.field
+			}
+			if required != test.required {
+				t.Errorf("NullablePointers=%v: %s required = %v, want %v", nullablePointers, test.field, required, test.required)
+			}
+		}
+	}
+}
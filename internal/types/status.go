@@ -0,0 +1,142 @@
+package types
+
+import (
+	"go/ast"
+	"go/token"
+	"net/http"
+	"strconv"
+)
+
+// HTTPStatusCodes maps the names of the net/http status code constants to their values
+var HTTPStatusCodes = map[string]int{
+	"StatusContinue":                      http.StatusContinue,
+	"StatusSwitchingProtocols":            http.StatusSwitchingProtocols,
+	"StatusProcessing":                    http.StatusProcessing,
+	"StatusEarlyHints":                    http.StatusEarlyHints,
+	"StatusOK":                            http.StatusOK,
+	"StatusCreated":                       http.StatusCreated,
+	"StatusAccepted":                      http.StatusAccepted,
+	"StatusNonAuthoritativeInfo":          http.StatusNonAuthoritativeInfo,
+	"StatusNoContent":                     http.StatusNoContent,
+	"StatusResetContent":                  http.StatusResetContent,
+	"StatusPartialContent":                http.StatusPartialContent,
+	"StatusMultiStatus":                   http.StatusMultiStatus,
+	"StatusAlreadyReported":               http.StatusAlreadyReported,
+	"StatusIMUsed":                        http.StatusIMUsed,
+	"StatusMultipleChoices":               http.StatusMultipleChoices,
+	"StatusMovedPermanently":              http.StatusMovedPermanently,
+	"StatusFound":                         http.StatusFound,
+	"StatusSeeOther":                      http.StatusSeeOther,
+	"StatusNotModified":                   http.StatusNotModified,
+	"StatusUseProxy":                      http.StatusUseProxy,
+	"StatusTemporaryRedirect":             http.StatusTemporaryRedirect,
+	"StatusPermanentRedirect":             http.StatusPermanentRedirect,
+	"StatusBadRequest":                    http.StatusBadRequest,
+	"StatusUnauthorized":                  http.StatusUnauthorized,
+	"StatusPaymentRequired":               http.StatusPaymentRequired,
+	"StatusForbidden":                     http.StatusForbidden,
+	"StatusNotFound":                      http.StatusNotFound,
+	"StatusMethodNotAllowed":              http.StatusMethodNotAllowed,
+	"StatusNotAcceptable":                 http.StatusNotAcceptable,
+	"StatusProxyAuthRequired":             http.StatusProxyAuthRequired,
+	"StatusRequestTimeout":                http.StatusRequestTimeout,
+	"StatusConflict":                      http.StatusConflict,
+	"StatusGone":                          http.StatusGone,
+	"StatusLengthRequired":                http.StatusLengthRequired,
+	"StatusPreconditionFailed":            http.StatusPreconditionFailed,
+	"StatusRequestEntityTooLarge":         http.StatusRequestEntityTooLarge,
+	"StatusRequestURITooLong":             http.StatusRequestURITooLong,
+	"StatusUnsupportedMediaType":          http.StatusUnsupportedMediaType,
+	"StatusRequestedRangeNotSatisfiable":  http.StatusRequestedRangeNotSatisfiable,
+	"StatusExpectationFailed":             http.StatusExpectationFailed,
+	"StatusTeapot":                        http.StatusTeapot,
+	"StatusMisdirectedRequest":            http.StatusMisdirectedRequest,
+	"StatusUnprocessableEntity":           http.StatusUnprocessableEntity,
+	"StatusLocked":                        http.StatusLocked,
+	"StatusFailedDependency":              http.StatusFailedDependency,
+	"StatusTooEarly":                      http.StatusTooEarly,
+	"StatusUpgradeRequired":               http.StatusUpgradeRequired,
+	"StatusPreconditionRequired":          http.StatusPreconditionRequired,
+	"StatusTooManyRequests":               http.StatusTooManyRequests,
+	"StatusRequestHeaderFieldsTooLarge":   http.StatusRequestHeaderFieldsTooLarge,
+	"StatusUnavailableForLegalReasons":    http.StatusUnavailableForLegalReasons,
+	"StatusInternalServerError":           http.StatusInternalServerError,
+	"StatusNotImplemented":                http.StatusNotImplemented,
+	"StatusBadGateway":                    http.StatusBadGateway,
+	"StatusServiceUnavailable":            http.StatusServiceUnavailable,
+	"StatusGatewayTimeout":                http.StatusGatewayTimeout,
+	"StatusHTTPVersionNotSupported":       http.StatusHTTPVersionNotSupported,
+	"StatusVariantAlsoNegotiates":         http.StatusVariantAlsoNegotiates,
+	"StatusInsufficientStorage":           http.StatusInsufficientStorage,
+	"StatusLoopDetected":                  http.StatusLoopDetected,
+	"StatusNotExtended":                   http.StatusNotExtended,
+	"StatusNetworkAuthenticationRequired": http.StatusNetworkAuthenticationRequired,
+}
+
+// CollectStatusConstants collects the constants and variables declared with
+// a single status code value, such as const created = http.StatusCreated,
+// across files. Values outside the 1xx-5xx range are ignored. Declarations referring to other collected names are resolved
+// too, regardless of their order.
+func CollectStatusConstants(files []*ast.File) map[string]int {
+	// Collect every single-valued declaration, including local ones
+	values := make(map[string]ast.Expr)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			genDecl, ok := n.(*ast.GenDecl)
+			if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+				return true
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok || len(valueSpec.Names) != len(valueSpec.Values) {
+					continue
+				}
+				for i, name := range valueSpec.Names {
+					values[name.Name] = valueSpec.Values[i]
+				}
+			}
+			return true
+		})
+	}
+
+	// Resolve them until no new status code is found
+	constants := make(map[string]int)
+	for found := true; found; {
+		found = false
+		for name, value := range values {
+			if _, resolved := constants[name]; resolved {
+				continue
+			}
+			if code, ok := ResolveStatusCode(value, constants); ok && code >= 100 && code <= 599 {
+				constants[name] = code
+				found = true
+			}
+		}
+	}
+
+	return constants
+}
+
+// ResolveStatusCode resolves an HTTP status code from an integer literal, a
+// net/http status constant, or one of the given named constants
+func ResolveStatusCode(expr ast.Expr, constants map[string]int) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			if code, err := strconv.Atoi(e.Value); err == nil {
+				return code, true
+			}
+		}
+	case *ast.SelectorExpr:
+		if ident, ok := e.X.(*ast.Ident); ok && ident.Name == "http" {
+			code, exists := HTTPStatusCodes[e.Sel.Name]
+			return code, exists
+		}
+	case *ast.Ident:
+		code, exists := constants[e.Name]
+		return code, exists
+	case *ast.ParenExpr:
+		return ResolveStatusCode(e.X, constants)
+	}
+	return 0, false
+}
@@ -1,9 +1,10 @@
 package types
 
 import (
-	"fmt"
 	"go/ast"
 	"go/token"
+
+	"github.com/user/golang-echo-analyzer/internal/logger"
 )
 
 // VariableInfo represents information about a variable
@@ -12,73 +13,341 @@ type VariableInfo struct {
 	Type      *TypeDefinition
 	IsPointer bool
 	Position  token.Position
+
+	// Marshaled is the type of the value encoded by json.Marshal, for byte
+	// slices holding its result
+	Marshaled *TypeDefinition
 }
 
-// VariableTracker tracks variable declarations and assignments in functions
+// VariableTracker tracks variable declarations and assignments in functions.
+// Variables are tracked per block, so a variable assigned in one branch of an
+// if statement, or shadowed in a nested block, only has that type in the
+// block; each identifier is resolved to the variable visible where it is used.
 type VariableTracker struct {
 	Registry    *TypeRegistry
-	Variables   map[string]*VariableInfo
+	Variables   map[string]*VariableInfo   // Last variable tracked under each name, in any scope
 	FunctionMap map[string]*TypeDefinition // Maps function names to their return types
-	Verbose     bool
+	Log         *logger.Logger
+
+	// Package is the package the tracked functions are declared in, whose
+	// types and imports their identifiers refer to. If empty, the registry's
+	// current package is used.
+	Package string
+
+	// scopes is the stack of block scopes while a function is being tracked
+	scopes []map[string]*VariableInfo
+
+	// uses maps identifiers in the tracked function to the variable visible
+	// where they're used
+	uses map[*ast.Ident]*VariableInfo
 }
 
 // NewVariableTracker creates a new VariableTracker
-func NewVariableTracker(registry *TypeRegistry, verbose bool) *VariableTracker {
+func NewVariableTracker(registry *TypeRegistry, log *logger.Logger) *VariableTracker {
 	return &VariableTracker{
 		Registry:    registry,
 		Variables:   make(map[string]*VariableInfo),
 		FunctionMap: make(map[string]*TypeDefinition),
-		Verbose:     verbose,
+		Log:         log,
+		uses:        make(map[*ast.Ident]*VariableInfo),
 	}
 }
 
 // TrackFunction tracks variables in a function
 func (t *VariableTracker) TrackFunction(funcDecl *ast.FuncDecl) error {
-	if t.Verbose {
-		fmt.Printf("Tracking variables in function: %s\n", funcDecl.Name.Name)
-	}
+	t.Log.Debugf("Tracking variables in function: %s", funcDecl.Name.Name)
 
 	// Clear previous variables
 	t.Variables = make(map[string]*VariableInfo)
+	t.uses = make(map[*ast.Ident]*VariableInfo)
+	t.scopes = nil
 
-	// Track function parameters
-	if funcDecl.Type.Params != nil {
-		for _, param := range funcDecl.Type.Params.List {
-			paramType := t.Registry.ResolveType(param.Type)
-			if paramType == nil {
-				continue
-			}
+	// The receiver and parameters are declared in the function scope, which
+	// is shared with the top-level statements of the body
+	t.pushScope()
+	defer t.popScope()
+	t.trackFields(funcDecl.Recv)
+	t.trackParams(funcDecl.Type)
 
-			for _, name := range param.Names {
-				varInfo := &VariableInfo{
-					Name:      name.Name,
-					Type:      paramType,
-					IsPointer: isPointerType(param.Type),
-					Position:  t.Registry.FileSet.Position(name.Pos()),
-				}
-				t.Variables[name.Name] = varInfo
+	// Track variables in the function body
+	if funcDecl.Body != nil {
+		t.trackStmts(funcDecl.Body.List)
+	}
 
-				if t.Verbose {
-					fmt.Printf("  Tracked parameter: %s of type %s\n", name.Name, paramType.Name)
-				}
-			}
+	return nil
+}
+
+// trackParams declares the parameters of a function in the current scope
+func (t *VariableTracker) trackParams(funcType *ast.FuncType) {
+	t.trackFields(funcType.Params)
+}
+
+// trackFields declares the named receiver or parameters of a function in the
+// current scope
+func (t *VariableTracker) trackFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	for _, param := range fields.List {
+		paramType := t.Registry.ResolveTypeIn(t.Package, param.Type)
+		if paramType == nil {
+			continue
+		}
+
+		for _, name := range param.Names {
+			t.setVariable(&VariableInfo{
+				Name:      name.Name,
+				Type:      paramType,
+				IsPointer: isPointerType(param.Type),
+				Position:  t.Registry.FileSet.Position(name.Pos()),
+			})
+
+			t.Log.Debugf("  Tracked parameter: %s of type %s", name.Name, paramType.Name)
 		}
 	}
+}
 
-	// Track variables in the function body
-	if funcDecl.Body != nil {
-		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+// pushScope opens a block scope
+func (t *VariableTracker) pushScope() {
+	t.scopes = append(t.scopes, make(map[string]*VariableInfo))
+}
+
+// popScope closes the innermost block scope, discarding the variables
+// declared and the assignments made in it
+func (t *VariableTracker) popScope() {
+	t.scopes = t.scopes[:len(t.scopes)-1]
+}
+
+// setVariable records a variable in the innermost scope. Assignments to
+// variables of outer scopes are recorded there too, so they're undone when
+// the block ends.
+func (t *VariableTracker) setVariable(varInfo *VariableInfo) {
+	t.scopes[len(t.scopes)-1][varInfo.Name] = varInfo
+	t.Variables[varInfo.Name] = varInfo
+}
+
+// lookupVariable finds the variable visible under a name in the current scopes
+func (t *VariableTracker) lookupVariable(name string) *VariableInfo {
+	for i := len(t.scopes) - 1; i >= 0; i-- {
+		if varInfo, exists := t.scopes[i][name]; exists {
+			return varInfo
+		}
+	}
+	return nil
+}
+
+// variable finds the variable an identifier refers to: the one visible in
+// the current scopes while tracking, or the one recorded for the identifier
+// afterwards
+func (t *VariableTracker) variable(ident *ast.Ident) *VariableInfo {
+	if len(t.scopes) > 0 {
+		return t.lookupVariable(ident.Name)
+	}
+	if varInfo, exists := t.uses[ident]; exists {
+		return varInfo
+	}
+	return t.Variables[ident.Name]
+}
+
+// trackStmts tracks the statements of a block in order
+func (t *VariableTracker) trackStmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		t.trackStmt(stmt)
+	}
+}
+
+// trackStmt tracks a statement, opening scopes for the blocks it contains
+func (t *VariableTracker) trackStmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		t.pushScope()
+		t.trackStmts(s.List)
+		t.popScope()
+
+	case *ast.AssignStmt:
+		t.trackUses(s.Rhs...)
+		t.trackAssignment(s)
+		t.trackUses(s.Lhs...)
+
+	case *ast.DeclStmt:
+		t.trackDeclaration(s)
+
+	case *ast.IfStmt:
+		t.pushScope()
+		if s.Init != nil {
+			t.trackStmt(s.Init)
+		}
+		t.trackUses(s.Cond)
+		t.trackStmt(s.Body)
+		if s.Else != nil {
+			t.trackStmt(s.Else)
+		}
+		t.popScope()
+
+	case *ast.ForStmt:
+		t.pushScope()
+		if s.Init != nil {
+			t.trackStmt(s.Init)
+		}
+		t.trackUses(s.Cond)
+		if s.Post != nil {
+			t.trackStmt(s.Post)
+		}
+		t.trackStmt(s.Body)
+		t.popScope()
+
+	case *ast.RangeStmt:
+		t.trackUses(s.X)
+		t.pushScope()
+		t.trackRange(s)
+		t.trackStmt(s.Body)
+		t.popScope()
+
+	case *ast.SwitchStmt:
+		t.pushScope()
+		if s.Init != nil {
+			t.trackStmt(s.Init)
+		}
+		t.trackUses(s.Tag)
+		for _, clause := range s.Body.List {
+			t.trackStmt(clause)
+		}
+		t.popScope()
+
+	case *ast.TypeSwitchStmt:
+		t.pushScope()
+		if s.Init != nil {
+			t.trackStmt(s.Init)
+		}
+		t.trackTypeSwitch(s)
+		t.popScope()
+
+	case *ast.SelectStmt:
+		for _, clause := range s.Body.List {
+			t.trackStmt(clause)
+		}
+
+	case *ast.CaseClause:
+		t.trackUses(s.List...)
+		t.pushScope()
+		t.trackStmts(s.Body)
+		t.popScope()
+
+	case *ast.CommClause:
+		t.pushScope()
+		if s.Comm != nil {
+			t.trackStmt(s.Comm)
+		}
+		t.trackStmts(s.Body)
+		t.popScope()
+
+	case *ast.LabeledStmt:
+		t.trackStmt(s.Stmt)
+
+	case *ast.ExprStmt:
+		t.trackUses(s.X)
+	case *ast.ReturnStmt:
+		t.trackUses(s.Results...)
+	case *ast.GoStmt:
+		t.trackUses(s.Call)
+	case *ast.DeferStmt:
+		t.trackUses(s.Call)
+	case *ast.SendStmt:
+		t.trackUses(s.Chan, s.Value)
+	case *ast.IncDecStmt:
+		t.trackUses(s.X)
+	}
+}
+
+// trackUses records the variable each identifier in the expressions refers
+// to, and tracks the bodies of function literals in nested scopes
+func (t *VariableTracker) trackUses(exprs ...ast.Expr) {
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		ast.Inspect(expr, func(n ast.Node) bool {
 			switch node := n.(type) {
-			case *ast.AssignStmt:
-				t.trackAssignment(node)
-			case *ast.DeclStmt:
-				t.trackDeclaration(node)
+			case *ast.Ident:
+				if varInfo := t.lookupVariable(node.Name); varInfo != nil {
+					t.uses[node] = varInfo
+				}
+			case *ast.SelectorExpr:
+				// The selected name is a field or method, not a variable
+				t.trackUses(node.X)
+				return false
+			case *ast.FuncLit:
+				t.pushScope()
+				t.trackParams(node.Type)
+				t.trackStmts(node.Body.List)
+				t.popScope()
+				return false
 			}
 			return true
 		})
 	}
+}
 
-	return nil
+// trackRange declares the value variable of a range statement over a map,
+// slice or array
+func (t *VariableTracker) trackRange(stmt *ast.RangeStmt) {
+	if stmt.Tok != token.DEFINE {
+		return
+	}
+
+	ident, ok := stmt.Value.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return
+	}
+
+	valueType := indexedType(t.resolveExpressionType(stmt.X))
+	if valueType == nil {
+		return
+	}
+	t.setVariable(&VariableInfo{
+		Name:      ident.Name,
+		Type:      valueType,
+		IsPointer: valueType.Kind == KindPointer,
+		Position:  t.Registry.FileSet.Position(ident.Pos()),
+	})
+	t.uses[ident] = t.lookupVariable(ident.Name)
+}
+
+// trackTypeSwitch tracks the clauses of a type switch. In a clause listing a
+// single type, the variable bound by the switch has that type.
+func (t *VariableTracker) trackTypeSwitch(stmt *ast.TypeSwitchStmt) {
+	var bound *ast.Ident
+	switch assign := stmt.Assign.(type) {
+	case *ast.AssignStmt:
+		if len(assign.Lhs) == 1 && len(assign.Rhs) == 1 {
+			bound, _ = assign.Lhs[0].(*ast.Ident)
+			t.trackUses(assign.Rhs...)
+		}
+	case *ast.ExprStmt:
+		t.trackUses(assign.X)
+	}
+
+	for _, stmt := range stmt.Body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+
+		t.pushScope()
+		if bound != nil && len(clause.List) == 1 {
+			if clauseType := t.Registry.ResolveTypeIn(t.Package, clause.List[0]); clauseType != nil {
+				t.setVariable(&VariableInfo{
+					Name:      bound.Name,
+					Type:      clauseType,
+					IsPointer: isPointerType(clause.List[0]),
+					Position:  t.Registry.FileSet.Position(clause.Pos()),
+				})
+			}
+		}
+		t.trackStmts(clause.Body)
+		t.popScope()
+	}
 }
 
 // trackAssignment tracks variable assignments
@@ -92,15 +361,31 @@ func (t *VariableTracker) trackAssignment(stmt *ast.AssignStmt) {
 	for i, lhs := range stmt.Lhs {
 		if ident, ok := lhs.(*ast.Ident); ok {
 			// Get the type from the right side
-			var rhsType *TypeDefinition
+			var rhsExpr ast.Expr
 			if i < len(stmt.Rhs) {
-				rhsType = t.resolveExpressionType(stmt.Rhs[i])
-			} else if len(stmt.Rhs) == 1 {
-				// Multiple assignment from a single value (e.g., a, b := returnsTwoValues())
-				rhsType = t.resolveExpressionType(stmt.Rhs[0])
-				// TODO: Handle multiple return values properly
+				rhsExpr = stmt.Rhs[i]
+			} else if len(stmt.Rhs) == 1 && i == 0 {
+				// Multiple assignment from a single call (e.g., user, err := repo.FindUser(id)),
+				// where the first result is the value and the others are usually an error
+				rhsExpr = stmt.Rhs[0]
+			}
+			if rhsExpr == nil {
+				continue
+			}
+
+			// JSON encoded values (e.g., data, err := json.Marshal(user)) keep
+			// the type of the value they encode
+			if marshaled := t.marshaledType(rhsExpr); marshaled != nil {
+				t.setVariable(&VariableInfo{
+					Name:      ident.Name,
+					Type:      t.Registry.ResolveTypeIn(t.Package, &ast.ArrayType{Elt: ast.NewIdent("byte")}),
+					Position:  t.Registry.FileSet.Position(ident.Pos()),
+					Marshaled: marshaled,
+				})
+				continue
 			}
 
+			rhsType := t.resolveExpressionType(rhsExpr)
 			if rhsType == nil {
 				continue
 			}
@@ -109,18 +394,33 @@ func (t *VariableTracker) trackAssignment(stmt *ast.AssignStmt) {
 			varInfo := &VariableInfo{
 				Name:      ident.Name,
 				Type:      rhsType,
-				IsPointer: isPointerType(stmt.Rhs[i]),
+				IsPointer: isPointerType(rhsExpr),
 				Position:  t.Registry.FileSet.Position(ident.Pos()),
 			}
-			t.Variables[ident.Name] = varInfo
+			t.setVariable(varInfo)
 
-			if t.Verbose {
-				fmt.Printf("  Tracked assignment: %s = %s\n", ident.Name, rhsType.Name)
-			}
+			t.Log.Debugf("  Tracked assignment: %s = %s", ident.Name, rhsType.Name)
 		}
 	}
 }
 
+// marshaledType returns the type of the value encoded by a json.Marshal or
+// json.MarshalIndent call, or nil if the expression isn't such a call
+func (t *VariableTracker) marshaledType(expr ast.Expr) *TypeDefinition {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Marshal" && sel.Sel.Name != "MarshalIndent") {
+		return nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "json" || t.variable(pkg) != nil {
+		return nil
+	}
+	return t.resolveExpressionType(call.Args[0])
+}
+
 // trackDeclaration tracks variable declarations
 func (t *VariableTracker) trackDeclaration(stmt *ast.DeclStmt) {
 	genDecl, ok := stmt.Decl.(*ast.GenDecl)
@@ -134,10 +434,12 @@ func (t *VariableTracker) trackDeclaration(stmt *ast.DeclStmt) {
 			continue
 		}
 
+		t.trackUses(valueSpec.Values...)
+
 		// Get the type from the value spec
 		var varType *TypeDefinition
 		if valueSpec.Type != nil {
-			varType = t.Registry.ResolveType(valueSpec.Type)
+			varType = t.Registry.ResolveTypeIn(t.Package, valueSpec.Type)
 		} else if len(valueSpec.Values) > 0 {
 			// Infer type from the first value
 			varType = t.resolveExpressionType(valueSpec.Values[0])
@@ -155,11 +457,9 @@ func (t *VariableTracker) trackDeclaration(stmt *ast.DeclStmt) {
 				IsPointer: isPointerType(valueSpec.Type),
 				Position:  t.Registry.FileSet.Position(name.Pos()),
 			}
-			t.Variables[name.Name] = varInfo
+			t.setVariable(varInfo)
 
-			if t.Verbose {
-				fmt.Printf("  Tracked declaration: %s of type %s\n", name.Name, varType.Name)
-			}
+			t.Log.Debugf("  Tracked declaration: %s of type %s", name.Name, varType.Name)
 		}
 	}
 }
@@ -169,36 +469,32 @@ func (t *VariableTracker) resolveExpressionType(expr ast.Expr) *TypeDefinition {
 	switch e := expr.(type) {
 	case *ast.Ident:
 		// Variable reference
-		if varInfo, exists := t.Variables[e.Name]; exists {
+		if varInfo := t.variable(e); varInfo != nil {
 			return varInfo.Type
 		}
 		// It might be a type name
-		return t.Registry.LookupType(e.Name)
+		return t.Registry.LookupTypeIn(t.Package, e.Name)
 
 	case *ast.SelectorExpr:
-		// Field access (e.g., user.Name) or package qualified name (e.g., models.User)
-		if x, ok := e.X.(*ast.Ident); ok {
-			// Check if it's a package qualified name
+		// Package qualified name (e.g., models.User)
+		if x, ok := e.X.(*ast.Ident); ok && t.variable(x) == nil {
 			qualifiedName := x.Name + "." + e.Sel.Name
-			if typeDef := t.Registry.LookupType(qualifiedName); typeDef != nil {
+			if typeDef := t.Registry.LookupTypeIn(t.Package, qualifiedName); typeDef != nil {
 				return typeDef
 			}
-
-			// Check if it's a field access
-			if varInfo, exists := t.Variables[x.Name]; exists && varInfo.Type.Kind == KindStruct {
-				// Find the field in the struct
-				for _, field := range varInfo.Type.Fields {
-					if field.Name == e.Sel.Name {
-						return field.Type
-					}
-				}
-			}
 		}
 
+		// Field access on a variable or on another expression (e.g., user.Name, h.repo.cache)
+		return fieldType(t.resolveExpressionType(e.X), e.Sel.Name)
+
 	case *ast.CallExpr:
 		// Function call
 		return t.resolveFunctionCallType(e)
 
+	case *ast.IndexExpr:
+		// Map or slice element (e.g., cache[id], users[0])
+		return indexedType(t.resolveExpressionType(e.X))
+
 	case *ast.UnaryExpr:
 		// Unary expression (e.g., &user)
 		if e.Op == token.AND {
@@ -217,7 +513,7 @@ func (t *VariableTracker) resolveExpressionType(expr ast.Expr) *TypeDefinition {
 
 	case *ast.CompositeLit:
 		// Composite literal (e.g., User{Name: "John"})
-		return t.Registry.ResolveType(e.Type)
+		return t.compositeLitType(e)
 
 	case *ast.BasicLit:
 		// Basic literal (e.g., "string", 123)
@@ -265,43 +561,117 @@ func (t *VariableTracker) resolveFunctionCallType(call *ast.CallExpr) *TypeDefin
 	// Handle function calls
 	switch fun := call.Fun.(type) {
 	case *ast.Ident:
+		// Allocation with new(Type), unless new is shadowed by a variable
+		// or a function declared in the analyzed code
+		if fun.Name == "new" && len(call.Args) == 1 && t.isBuiltinNew(fun) {
+			if elemType := t.Registry.ResolveTypeIn(t.Package, call.Args[0]); elemType != nil {
+				return &TypeDefinition{
+					Name:        "*" + elemType.Name,
+					Kind:        KindPointer,
+					ElementType: elemType,
+					Package:     elemType.Package,
+					IsResolved:  elemType.IsResolved,
+				}
+			}
+		}
+
 		// Direct function call
 		if returnType, exists := t.FunctionMap[fun.Name]; exists {
 			return returnType
 		}
+		if returnType := t.Registry.LookupFunctionReturnTypeIn(t.Package, fun.Name); returnType != nil {
+			return returnType
+		}
 
 	case *ast.SelectorExpr:
 		// Method call or function from another package
 		if x, ok := fun.X.(*ast.Ident); ok {
-			// Check if it's a method call on a variable
-			if _, exists := t.Variables[x.Name]; exists {
-				// TODO: Look up method in the type's methods
-				// For now, return a placeholder
-				return &TypeDefinition{
-					Name:       "any",
-					Kind:       KindBasic,
-					BasicType:  "any",
-					Package:    "",
-					IsResolved: true,
+			if t.variable(x) == nil {
+				// Errors created or wrapped with the standard library
+				if errorConstructors[x.Name+"."+fun.Sel.Name] {
+					return errorType()
+				}
+
+				// Check if it's a function from another package
+				funcName := x.Name + "." + fun.Sel.Name
+				if returnType, exists := t.FunctionMap[funcName]; exists {
+					return returnType
+				}
+				if returnType := t.Registry.LookupFunctionReturnTypeIn(t.Package, funcName); returnType != nil {
+					return returnType
 				}
 			}
+		}
 
-			// Check if it's a function from another package
-			funcName := x.Name + "." + fun.Sel.Name
-			if returnType, exists := t.FunctionMap[funcName]; exists {
+		// Method call on a variable or on the result of another call, which
+		// resolves builder chains like NewResponse().WithData(users).Build()
+		if recvType := t.resolveExpressionType(fun.X); recvType != nil {
+			if returnType := t.Registry.LookupMethodReturnType(recvType, fun.Sel.Name); returnType != nil {
 				return returnType
 			}
 		}
 	}
 
 	// If we can't determine the return type, return a placeholder
-	return &TypeDefinition{
-		Name:       "any",
-		Kind:       KindBasic,
-		BasicType:  "any",
-		Package:    "",
-		IsResolved: true,
+	return UnknownType()
+}
+
+// errorConstructors are the standard library functions returning an error,
+// such as fmt.Errorf wrapping another one
+var errorConstructors = map[string]bool{
+	"errors.New":    true,
+	"errors.Join":   true,
+	"errors.Unwrap": true,
+	"fmt.Errorf":    true,
+}
+
+// isBuiltinNew reports whether an identifier named new refers to the builtin
+func (t *VariableTracker) isBuiltinNew(ident *ast.Ident) bool {
+	if t.variable(ident) != nil {
+		return false
+	}
+	if _, exists := t.FunctionMap[ident.Name]; exists {
+		return false
+	}
+	return t.Registry.LookupFunctionReturnTypeIn(t.Package, ident.Name) == nil
+}
+
+// fieldType returns the type of a field of a struct, or of the struct a
+// pointer points to, or nil if there is no such field
+func fieldType(typeDef *TypeDefinition, name string) *TypeDefinition {
+	for typeDef != nil && typeDef.Kind == KindPointer {
+		typeDef = typeDef.ElementType
+	}
+	if typeDef == nil || typeDef.Kind != KindStruct {
+		return nil
+	}
+
+	for _, field := range typeDef.Fields {
+		if field.Name == name {
+			return field.Type
+		}
+	}
+	return nil
+}
+
+// indexedType returns the element type of an indexed map, slice or array
+// type, or nil if the type can't be indexed
+func indexedType(typeDef *TypeDefinition) *TypeDefinition {
+	// Arrays can be indexed through a pointer
+	if typeDef != nil && typeDef.Kind == KindPointer && typeDef.ElementType != nil && typeDef.ElementType.Kind == KindArray {
+		typeDef = typeDef.ElementType
 	}
+	if typeDef == nil {
+		return nil
+	}
+
+	switch typeDef.Kind {
+	case KindMap:
+		return typeDef.ValueType
+	case KindArray:
+		return typeDef.ElementType
+	}
+	return nil
 }
 
 // GetVariableType gets the type of a variable
@@ -312,10 +682,26 @@ func (t *VariableTracker) GetVariableType(name string) *TypeDefinition {
 	return nil
 }
 
+// VariableTypeOf gets the type of the variable an identifier refers to where
+// it's used in the last tracked function
+func (t *VariableTracker) VariableTypeOf(ident *ast.Ident) *TypeDefinition {
+	if varInfo := t.variable(ident); varInfo != nil {
+		return varInfo.Type
+	}
+	return nil
+}
+
+// MarshaledTypeOf gets the type of the value JSON encoded into the variable
+// an identifier refers to, e.g. User for data, err := json.Marshal(user)
+func (t *VariableTracker) MarshaledTypeOf(ident *ast.Ident) *TypeDefinition {
+	if varInfo := t.variable(ident); varInfo != nil {
+		return varInfo.Marshaled
+	}
+	return nil
+}
+
 // RegisterFunctionReturnType registers the return type of a function
 func (t *VariableTracker) RegisterFunctionReturnType(funcName string, returnType *TypeDefinition) {
 	t.FunctionMap[funcName] = returnType
-	if t.Verbose {
-		fmt.Printf("Registered function return type: %s -> %s\n", funcName, returnType.Name)
-	}
+	t.Log.Debugf("Registered function return type: %s -> %s", funcName, returnType.Name)
 }
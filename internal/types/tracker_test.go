@@ -0,0 +1,129 @@
+package types
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// trackedResponses tracks the variables of the handler of a source file and
+// returns the types of the identifiers passed to c.JSON, in source order
+func trackedResponses(t *testing.T, source, handler string) []*TypeDefinition {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := NewTypeRegistry(fset, nil)
+	collector := NewTypeCollector(registry, nil)
+	if err := collector.CollectTypes([]*ast.File{file}, "main"); err != nil {
+		t.Fatalf("CollectTypes() = %v", err)
+	}
+	if err := collector.ResolveTypes(); err != nil {
+		t.Fatalf("ResolveTypes() = %v", err)
+	}
+
+	var funcDecl *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == handler {
+			funcDecl = fn
+		}
+	}
+	if funcDecl == nil {
+		t.Fatalf("no function %s", handler)
+	}
+	tracker := NewVariableTracker(registry, nil)
+	tracker.Package = "main"
+	if err := tracker.TrackFunction(funcDecl); err != nil {
+		t.Fatalf("TrackFunction() = %v", err)
+	}
+
+	var responses []*TypeDefinition
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "JSON" {
+			if ident, ok := call.Args[1].(*ast.Ident); ok {
+				responses = append(responses, tracker.VariableTypeOf(ident))
+			}
+		}
+		return true
+	})
+	return responses
+}
+
+// assertTypeNames fails the test unless the types have the given names
+func assertTypeNames(t *testing.T, got []*TypeDefinition, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("found %d c.JSON calls, want %d", len(got), len(want))
+	}
+	for i, typ := range got {
+		if typ == nil || typ.Name != want[i] {
+			t.Errorf("c.JSON call %d responds with %+v, want %s", i, typ, want[i])
+		}
+	}
+}
+
+const branchSource = `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type User struct {
+	Name string
+}
+
+type ErrorResponse struct {
+	Error string
+}
+
+func assigned(c echo.Context) error {
+	var resp interface{}
+	if c.Param("id") == "" {
+		resp = ErrorResponse{Error: "missing id"}
+		return c.JSON(http.StatusBadRequest, resp)
+	} else {
+		resp = User{Name: c.Param("id")}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func declared(c echo.Context) error {
+	if c.Param("id") == "" {
+		resp := ErrorResponse{Error: "missing id"}
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	resp := User{Name: c.Param("id")}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func shadowed(c echo.Context) error {
+	resp := User{Name: c.Param("id")}
+	if c.QueryParam("fail") != "" {
+		resp := ErrorResponse{Error: "failed"}
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+`
+
+func TestVariableTrackerBranches(t *testing.T) {
+	// resp is assigned an ErrorResponse in the if branch and a User in the else branch
+	assertTypeNames(t, trackedResponses(t, branchSource, "assigned"), "ErrorResponse", "User")
+
+	// resp is declared in the if branch, then again after it
+	assertTypeNames(t, trackedResponses(t, branchSource, "declared"), "ErrorResponse", "User")
+
+	// The resp of the if branch shadows the outer one only in the branch
+	assertTypeNames(t, trackedResponses(t, branchSource, "shadowed"), "ErrorResponse", "User")
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// validateTag returns the validate tag of a struct field, as used by
+// go-playground/validator (e.g. validate:"required,min=3")
+func validateTag(field *ast.Field) string {
+	return fieldTag(field, "validate")
+}
+
+// applyValidationConstraints adds the constraints of a validate tag to a
+// property schema and reports whether the tag makes the field required.
+// Length rules constrain strings and arrays; value rules constrain numbers.
+func applyValidationConstraints(property *JSONSchemaProperty, validate string) bool {
+	required := false
+
+	for _, rule := range strings.Split(validate, ",") {
+		name, param := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			property.Format = JSONSchemaFormatEmail
+		case "url", "uri":
+			property.Format = JSONSchemaFormatURI
+		case "uuid", "uuid4":
+			property.Format = JSONSchemaFormatUUID
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				property.Enum = append(property.Enum, enumValue(property.Type, value))
+			}
+		case "len":
+			setBound(property, param, true, true)
+		case "min", "gte":
+			setBound(property, param, true, false)
+		case "max", "lte":
+			setBound(property, param, false, true)
+		case "gt":
+			if isNumericSchema(property.Type) {
+				setBound(property, param, true, false)
+				property.ExclusiveMinimum = property.Minimum != nil
+			} else {
+				setBound(property, offsetParam(param, 1), true, false)
+			}
+		case "lt":
+			if isNumericSchema(property.Type) {
+				setBound(property, param, false, true)
+				property.ExclusiveMaximum = property.Maximum != nil
+			} else {
+				setBound(property, offsetParam(param, -1), false, true)
+			}
+		}
+	}
+
+	return required
+}
+
+// setBound sets the lower and/or upper bound of a property: its length for
+// strings, its number of items for arrays, and its value for numbers
+func setBound(property *JSONSchemaProperty, param string, lower, upper bool) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	n := int(value)
+	switch property.Type {
+	case JSONSchemaTypeString:
+		if lower {
+			property.MinLength = &n
+		}
+		if upper {
+			property.MaxLength = &n
+		}
+	case JSONSchemaTypeArray:
+		if lower {
+			property.MinItems = &n
+		}
+		if upper {
+			property.MaxItems = &n
+		}
+	case JSONSchemaTypeInteger, JSONSchemaTypeNumber:
+		if lower {
+			property.Minimum = &value
+		}
+		if upper {
+			property.Maximum = &value
+		}
+	}
+}
+
+// isNumericSchema checks if a schema type is integer or number
+func isNumericSchema(schemaType JSONSchemaType) bool {
+	return schemaType == JSONSchemaTypeInteger || schemaType == JSONSchemaTypeNumber
+}
+
+// offsetParam adds an offset to an integer rule parameter, turning the
+// exclusive length bounds of gt and lt into inclusive ones
+func offsetParam(param string, offset int) string {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return param
+	}
+	return strconv.Itoa(n + offset)
+}
+
+// enumValue converts a oneof value to the type of the property
+func enumValue(schemaType JSONSchemaType, value string) interface{} {
+	if isNumericSchema(schemaType) {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
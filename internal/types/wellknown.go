@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WellKnownType describes how a type from outside the analyzed code, such as
+// uuid.UUID, is represented in JSON
+type WellKnownType struct {
+	Schema  *JSONSchema
+	Example interface{}
+}
+
+// DefaultWellKnownTypes maps qualified type names, as written in the source,
+// to their JSON representation
+var DefaultWellKnownTypes = map[string]WellKnownType{
+	"time.Time": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeString, Format: JSONSchemaFormatDateTime},
+		Example: "2025-04-23T01:27:02Z",
+	},
+	"time.Duration": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeInteger, Format: JSONSchemaFormatInt64, Description: "Duration in nanoseconds"},
+		Example: 1000000000,
+	},
+	"json.RawMessage": {
+		Schema:  &JSONSchema{Description: "Arbitrary JSON value"},
+		Example: map[string]interface{}{},
+	},
+	"json.Number": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeNumber},
+		Example: 0,
+	},
+	"url.URL": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeString, Format: JSONSchemaFormatURI},
+		Example: "https://example.com",
+	},
+	"net.IP": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeString, Description: "IP address"},
+		Example: "192.0.2.1",
+	},
+	"uuid.UUID": {
+		Schema:  &JSONSchema{Type: JSONSchemaTypeString, Format: JSONSchemaFormatUUID},
+		Example: "123e4567-e89b-12d3-a456-426614174000",
+	},
+	"decimal.Decimal": {
+		Schema: &JSONSchema{
+			Description: "Decimal number, encoded as a string unless configured otherwise",
+			OneOf: []*JSONSchema{
+				{Type: JSONSchemaTypeString},
+				{Type: JSONSchemaTypeNumber},
+			},
+		},
+		Example: "12.34",
+	},
+}
+
+// RegisterWellKnownType maps a qualified type name to a schema, overriding
+// the default mapping if there is one
+func (g *SchemaGenerator) RegisterWellKnownType(name string, wellKnown WellKnownType) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.WellKnownTypes[name] = wellKnown
+}
+
+// ParseTypeMapping parses a well-known type mapping of the form
+// Name=type[:format] (e.g. uuid.UUID=string:uuid)
+func ParseTypeMapping(spec string) (string, WellKnownType, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", WellKnownType{}, fmt.Errorf("invalid type mapping %q, expected Name=type[:format]", spec)
+	}
+
+	schemaType, format := parts[1], ""
+	if i := strings.Index(schemaType, ":"); i >= 0 {
+		schemaType, format = schemaType[:i], schemaType[i+1:]
+	}
+
+	var example interface{}
+	switch JSONSchemaType(schemaType) {
+	case JSONSchemaTypeString:
+		example = "string"
+	case JSONSchemaTypeNumber, JSONSchemaTypeInteger:
+		example = 0
+	case JSONSchemaTypeBoolean:
+		example = false
+	case JSONSchemaTypeObject:
+		example = map[string]interface{}{}
+	case JSONSchemaTypeArray:
+		example = []interface{}{}
+	default:
+		return "", WellKnownType{}, fmt.Errorf("invalid schema type %q in type mapping %q", schemaType, spec)
+	}
+
+	return parts[0], WellKnownType{
+		Schema:  &JSONSchema{Type: JSONSchemaType(schemaType), Format: JSONSchemaFormat(format)},
+		Example: example,
+	}, nil
+}
+
+// lookupWellKnownType returns the mapping of a type, if it is well known
+func (g *SchemaGenerator) lookupWellKnownType(typeDef *TypeDefinition) (WellKnownType, bool) {
+	wellKnown, exists := g.WellKnownTypes[typeDef.Name]
+	return wellKnown, exists
+}
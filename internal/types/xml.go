@@ -0,0 +1,173 @@
+package types
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// XMLObject describes how a schema is represented in XML, as the OpenAPI
+// xml keyword
+type XMLObject struct {
+	Name      string `json:"name,omitempty"`
+	Attribute bool   `json:"attribute,omitempty"`
+}
+
+// GenerateXMLSchema generates a schema for a type as encoding/xml encodes it:
+// properties are named after the xml tags of fields, or else the Go field
+// names, and attributes are marked with the xml keyword. The root element is
+// named after the type's XMLName field, or else the type.
+func (g *SchemaGenerator) GenerateXMLSchema(typeDef *TypeDefinition) *JSONSchema {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.xml = true
+	defer func() { g.xml = false }()
+
+	schema := g.generateSchema(typeDef)
+	if schema == nil {
+		return nil
+	}
+
+	name := xmlElementName(typeDef)
+	if name == "" {
+		return schema
+	}
+
+	// Name the root element on a copy, since the cached schema is shared
+	// with the fields of this type
+	root := *schema
+	root.XML = &XMLObject{Name: name}
+	return &root
+}
+
+// GenerateXMLSchemaString generates an XML schema for a type as an indented
+// JSON string
+func (g *SchemaGenerator) GenerateXMLSchemaString(typeDef *TypeDefinition) (string, error) {
+	return marshalJSONSchema(g.GenerateXMLSchema(typeDef))
+}
+
+// xmlElementName returns the element name encoding/xml uses for a value of a
+// type: the name in the XMLName field's tag, or else the type name. Slices are
+// encoded as a sequence of elements named after their element type. It is
+// empty for types that can't be statically determined.
+func xmlElementName(typeDef *TypeDefinition) string {
+	for typeDef.ElementType != nil && (typeDef.Kind == KindPointer || typeDef.Kind == KindArray) {
+		typeDef = typeDef.ElementType
+	}
+	if typeDef.Kind == KindUnknown {
+		return ""
+	}
+	if typeDef.XMLName != "" {
+		return typeDef.XMLName
+	}
+	return typeDef.Name
+}
+
+// GenerateExampleXML generates an example XML document for a type definition
+func (g *SchemaGenerator) GenerateExampleXML(typeDef *TypeDefinition) (string, error) {
+	if typeDef == nil {
+		return "", fmt.Errorf("failed to generate XML example for a nil type")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if name := xmlElementName(typeDef); name != "" {
+		if err := g.encodeXMLExample(encoder, name, typeDef); err != nil {
+			return "", err
+		}
+	} else if err := encoder.EncodeToken(xml.Comment(" " + UnknownTypeDescription + " ")); err != nil {
+		return "", err
+	}
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// encodeXMLExample writes an example element of a type, with the lock held
+func (g *SchemaGenerator) encodeXMLExample(encoder *xml.Encoder, name string, typeDef *TypeDefinition) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	// Types with a well-known representation are encoded as text
+	if wellKnown, exists := g.lookupWellKnownType(typeDef); exists {
+		return encoder.EncodeElement(fmt.Sprint(wellKnown.Example), start)
+	}
+
+	switch typeDef.Kind {
+	case KindPointer, KindArray:
+		// Slices are encoded as repeated elements; one stands for all
+		if typeDef.ElementType != nil {
+			return g.encodeXMLExample(encoder, name, typeDef.ElementType)
+		}
+		return nil
+	case KindMap:
+		// encoding/xml doesn't support maps
+		return nil
+	case KindBasic:
+		return encoder.EncodeElement(fmt.Sprint(g.generateBasicExample(typeDef)), start)
+	case KindStruct:
+		return g.encodeXMLStructExample(encoder, start, typeDef)
+	}
+
+	// Interfaces and unknown types are left empty
+	return encoder.EncodeElement("", start)
+}
+
+// encodeXMLStructExample writes an example element of a struct type, with its
+// attributes and child elements
+func (g *SchemaGenerator) encodeXMLStructExample(encoder *xml.Encoder, start xml.StartElement, typeDef *TypeDefinition) error {
+	// Break cycles in recursive types with an empty element
+	exampleKey := "xml-example:" + typeDef.Package + "." + typeDef.Name
+	if g.inProgress[exampleKey] {
+		return encoder.EncodeElement("", start)
+	}
+	g.inProgress[exampleKey] = true
+	defer delete(g.inProgress, exampleKey)
+
+	children := []*FieldDefinition{}
+	for _, field := range typeDef.Fields {
+		if field.Type == nil || field.XMLName == "-" || field.Name == "XMLName" {
+			continue
+		}
+		if !field.XMLAttr {
+			children = append(children, field)
+			continue
+		}
+
+		value := field.Example
+		if value == "" {
+			value = fmt.Sprint(g.generateExample(field.Type))
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: xmlFieldName(field)}, Value: value})
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range children {
+		var err error
+		if field.Example != "" {
+			err = encoder.EncodeElement(field.Example, xml.StartElement{Name: xml.Name{Local: xmlFieldName(field)}})
+		} else {
+			err = g.encodeXMLExample(encoder, xmlFieldName(field), field.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlFieldName returns the element or attribute name of a struct field
+func xmlFieldName(field *FieldDefinition) string {
+	if field.XMLName != "" {
+		return field.XMLName
+	}
+	return field.Name
+}
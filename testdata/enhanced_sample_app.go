@@ -0,0 +1,1329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// User represents a user in the system
+type User struct {
+	// Unique identifier of the user
+	ID   int    `json:"id"`
+	Name string `json:"name"` // Full name
+	// Contact email address, omitted when the user hasn't provided one
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Profile   *Profile  `json:"profile,omitempty"`
+}
+
+// Profile represents a user profile
+type Profile struct {
+	Bio    string   `json:"bio,omitempty" example:"Software Engineer" description:"Short biography"`
+	Skills []string `json:"skills" example:"Go,Docker"`
+}
+
+// Address represents a physical address
+type Address struct {
+	Street  string `json:"street"`
+	City    string `json:"city"`
+	State   string `json:"state"`
+	ZipCode string `json:"zip_code"`
+	Country string `json:"country"`
+}
+
+// ReviewRequest is bound from the path, query string, headers and body
+type ReviewRequest struct {
+	ProductID int    `param:"id"`
+	Notify    bool   `query:"notify"`
+	Locale    string `header:"Accept-Language"`
+	Rating    int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// Product represents a product in the system
+type Product struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name" validate:"required,min=3,max=100"`
+	Description string            `json:"description,omitempty" validate:"max=1000"`
+	Price       float64           `json:"price" validate:"gt=0"`
+	Categories  []string          `json:"categories" validate:"min=1"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Inventory   *ProductInventory `json:"inventory,omitempty"`
+	Condition   string            `json:"condition,omitempty" validate:"oneof=new used refurbished"`
+}
+
+// CatalogEntry is a product as listed in the XML catalog feed
+type CatalogEntry struct {
+	XMLName  xml.Name `xml:"product"`
+	SKU      string   `xml:"sku,attr" example:"SKU-1001"`
+	Name     string   `xml:"name"`
+	Price    float64  `xml:"price"`
+	Tags     []string `xml:"tag,omitempty"`
+	Internal string   `xml:"-"`
+}
+
+// ProductInventory represents inventory information for a product
+type ProductInventory struct {
+	Quantity  int  `json:"quantity" example:"42"`
+	Available bool `json:"available" example:"true"`
+}
+
+// Order represents a customer order
+type Order struct {
+	ID              int             `json:"id"`
+	UserID          int             `json:"user_id"`
+	Items           OrderItems      `json:"items"`
+	TotalPrice      float64         `json:"total_price"`
+	Status          string          `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ShippingAddress Address         `json:"shipping_address"`
+	ShippedAt       *time.Time      `json:"shipped_at"`
+	TrackingNumber  string          `json:"tracking_number,omitempty"`
+	Payment         Payment         `json:"payment,omitempty"`
+	Metadata        interface{}     `json:"metadata,omitempty"`
+	Attributes      Attributes      `json:"attributes,omitempty"`
+	Reference       uuid.UUID       `json:"reference"`
+	ProcessingTime  time.Duration   `json:"processing_time"`
+	Discount        decimal.Decimal `json:"discount"`
+	Extra           json.RawMessage `json:"extra,omitempty"`
+	Priority        Priority        `json:"priority"`
+	DeliveryWindow  DeliveryWindow  `json:"delivery_window,omitempty"`
+}
+
+// Priority is the shipping priority of an order, encoded as its name
+type Priority int
+
+// MarshalJSON encodes the priority as its name
+func (p Priority) MarshalJSON() ([]byte, error) {
+	names := []string{"standard", "express"}
+	return json.Marshal(names[p])
+}
+
+// DeliveryWindow is the time range an order is delivered in, encoded as
+// "15:00-18:00"
+//
+// schema: {"type": "string", "description": "delivery time range, e.g. 15:00-18:00"}
+type DeliveryWindow struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// MarshalJSON encodes the window as a "from-to" string
+func (w DeliveryWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%02d:00-%02d:00", int(w.From.Hours()), int(w.To.Hours())))
+}
+
+// Attributes holds free-form order attributes
+type Attributes map[string]string
+
+// Payment is implemented by the supported payment methods
+type Payment interface {
+	Method() string
+}
+
+// CardPayment represents a credit card payment
+type CardPayment struct {
+	Last4 string `json:"last4"`
+	Brand string `json:"brand"`
+}
+
+// Method returns the payment method name
+func (p CardPayment) Method() string {
+	return "card"
+}
+
+// BankTransfer represents a bank transfer payment
+type BankTransfer struct {
+	IBAN      string `json:"iban"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// Method returns the payment method name
+func (p *BankTransfer) Method() string {
+	return "bank_transfer"
+}
+
+// OrderItems is the list of items in an order
+type OrderItems []OrderItem
+
+// OrderItem represents an item in an order
+type OrderItem struct {
+	ProductID int     `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// Page represents a page of results
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// UserListResponse represents a list of users with its total count
+type UserListResponse struct {
+	Data  []User `json:"data"`
+	Total int    `json:"total"`
+}
+
+// Envelope wraps every response of the enveloped routes
+type Envelope struct {
+	Data interface{}  `json:"data"`
+	Meta EnvelopeMeta `json:"meta"`
+}
+
+// EnvelopeMeta holds pagination metadata of an enveloped response
+type EnvelopeMeta struct {
+	Page  int `json:"page"`
+	Total int `json:"total"`
+}
+
+// UserListBuilder builds a UserListResponse
+type UserListBuilder struct {
+	users []User
+}
+
+// NewUserListBuilder creates a new UserListBuilder
+func NewUserListBuilder() *UserListBuilder {
+	return &UserListBuilder{}
+}
+
+// WithData sets the users of the response
+func (b *UserListBuilder) WithData(users []User) *UserListBuilder {
+	b.users = users
+	return b
+}
+
+// Build creates the response
+func (b *UserListBuilder) Build() UserListResponse {
+	return UserListResponse{Data: b.users, Total: len(b.users)}
+}
+
+// UserStore looks up users
+type UserStore interface {
+	FindUser(id string) (*User, error)
+}
+
+// memoryUserStore is a UserStore backed by a map
+type memoryUserStore struct {
+	users map[string]*User
+}
+
+// NewUserStore creates an empty UserStore
+func NewUserStore() UserStore {
+	return &memoryUserStore{users: map[string]*User{}}
+}
+
+func (s *memoryUserStore) FindUser(id string) (*User, error) {
+	if user, exists := s.users[id]; exists {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user %s not found", id)
+}
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code"`
+}
+
+// Route paths and methods declared as constants
+const (
+	apiVersion     = "/v1"
+	healthPath     = apiVersion + "/health"
+	methodPropfind = "PROPFIND"
+)
+
+// apiServer holds the groups routes are mounted on
+type apiServer struct {
+	internal *echo.Group
+}
+
+func main() {
+	// Create a new Echo instance
+	e := echo.New()
+
+	// Global middleware
+	e.Use(middleware.Logger(), middleware.Recover())
+
+	// Routes
+	e.GET("/", helloWorld)
+	e.GET("/users", getUsers)
+	// Kept for clients of the first version
+	// @deprecated
+	e.GET("/v0/users", getUsers)
+	e.GET("/users/featured", getFeaturedUsers)
+	e.GET("/users/newest", getNewestUser)
+	e.GET("/users/lookup", lookupUser)
+	e.GET("/users/cached", getCachedUsers)
+	e.GET("/users/search", searchUsers)
+	e.GET("/users/me", getCurrentUser, middleware.JWT([]byte("secret")))
+	e.GET("/users/default", func(c echo.Context) error {
+		lang := c.QueryParam("lang")
+		user := User{ID: 0, Name: "Guest", Email: "guest@example.com", CreatedAt: time.Now()}
+		if lang == "" {
+			return c.JSON(http.StatusOK, user)
+		}
+		return c.JSON(http.StatusNotFound, ErrorResponse{Error: "unsupported language", Code: 404})
+	})
+	e.GET("/users/:id", getUserByID).Name = "get-user"
+	e.GET("/users/:id/profile", getUserProfile)
+	e.GET("/users/:id/pretty", getUserPretty)
+	e.GET("/users/:id/export", exportUser)
+	e.POST("/users", createUser)
+	e.POST("/users/:id/avatar", uploadAvatar)
+	e.POST("/login", login, middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(10)))
+	e.PUT("/users/:id", updateUser)
+	e.DELETE("/users/:id", deleteUser, middleware.KeyAuth(validateAPIKey))
+
+	// Product routes
+	e.GET("/products", getProducts)
+	e.GET("/products/legacy", getLegacyProducts)
+	e.GET("/products/page", getProductPage)
+	e.GET("/products/catalog", getProductCatalog)
+	e.GET("/products/enveloped", getEnvelopedProducts)
+	e.GET("/products/index", getProductIndex)
+	productRoute := e.GET("/products/:id", getProductByID)
+	productRoute.Name = "get-product"
+	e.POST("/products", createProduct)
+	e.PUT("/products/:id", updateProduct)
+	e.POST("/products/:id/reviews", createReview)
+	e.POST("/products/search", searchProducts)
+	e.PUT("/products/:id/inventory", restockProduct)
+
+	// Order routes
+	registerOrderRoutes(e)
+
+	// Admin API, with groups nested across functions
+	admin := e.Group("/admin", requireAdmin)
+	registerAdminRoutes(admin.Group("/v1", middleware.RequestID()))
+	e.GET("/terms", getTerms)
+	e.GET("/docs", redirectToDocs)
+	e.GET("/legal", redirectToTerms)
+
+	// Static files and pages rendered from templates
+	e.Static("/assets", "public")
+	e.Group("/static").Static("/", "assets")
+	e.GET("/pages/welcome", renderWelcomePage)
+
+	// Groups held in struct fields and passed to function literals
+	srv := &apiServer{internal: e.Group("/internal")}
+	mountStatus := func(g *echo.Group) {
+		g.GET("/status", healthCheck)
+	}
+	mountStatus(srv.internal)
+
+	// Routes registered with constants
+	e.GET(healthPath, healthCheck)
+	e.Add(methodPropfind, "/files", listFiles)
+	e.Match([]string{http.MethodGet, http.MethodHead}, "/status", healthCheck)
+
+	// Routes registered through a helper (--registrar-method register:1:2:3)
+	register(e, http.MethodGet, "/version", getVersion, []echo.MiddlewareFunc{middleware.Gzip()}...)
+
+	// Fallback for unmatched paths and errors returned by handlers
+	e.RouteNotFound("/*", notFound)
+	e.HTTPErrorHandler = customHTTPErrorHandler
+
+	// Start server
+	e.Logger.Fatal(e.Start(":8080"))
+}
+
+// healthCheck reports that the service is up
+func healthCheck(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+// listFiles answers WebDAV PROPFIND requests
+func listFiles(c echo.Context) error {
+	return c.XMLBlob(http.StatusMultiStatus, []byte("<multistatus/>"))
+}
+
+// notFound handles requests to paths no route matches
+func notFound(c echo.Context) error {
+	return c.JSON(http.StatusNotFound, ErrorResponse{Error: "not_found", Code: http.StatusNotFound})
+}
+
+// customHTTPErrorHandler writes errors returned by handlers as an ErrorResponse
+func customHTTPErrorHandler(err error, c echo.Context) {
+	code := http.StatusInternalServerError
+	if he, ok := err.(*echo.HTTPError); ok {
+		code = he.Code
+	}
+	c.JSON(code, ErrorResponse{Error: http.StatusText(code), Message: err.Error(), Code: code})
+}
+
+// registerOrderRoutes registers the order routes on the given router
+func registerOrderRoutes(r *echo.Echo) {
+	r.GET("/orders", getOrders)
+	r.GET("/orders/:id", getOrderByID)
+	r.POST("/orders", createOrder)
+	r.PUT("/orders/:id/status", updateOrderStatus, requireAdmin)
+	r.GET("/users/:userId/orders/:id", getUserOrder)
+	r.GET("/orders/:id/invoice", getOrderInvoice)
+	r.GET("/orders/events", streamOrderEvents)
+	r.GET("/orders/feed", orderFeed)
+	r.GET("/orders/ws", orderUpdatesSocket)
+}
+
+// registerAdminRoutes registers the admin routes on a versioned admin group
+func registerAdminRoutes(g *echo.Group) {
+	accounts := g.Group("/accounts")
+	accounts.GET("", getUsers)
+	accounts.DELETE("/:id", deleteUser)
+
+	reports := newReportsGroup(g)
+	reports.GET("/orders", getOrders)
+}
+
+// newReportsGroup creates the group of the compressed admin reports
+func newReportsGroup(g *echo.Group) *echo.Group {
+	reports := g.Group("/reports")
+	reports.Use(middleware.Gzip())
+	return reports
+}
+
+// register registers a route with its middleware
+func register(e *echo.Echo, method, path string, h echo.HandlerFunc, mws ...echo.MiddlewareFunc) {
+	e.Add(method, path, h, mws...)
+}
+
+// getVersion returns the version of the API
+func getVersion(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"version": "1.0.0"})
+}
+
+// validateAPIKey accepts the API key of internal clients
+func validateAPIKey(key string, c echo.Context) (bool, error) {
+	return key == "internal-key", nil
+}
+
+// Handler functions
+func helloWorld(c echo.Context) error {
+	return c.String(http.StatusOK, "Hello, World!")
+}
+
+func getUsers(c echo.Context) error {
+	// Query parameters
+	limit := c.QueryParam("limit")
+	if limit == "" {
+		limit = "20"
+	}
+	offset := c.QueryParam("offset")
+	if _, err := strconv.Atoi(limit); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+	}
+
+	// Mock data
+	users := []User{
+		{
+			ID:        1,
+			Name:      "John Doe",
+			Email:     "john@example.com",
+			CreatedAt: time.Now(),
+			Profile: &Profile{
+				Bio:    "Software Engineer",
+				Skills: []string{"Go", "JavaScript", "Docker"},
+			},
+		},
+		{
+			ID:        2,
+			Name:      "Jane Smith",
+			Email:     "jane@example.com",
+			CreatedAt: time.Now(),
+		},
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+func getFeaturedUsers(c echo.Context) error {
+	// Inline slice literals of named and pointer element types
+	if c.QueryParam("pointers") != "" {
+		return c.JSON(http.StatusOK, []*User{
+			{ID: 1, Name: "John Doe"},
+		})
+	}
+
+	return c.JSON(http.StatusOK, []User{
+		{ID: 1, Name: "John Doe"},
+		{ID: 2, Name: "Jane Smith"},
+	})
+}
+
+func searchUsers(c echo.Context) error {
+	users := []User{
+		{ID: 1, Name: "John Doe"},
+	}
+
+	// Response built through a builder chain
+	resp := NewUserListBuilder().WithData(users).Build()
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// responsePool recycles user list responses
+var responsePool = sync.Pool{
+	New: func() interface{} { return new(UserListResponse) },
+}
+
+func getCachedUsers(c echo.Context) error {
+	// Value read from an interface-typed store
+	resp := responsePool.Get()
+	defer responsePool.Put(resp)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func getUserByID(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+	uid, err := strconv.Atoi(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+
+	// Mock data
+	user := User{
+		ID:        uid,
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		CreatedAt: time.Now(),
+		Profile: &Profile{
+			Bio:    "Software Engineer",
+			Skills: []string{"Go", "JavaScript", "Docker"},
+		},
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func getCurrentUser(c echo.Context) error {
+	// The same name holds a different type in each branch
+	if c.Request().Header.Get("Authorization") == "" {
+		resp := ErrorResponse{Error: "unauthorized", Code: 401}
+		return c.JSON(http.StatusUnauthorized, resp)
+	} else {
+		resp := User{ID: 1, Name: "John Doe"}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+func getUserProfile(c echo.Context) error {
+	// Result of a method call on an interface
+	store := NewUserStore()
+	user, err := store.FindUser(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error(), Code: 404})
+	}
+
+	return c.JSON(http.StatusOK, user.Profile)
+}
+
+func getUserPretty(c echo.Context) error {
+	// Indented JSON response; the indent argument isn't part of the body
+	user := User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	return c.JSONPretty(http.StatusOK, user, "  ")
+}
+
+func exportUser(c echo.Context) error {
+	// Pre-encoded JSON response, typed from the value passed to json.Marshal
+	user := &User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	data, err := json.Marshal(user)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error(), Code: 500})
+	}
+	return c.JSONBlob(http.StatusOK, data)
+}
+
+func uploadAvatar(c echo.Context) error {
+	id := c.Param("id")
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Code: 400, Message: "Missing avatar"})
+	}
+	caption := c.FormValue("caption")
+	return c.String(http.StatusOK, id+": "+file.Filename+" "+caption)
+}
+
+func login(c echo.Context) error {
+	username := c.FormValue("username")
+	password := c.FormValue("password")
+	if username == "" || password == "" {
+		status := http.StatusUnauthorized
+		if username == "" {
+			status = http.StatusBadRequest
+		}
+		return c.JSON(status, ErrorResponse{Error: "invalid_credentials", Code: status})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getNewestUser(c echo.Context) error {
+	users := []User{
+		{ID: 1, Name: "John Doe"},
+		{ID: 2, Name: "Jane Smith"},
+	}
+
+	// Element of a slice
+	return c.JSON(http.StatusOK, users[len(users)-1])
+}
+
+func lookupUser(c echo.Context) error {
+	cache := map[string]*User{
+		"1": {ID: 1, Name: "John Doe"},
+	}
+
+	// Value of a map
+	return c.JSON(http.StatusOK, cache[c.QueryParam("id")])
+}
+
+func createUser(c echo.Context) error {
+	// Bind request body
+	user := new(User)
+	if err := c.Bind(user); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "InvalidRequest",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	// Mock response
+	user.ID = 123
+	user.CreatedAt = time.Now()
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+func updateUser(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	// Bind request body
+	user := new(User)
+	if err := c.Bind(user); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "InvalidRequest",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	// Set ID from path
+	user.ID = 1
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func deleteUser(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getProducts(c echo.Context) error {
+	// Query parameters
+	category := c.QueryParam("category")
+
+	// Mock data
+	products := []Product{
+		{
+			ID:          1,
+			Name:        "Product 1",
+			Description: "This is product 1",
+			Price:       19.99,
+			Categories:  []string{"Electronics", "Gadgets"},
+			Attributes: map[string]string{
+				"color": "black",
+				"size":  "medium",
+			},
+			Inventory: &ProductInventory{
+				Quantity:  100,
+				Available: true,
+			},
+		},
+		{
+			ID:          2,
+			Name:        "Product 2",
+			Description: "This is product 2",
+			Price:       29.99,
+			Categories:  []string{"Home", "Kitchen"},
+		},
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func getProductPage(c echo.Context) error {
+	// Mock data
+	products := []Product{
+		{
+			ID:         1,
+			Name:       "Product 1",
+			Price:      19.99,
+			Categories: []string{"Electronics"},
+		},
+	}
+
+	return c.JSON(http.StatusOK, Page[Product]{
+		Items: products,
+		Total: len(products),
+	})
+}
+
+func getProductCatalog(c echo.Context) error {
+	// XML or JSON response depending on the Accept header, with element
+	// names from xml tags
+	entry := CatalogEntry{SKU: "SKU-1001", Name: "Product 1", Price: 19.99}
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON) {
+		return c.JSON(http.StatusOK, entry)
+	}
+	return c.XMLPretty(http.StatusOK, entry, "  ")
+}
+
+func getEnvelopedProducts(c echo.Context) error {
+	products := []Product{
+		{ID: 1, Name: "Laptop", Price: 999.99},
+	}
+	return c.JSON(http.StatusOK, Envelope{
+		Data: products,
+		Meta: EnvelopeMeta{Page: 1, Total: len(products)},
+	})
+}
+
+func getProductIndex(c echo.Context) error {
+	// Products by SKU; discontinued SKUs map to nil
+	index := map[string]*Product{
+		"LAP-001": {ID: 1, Name: "Laptop", Price: 999.99},
+		"OLD-001": nil,
+	}
+	return c.JSON(http.StatusOK, index)
+}
+
+func getProductByID(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	// Mock data
+	product := Product{
+		ID:          1,
+		Name:        "Product 1",
+		Description: "This is product 1",
+		Price:       19.99,
+		Categories:  []string{"Electronics", "Gadgets"},
+		Attributes: map[string]string{
+			"color": "black",
+			"size":  "medium",
+		},
+		Inventory: &ProductInventory{
+			Quantity:  100,
+			Available: true,
+		},
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+func createProduct(c echo.Context) error {
+	// Bind request body
+	product := new(Product)
+	if err := c.Bind(product); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "InvalidRequest",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+	if err := c.Validate(product); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "ValidationFailed",
+			Message: err.Error(),
+			Code:    422,
+		})
+	}
+
+	// Mock response
+	product.ID = 123
+
+	// Send SNS notification
+	sendProductCreatedEvent(product)
+
+	return c.JSON(http.StatusCreated, product)
+}
+
+func updateProduct(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	// Bind request body
+	product := new(Product)
+	if err := c.Bind(product); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "InvalidRequest",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	// Set ID from path
+	product.ID = 1
+
+	return c.JSON(http.StatusOK, product)
+}
+
+func searchProducts(c echo.Context) error {
+	// Filter by the fields set in the body; without one, every product matches
+	filter := new(Product)
+	c.Bind(filter)
+
+	return c.JSON(http.StatusOK, []Product{})
+}
+
+// restockProduct sets the inventory of a product, or marks it available
+// again when no body is sent
+// @body-optional
+func restockProduct(c echo.Context) error {
+	inventory := ProductInventory{Available: true}
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&inventory); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return c.JSON(http.StatusOK, inventory)
+}
+
+func createReview(c echo.Context) error {
+	// Path, query, header and body fields bound at once
+	var req ReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, req)
+}
+
+// defaultQuery reads a query parameter, falling back to a default value
+func defaultQuery(c echo.Context, name, value string) string {
+	if param := c.QueryParam(name); param != "" {
+		return param
+	}
+	return value
+}
+
+func getOrders(c echo.Context) error {
+	// Query parameters
+	status := c.QueryParam("status")
+	sort := defaultQuery(c, "sort", "created_at")
+
+	// Header and cookie
+	apiKey := c.Request().Header.Get("X-Api-Key")
+	session, _ := c.Cookie("session")
+
+	// Mock data
+	orders := []Order{
+		{
+			ID:     1,
+			UserID: 1,
+			Items: []OrderItem{
+				{
+					ProductID: 1,
+					Quantity:  2,
+					Price:     19.99,
+				},
+				{
+					ProductID: 2,
+					Quantity:  1,
+					Price:     29.99,
+				},
+			},
+			TotalPrice: 69.97,
+			Status:     "pending",
+			CreatedAt:  time.Now(),
+			ShippingAddress: Address{
+				Street:  "123 Main St",
+				City:    "Anytown",
+				State:   "CA",
+				ZipCode: "12345",
+				Country: "USA",
+			},
+		},
+		{
+			ID:     2,
+			UserID: 2,
+			Items: []OrderItem{
+				{
+					ProductID: 3,
+					Quantity:  1,
+					Price:     49.99,
+				},
+			},
+			TotalPrice: 49.99,
+			Status:     "shipped",
+			CreatedAt:  time.Now(),
+			ShippingAddress: Address{
+				Street:  "456 Oak Ave",
+				City:    "Somewhere",
+				State:   "NY",
+				ZipCode: "67890",
+				Country: "USA",
+			},
+		},
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func getOrderByID(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	// Bound the time spent loading the order
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+	_ = ctx
+
+	// Mock data
+	order := Order{
+		ID:     1,
+		UserID: 1,
+		Items: []OrderItem{
+			{
+				ProductID: 1,
+				Quantity:  2,
+				Price:     19.99,
+			},
+			{
+				ProductID: 2,
+				Quantity:  1,
+				Price:     29.99,
+			},
+		},
+		TotalPrice: 69.97,
+		Status:     "pending",
+		CreatedAt:  time.Now(),
+		ShippingAddress: Address{
+			Street:  "123 Main St",
+			City:    "Anytown",
+			State:   "CA",
+			ZipCode: "12345",
+			Country: "USA",
+		},
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+func createOrder(c echo.Context) error {
+	// Bind request body
+	order := new(Order)
+	if err := c.Bind(order); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "InvalidRequest",
+			Message: "Invalid request body",
+			Code:    400,
+		})
+	}
+
+	// Mock response
+	if len(order.Items) == 0 {
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "EmptyOrder",
+			Message: "Order has no items",
+			Code:    409,
+		})
+	}
+
+	order.ID = 123
+	order.CreatedAt = time.Now()
+	order.Status = "pending"
+
+	// Calculate total price
+	var totalPrice float64
+	for _, item := range order.Items {
+		totalPrice += item.Price * float64(item.Quantity)
+	}
+	order.TotalPrice = totalPrice
+
+	// Send SNS notification
+	sendOrderCreatedEvent(order)
+	queueOrderCreatedEvent(order)
+
+	return c.JSON(orderCreated, order)
+}
+
+// orderCreated is the status code of a successfully created order
+const orderCreated = http.StatusCreated
+
+func getOrderInvoice(c echo.Context) error {
+	// Binary response with an explicit content type
+	invoice := []byte("%PDF-1.4")
+	return c.Blob(http.StatusOK, "application/pdf", invoice)
+}
+
+func streamOrderEvents(c echo.Context) error {
+	// Streamed response
+	events := strings.NewReader("data: {\"status\":\"shipped\"}\n\n")
+	return c.Stream(http.StatusOK, "text/event-stream", events)
+}
+
+func orderFeed(c echo.Context) error {
+	// Server-Sent Events written and flushed by hand
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().WriteHeader(http.StatusOK)
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(c.Response(), "data: {\"sequence\":%d}\n\n", i)
+		c.Response().Flush()
+	}
+	return nil
+}
+
+var upgrader = websocket.Upgrader{}
+
+func orderUpdatesSocket(c echo.Context) error {
+	// Upgrade the connection to a WebSocket
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "upgrade failed", Code: 400})
+	}
+	defer ws.Close()
+
+	return ws.WriteJSON(map[string]string{"status": "subscribed"})
+}
+
+// requireAdmin rejects requests from users without the admin role
+func requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get("X-Role") != "admin" {
+			return echo.ErrForbidden
+		}
+		return next(c)
+	}
+}
+
+func getTerms(c echo.Context) error {
+	// Content type inferred from the file extension
+	return c.File("static/terms.html")
+}
+
+func redirectToDocs(c echo.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+}
+
+func redirectToTerms(c echo.Context) error {
+	// Status code only known at runtime
+	status := http.StatusFound
+	if c.QueryParam("permanent") == "true" {
+		status = http.StatusMovedPermanently
+	}
+	return c.Redirect(status, "/terms")
+}
+
+// getLegacyProducts lists products with their inventory inlined.
+//
+// Deprecated: use GET /products and GET /products/index instead.
+func getLegacyProducts(c echo.Context) error {
+	products := []ProductInventory{}
+	return c.JSON(http.StatusOK, products)
+}
+
+func renderWelcomePage(c echo.Context) error {
+	// Rendered with the echo.Renderer set on the Echo instance
+	user := User{ID: 1, Name: "John Doe"}
+	return c.Render(http.StatusOK, "welcome.html", user)
+}
+
+func getUserOrder(c echo.Context) error {
+	// Path parameters with mixed naming
+	userID := c.Param("userId")
+	id := c.Param("id")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"user_id":  userID,
+		"order_id": id,
+	})
+}
+
+func updateOrderStatus(c echo.Context) error {
+	// Path parameter
+	id := c.Param("id")
+
+	// Query parameter
+	status := c.QueryParam("status")
+
+	// Mock data
+	order := Order{
+		ID:     1,
+		UserID: 1,
+		Items: []OrderItem{
+			{
+				ProductID: 1,
+				Quantity:  2,
+				Price:     19.99,
+			},
+			{
+				ProductID: 2,
+				Quantity:  1,
+				Price:     29.99,
+			},
+		},
+		TotalPrice: 69.97,
+		Status:     status,
+		CreatedAt:  time.Now(),
+		ShippingAddress: Address{
+			Street:  "123 Main St",
+			City:    "Anytown",
+			State:   "CA",
+			ZipCode: "12345",
+			Country: "USA",
+		},
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// AWS SNS/SQS example
+func sendProductCreatedEvent(product *Product) {
+	// Create SNS client
+	snsClient := sns.New(session.New())
+
+	// Create message
+	message, _ := json.Marshal(map[string]interface{}{
+		"event":   "product_created",
+		"product": product,
+	})
+
+	// Publish to SNS topic
+	_, err := snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:product-events"),
+		Message:  aws.String(string(message)),
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"event_type": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("product_created"),
+			},
+		},
+	})
+
+	if err != nil {
+		fmt.Println("Error publishing to SNS:", err)
+	}
+}
+
+// Send order created event
+func sendOrderCreatedEvent(order *Order) {
+	// Create SNS client
+	snsClient := sns.New(session.New())
+
+	// Create message
+	message, _ := json.Marshal(OrderEvent{
+		Event: "order_created",
+		Order: order,
+	})
+
+	// Publish to SNS topic
+	_, err := snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:order-events"),
+		Message:  aws.String(string(message)),
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"event_type": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("order_created"),
+			},
+		},
+	})
+
+	if err != nil {
+		fmt.Println("Error publishing to SNS:", err)
+	}
+}
+
+// OrderEvent is the message sent when an order changes
+type OrderEvent struct {
+	Event string `json:"event"`
+	Order *Order `json:"order"`
+}
+
+// Queue order created event for fulfillment
+func queueOrderCreatedEvent(order *Order) {
+	sqsClient := sqs.New(session.New())
+
+	body, _ := json.Marshal(OrderEvent{Event: "order_created", Order: order})
+	_, err := sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/fulfillment-queue"),
+		MessageBody: aws.String(string(body)),
+	})
+
+	if err != nil {
+		fmt.Println("Error sending message to SQS:", err)
+	}
+}
+
+// Send message to SQS
+func sendToQueue(message string) {
+	// Create SQS client
+	sqsClient := sqs.New(session.New())
+
+	// Send message to SQS queue
+	_, err := sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/product-queue"),
+		MessageBody: aws.String(message),
+		MessageAttributes: map[string]*sqs.MessageAttributeValue{
+			"source": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("product-service"),
+			},
+		},
+	})
+
+	if err != nil {
+		fmt.Println("Error sending to SQS:", err)
+	}
+}
+
+// Send a batch of order messages to SQS
+func sendOrderBatch() {
+	sqsClient := sqs.New(session.New())
+
+	// Two entries share a message format, the third carries an attribute
+	_, err := sqsClient.SendMessageBatch(&sqs.SendMessageBatchInput{
+		QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/order-queue"),
+		Entries: []*sqs.SendMessageBatchRequestEntry{
+			{Id: aws.String("1"), MessageBody: aws.String(`{"order_id": 1}`)},
+			{Id: aws.String("2"), MessageBody: aws.String(`{"order_id": 2}`)},
+			{
+				Id:          aws.String("3"),
+				MessageBody: aws.String(`{"order_id": 3}`),
+				MessageAttributes: map[string]*sqs.MessageAttributeValue{
+					"priority": {
+						DataType:    aws.String("Number"),
+						StringValue: aws.String("1"),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Println("Error sending batch to SQS:", err)
+	}
+
+	// Publish the same batch to SNS
+	snsClient := sns.New(session.New())
+	_, err = snsClient.PublishBatch(&sns.PublishBatchInput{
+		TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:order-events"),
+		PublishBatchRequestEntries: []*sns.PublishBatchRequestEntry{
+			{Id: aws.String("1"), Message: aws.String(`{"order_id": 1}`)},
+			{Id: aws.String("2"), Message: aws.String(`{"order_id": 2}`)},
+		},
+	})
+	if err != nil {
+		fmt.Println("Error publishing batch to SNS:", err)
+	}
+}
+
+// Persist an order to DynamoDB
+func saveOrder(order *Order) {
+	// Create DynamoDB client
+	dynamoClient := dynamodb.New(session.New())
+
+	_, err := dynamoClient.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String("orders"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":     {N: aws.String("1")},
+			"status": {S: aws.String(order.Status)},
+		},
+	})
+
+	if err != nil {
+		fmt.Println("Error writing to DynamoDB:", err)
+	}
+}
+
+// Upload an invoice to S3
+func uploadInvoice(body []byte) {
+	// Create S3 client
+	s3Client := s3.New(session.New())
+
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String("invoices"),
+		Key:    aws.String("orders/invoice.pdf"),
+	})
+
+	if err != nil {
+		fmt.Println("Error uploading to S3:", err)
+	}
+}
+
+// Publish an order shipped event to EventBridge
+func publishOrderShipped(order *Order) {
+	// Create EventBridge client
+	eventsClient := eventbridge.New(session.New())
+
+	_, err := eventsClient.PutEvents(&eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String("orders-bus"),
+				Source:       aws.String("order-service"),
+				DetailType:   aws.String("OrderShipped"),
+				Detail:       aws.String(`{"status":"shipped"}`),
+			},
+		},
+	})
+
+	if err != nil {
+		fmt.Println("Error publishing to EventBridge:", err)
+	}
+}
+
+func pollProductQueue() {
+	sqsClient := sqs.New(session.New())
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/product-queue"
+
+	// Consume messages from the SQS queue
+	output, err := sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		MaxNumberOfMessages: aws.Int64(10),
+	})
+	if err != nil {
+		fmt.Println("Error receiving from SQS:", err)
+		return
+	}
+
+	for _, message := range output.Messages {
+		sqsClient.DeleteMessage(&sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(queueURL),
+			ReceiptHandle: message.ReceiptHandle,
+		})
+	}
+}
+
+// handleOrderNotifications is a Lambda handler subscribed to the order topic
+func handleOrderNotifications(ctx context.Context, event events.SNSEvent) error {
+	for _, record := range event.Records {
+		fmt.Println("Order notification:", record.SNS.Message)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Sample net/http application routing with Go 1.22 ServeMux patterns, analyzed
+// with --framework nethttp
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+type server struct {
+	mux *http.ServeMux
+}
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", getUser)
+	mux.HandleFunc("POST /users", createUser)
+	mux.HandleFunc("DELETE /users/{id}", deleteUser)
+	mux.Handle("GET /files/{path...}", http.HandlerFunc(getFile))
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "home")
+	})
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("public"))))
+	http.HandleFunc("GET /health", health)
+
+	s := &server{mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /search", search)
+	http.ListenAndServe(":8080", mux)
+}
+
+func getUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Message: "invalid id"})
+		return
+	}
+	if r.Header.Get("X-Fail") != "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(User{ID: id})
+}
+
+func createUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user := User{ID: 1, Name: req.Name}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	enc := json.NewEncoder(w)
+	enc.Encode(user)
+}
+
+func deleteUser(w http.ResponseWriter, r *http.Request) {
+	_ = r.PathValue("id")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func getFile(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, r.PathValue("path"))
+}
+
+func health(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+func search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	users := []User{{Name: q}}
+	json.NewEncoder(w).Encode(users)
+}